@@ -2,3 +2,7 @@ package arch
 
 // AddrBootArg is the address to write the boot argument
 const AddrBootArg = pageBasicIO*PageSize + bootArgBase
+
+// AddrImageEnd is the address where the end of the loaded boot image is
+// saved. The memory after the image is free for the program to use.
+const AddrImageEnd = pageSysInfo*PageSize + 8
@@ -0,0 +1,171 @@
+package arch
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// busNode is a component slot on a bus.
+type busNode struct {
+	id    uint8
+	comp  Component
+	in    *MsgQueue
+	state int
+}
+
+func (n *busNode) runnable() bool {
+	switch n.state {
+	case StateContinue:
+		return true
+	case StateSleep:
+		return n.in.Len() > 0
+	}
+	return false
+}
+
+// Bus links a set of components together. It schedules the components
+// in rounds, and delivers messages among them by their ids.
+type Bus struct {
+	nodes []*busNode
+	ids   map[uint8]*busNode
+}
+
+// NewBus creates a new empty bus.
+func NewBus() *Bus {
+	return &Bus{
+		ids: make(map[uint8]*busNode),
+	}
+}
+
+// busSender sends messages on behalf of a component on a bus.
+type busSender struct {
+	bus *Bus
+	id  uint8
+}
+
+// Send sends a copy of the message onto the bus, with the sender's id
+// as From.
+func (s *busSender) Send(m *Message) error {
+	cp := *m
+	cp.From = s.id
+	return s.bus.Send(&cp)
+}
+
+// Add links a component onto the bus with the given id. It returns
+// a sender for the component to send messages to other components.
+func (b *Bus) Add(id uint8, c Component) (MsgSender, error) {
+	if _, found := b.ids[id]; found {
+		return nil, fmt.Errorf("component %d already on bus", id)
+	}
+
+	n := &busNode{
+		id:    id,
+		comp:  c,
+		in:    NewMsgQueue(),
+		state: StateContinue,
+	}
+	b.nodes = append(b.nodes, n)
+	b.ids[id] = n
+	return &busSender{bus: b, id: id}, nil
+}
+
+// Send delivers a message to the component of id m.To. A sleeping
+// component will be woken up on the next round.
+func (b *Bus) Send(m *Message) error {
+	n, found := b.ids[m.To]
+	if !found {
+		return fmt.Errorf("component %d not found", m.To)
+	}
+	if n.state == StateHalt {
+		return fmt.Errorf("component %d halted", m.To)
+	}
+	n.in.Push(m)
+	return nil
+}
+
+// Tick runs one round on the bus, where each runnable component runs
+// once in the order they are added. It returns the number of
+// components that ran.
+func (b *Bus) Tick() int {
+	ret := 0
+	for _, n := range b.nodes {
+		if !n.runnable() {
+			continue
+		}
+
+		state := n.comp.Run(n.in)
+		switch state {
+		case StateContinue, StateHalt, StateSleep:
+		default:
+			panic(fmt.Sprintf("invalid component state: %d", state))
+		}
+		n.state = state
+		ret++
+	}
+	return ret
+}
+
+// Run runs the bus for maximum n rounds, or until no component is
+// runnable, which happens when all components are halted or sleeping
+// without pending messages. When n is 0, it runs until idle. It returns
+// the number of rounds that ran.
+func (b *Bus) Run(n int) int {
+	ret := 0
+	for i := 0; n == 0 || i < n; i++ {
+		if b.Tick() == 0 {
+			break
+		}
+		ret++
+	}
+	return ret
+}
+
+// Idle checks if no component on the bus is runnable.
+func (b *Bus) Idle() bool {
+	for _, n := range b.nodes {
+		if n.runnable() {
+			return false
+		}
+	}
+	return true
+}
+
+// CompState returns the last returned running state of a component.
+func (b *Bus) CompState(id uint8) (int, bool) {
+	n, found := b.ids[id]
+	if !found {
+		return 0, false
+	}
+	return n.state, true
+}
+
+// busStateEntry is the inspection entry of a component.
+type busStateEntry struct {
+	ID      uint8
+	State   int
+	Pending int
+	Comp    interface{}
+}
+
+type byID []*busStateEntry
+
+func (s byID) Len() int           { return len(s) }
+func (s byID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+
+// MarshalState marshals the states of the components into JSON, with
+// their ids, running states and count of pending messages.
+func (b *Bus) MarshalState() ([]byte, error) {
+	var entries []*busStateEntry
+	for _, n := range b.nodes {
+		entries = append(entries, &busStateEntry{
+			ID:      n.id,
+			State:   n.state,
+			Pending: n.in.Len(),
+			Comp:    n.comp.State(),
+		})
+	}
+	sort.Sort(byID(entries))
+	return json.Marshal(entries)
+}
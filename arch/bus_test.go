@@ -0,0 +1,112 @@
+package arch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// pinger sends a counter to a peer, and sleeps until it gets a reply.
+type pinger struct {
+	out   MsgSender
+	peer  uint8
+	count int
+	max   int
+}
+
+func (p *pinger) Run(q *MsgQueue) int {
+	for q.Len() > 0 {
+		q.Pull()
+		p.count++
+	}
+	if p.count >= p.max {
+		return StateHalt
+	}
+	if p.out != nil {
+		err := p.out.Send(&Message{To: p.peer, Payload: []byte{1}})
+		if err != nil {
+			return StateHalt
+		}
+	}
+	return StateSleep
+}
+
+func (p *pinger) State() interface{} {
+	return map[string]int{"count": p.count}
+}
+
+// echoer replies every message back to its sender.
+type echoer struct {
+	out  MsgSender
+	echo int
+}
+
+func (e *echoer) Run(q *MsgQueue) int {
+	for q.Len() > 0 {
+		m, err := q.Receive()
+		if err != nil {
+			return StateHalt
+		}
+		e.echo++
+		e.out.Send(&Message{To: m.From, Payload: m.Payload})
+	}
+	return StateSleep
+}
+
+func (e *echoer) State() interface{} { return e.echo }
+
+func TestBus(t *testing.T) {
+	as := func(cond bool, s string, args ...interface{}) {
+		if !cond {
+			t.Fatalf(s, args...)
+		}
+	}
+
+	b := NewBus()
+	p := &pinger{peer: 2, max: 3}
+	e := new(echoer)
+
+	var err error
+	p.out, err = b.Add(1, p)
+	as(err == nil, "add pinger: %s", err)
+	e.out, err = b.Add(2, e)
+	as(err == nil, "add echoer: %s", err)
+	_, err = b.Add(2, e)
+	as(err != nil, "duplicate id should fail")
+
+	n := b.Run(100)
+	as(n < 100, "bus not idle after %d rounds", n)
+	as(b.Idle(), "bus not idle")
+	as(p.count == 3, "pinger got %d replies", p.count)
+	as(e.echo == 3, "echoer got %d messages", e.echo)
+
+	state, found := b.CompState(1)
+	as(found && state == StateHalt, "pinger not halted")
+	state, found = b.CompState(2)
+	as(found && state == StateSleep, "echoer not sleeping")
+	_, found = b.CompState(3)
+	as(!found, "component 3 should not exist")
+
+	err = b.Send(&Message{From: 2, To: 1})
+	as(err != nil, "sending to halted component should fail")
+	err = b.Send(&Message{From: 1, To: 5})
+	as(err != nil, "sending to missing component should fail")
+
+	err = b.Send(&Message{From: 1, To: 2})
+	as(err == nil, "wake up echoer: %s", err)
+	as(!b.Idle(), "echoer should be woken up")
+	as(b.Tick() == 1, "only echoer should run")
+	as(e.echo == 4, "echoer got %d messages", e.echo)
+
+	bs, err := b.MarshalState()
+	as(err == nil, "marshal state: %s", err)
+	var entries []*busStateEntry
+	as(json.Unmarshal(bs, &entries) == nil, "unmarshal state")
+	as(len(entries) == 2, "got %d entries", len(entries))
+	as(entries[0].ID == 1 && entries[0].State == StateHalt, "bad entry 1")
+	as(entries[1].ID == 2 && entries[1].State == StateSleep, "bad entry 2")
+	as(entries[1].Comp == 4.0, "echoer state is %v", entries[1].Comp)
+
+	m := &Message{To: 2}
+	as(p.out.Send(m) == nil, "send from pinger")
+	as(m.From == 0, "sender changed the message")
+}
@@ -11,8 +11,8 @@ import (
 // Console is a simple console that can output/input a single
 // byte at a time
 type console struct {
+	MMIORegs
 	intBus intBus
-	p      *pageOffset
 
 	Core      byte // Core to throw exception
 	Interrupt byte
@@ -20,11 +20,21 @@ type console struct {
 	Output io.Writer
 }
 
+const (
+	consoleOut      = 0
+	consoleOutValid = 1
+
+	consoleIn      = 4
+	consoleInValid = 5
+
+	consoleRegsSize = 8
+)
+
 // NewConsole creates a new simple console.
-func newConsole(p *page, i intBus) *console {
+func newConsole(i intBus) *console {
 	ret := new(console)
+	ret.MMIORegs = NewMMIORegs(consoleRegsSize)
 	ret.intBus = i
-	ret.p = &pageOffset{p, consoleBase}
 
 	ret.Core = 0
 	ret.Interrupt = 9
@@ -33,14 +43,6 @@ func newConsole(p *page, i intBus) *console {
 	return ret
 }
 
-const (
-	consoleOut      = 0
-	consoleOutValid = 1
-
-	consoleIn      = 4
-	consoleInValid = 5
-)
-
 func (c *console) interrupt(code byte) {
 	c.intBus.Interrupt(code, c.Core)
 }
@@ -62,18 +64,32 @@ func (c *console) Handle(req []byte) ([]byte, int32) {
 	return nil, 0
 }
 
-// Tick flushes the buffered byte to the console.
-func (c *console) Tick() {
-	outValid := c.p.readU8(consoleOutValid)
+// WriteU8 writes a console register, and flushes the output byte
+// when it becomes valid.
+func (c *console) WriteU8(offset uint32, b byte) {
+	c.MMIORegs.WriteU8(offset, b)
+	c.flush()
+}
+
+// WriteU32 writes a console register word, and flushes the output byte
+// when it becomes valid.
+func (c *console) WriteU32(offset uint32, w uint32) {
+	c.MMIORegs.WriteU32(offset, w)
+	c.flush()
+}
+
+// flush flushes the buffered byte to the console.
+func (c *console) flush() {
+	outValid := c.MMIORegs.ReadU8(consoleOutValid)
 	if outValid == 0 {
 		return
 	}
 
-	out := c.p.readU8(consoleOut)
+	out := c.MMIORegs.ReadU8(consoleOut)
 	_, e := c.Output.Write([]byte{out})
 	if e != nil {
 		log.Print(e)
 	}
-	c.p.writeU8(consoleOutValid, 0)
+	c.MMIORegs.WriteU8(consoleOutValid, 0)
 	c.interrupt(c.Interrupt) // out available
 }
@@ -1,3 +1,3 @@
 package arch
 
-var _ device = new(console)
+var _ MMIODevice = new(console)
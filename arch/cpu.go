@@ -17,7 +17,7 @@ type cpu struct {
 
 	inst     inst
 	index    byte
-	ncycle   uint64
+	perf     PerfCounters
 	sleeping bool
 }
 
@@ -63,8 +63,14 @@ func (c *cpu) Reset() {
 	c.interrupt.Disable()
 }
 
+func (c *cpu) perfCounters() *PerfCounters {
+	ret := c.perf
+	ret.PageWalks = c.virtMem.nwalk
+	return &ret
+}
+
 func (c *cpu) tick() *Excep {
-	c.ncycle++
+	c.perf.Cycles++
 	pc := c.regs[PC]
 	inst, e := c.readU32(pc)
 	if e != nil {
@@ -81,6 +87,7 @@ func (c *cpu) tick() *Excep {
 		}
 	}
 
+	c.perf.Insts++
 	return nil
 }
 
@@ -147,6 +154,7 @@ func (c *cpu) Ienter(code byte, arg uint32) *Excep {
 	c.regs[RET] = c.regs[PC]
 	c.regs[PC] = c.interrupt.handlerPC()
 	c.ring = 0
+	c.perf.Interrupts++
 
 	return nil
 }
@@ -223,6 +231,9 @@ func (c *cpu) Tick() *Excep {
 	}
 
 	// proceed attempt failed, this is a fault.
+	if e.Code == ErrPageFault {
+		c.perf.PageFaults++
+	}
 	c.interrupt.Issue(e.Code)       // put the fault on to interrupt
 	poll, code = c.interrupt.Poll() // see if it is handlable
 	if poll {
@@ -42,6 +42,9 @@ const (
 	IntSerial = 16
 	IntROM    = 17
 	IntSwap   = 18
+	IntTimer  = 19
+
+	IntDevice = 32 // first interrupt code for devices
 )
 
 var (
@@ -58,6 +58,13 @@ func (i *instImm) I(cpu *cpu, in uint32) *Excep {
 		return e
 	}
 
+	switch op {
+	case LW, LB, LBU:
+		cpu.perf.Loads++
+	case SW, SB:
+		cpu.perf.Stores++
+	}
+
 	cpu.regs[dest] = d
 	return nil
 }
@@ -111,12 +111,30 @@ func (i *instReg) I(cpu *cpu, in uint32) *Excep {
 		case FDIV:
 			fd = f1 / f2
 		case FINT:
-			d = uint32(f1)
+			d = floatToInt(f1)
+		case FFLT:
+			fd = float32(int32(s1))
+		case FLT:
+			if f1 < f2 {
+				d = 1
+			}
+		case FEQ:
+			if f1 == f2 {
+				d = 1
+			}
+		case FNEG:
+			fd = -f1
+		case FABS:
+			fd = float32(math.Abs(float64(f1)))
+		case FSQRT:
+			fd = float32(math.Sqrt(float64(f1)))
 		default:
 			return errInvalidInst
 		}
 
-		if funct != 4 {
+		switch funct {
+		case FINT, FLT, FEQ: // results are integers
+		default:
 			d = math.Float32bits(fd)
 		}
 	}
@@ -124,3 +142,18 @@ func (i *instReg) I(cpu *cpu, in uint32) *Excep {
 	cpu.regs[dest] = d
 	return nil
 }
+
+// floatToInt converts a float to an integer, truncating towards zero.
+// NaN converts to 0, and the values out of the integer range saturate to
+// the largest or the smallest integer.
+func floatToInt(f float32) uint32 {
+	switch {
+	case f != f: // NaN
+		return 0
+	case f >= 1<<31:
+		return math.MaxInt32
+	case f < -(1 << 31):
+		return 1 << 31
+	}
+	return uint32(int32(f))
+}
@@ -78,6 +78,29 @@ func TestInstReg(t *testing.T) {
 		}
 	}
 
+	tstfr := func(op, s1, s2, d, v1, v2, res uint32) {
+		cpu.Reset()
+
+		in := op & 0xff
+		in |= (s1 & 0x7) << 18
+		in |= (s2 & 0x7) << 15
+		in |= (d & 0x7) << 21
+		in |= 0x1 << 8
+		m.WriteU32(InitPC, in)
+
+		cpu.regs[s1] = v1
+		cpu.regs[s2] = v2
+		e := cpu.Tick()
+		if e != nil {
+			t.Fatal("unexpected exception")
+		}
+
+		got := cpu.regs[d]
+		if got != res {
+			t.Fatalf("got 0x%08x, expect 0x%08x", got, res)
+		}
+	}
+
 	tf := func(op uint32, f func(a, b uint32) uint32) {
 		for i := 0; i < 100; i++ {
 			s1 := uint32(rand.Intn(5))
@@ -206,4 +229,66 @@ func TestInstReg(t *testing.T) {
 	tff(FSUB, func(a, b float32) float32 { return a - b })
 	tff(FMUL, func(a, b float32) float32 { return a * b })
 	tff(FDIV, func(a, b float32) float32 { return a / b })
+
+	tffr := func(op uint32, f func(a, b uint32) uint32) {
+		for i := 0; i < 100; i++ {
+			s1 := uint32(rand.Intn(5))
+			s2 := uint32(rand.Intn(5))
+			for s2 == s1 {
+				s2 = uint32(rand.Intn(5))
+			}
+			d := uint32(rand.Intn(5))
+
+			v1 := uint32(rand.Int63())
+			v2 := uint32(rand.Int63())
+			if i%10 == 0 {
+				v2 = v1
+			}
+
+			exp := f(v1, v2)
+			tstfr(op, s1, s2, d, v1, v2, exp)
+		}
+	}
+
+	f32 := math.Float32frombits
+	b32 := math.Float32bits
+	tffr(FINT, func(a, b uint32) uint32 { return floatToInt(f32(a)) })
+	tffr(FFLT, func(a, b uint32) uint32 { return b32(float32(int32(a))) })
+	tffr(FLT, func(a, b uint32) uint32 {
+		if f32(a) < f32(b) {
+			return 1
+		}
+		return 0
+	})
+	tffr(FEQ, func(a, b uint32) uint32 {
+		if f32(a) == f32(b) {
+			return 1
+		}
+		return 0
+	})
+	tffr(FNEG, func(a, b uint32) uint32 { return b32(-f32(a)) })
+	tffr(FABS, func(a, b uint32) uint32 {
+		return b32(float32(math.Abs(float64(f32(a)))))
+	})
+	tffr(FSQRT, func(a, b uint32) uint32 {
+		return b32(float32(math.Sqrt(float64(f32(a)))))
+	})
+
+	tstfr(FINT, 0, 1, 2, b32(-3.7), 0, uint32(0xfffffffd))
+	tstfr(FINT, 0, 1, 2, b32(2147483520), 0, 2147483520)
+	tstfr(FINT, 0, 1, 2, b32(1<<31), 0, 0x7fffffff)
+	tstfr(FINT, 0, 1, 2, b32(1e20), 0, 0x7fffffff)
+	tstfr(FINT, 0, 1, 2, b32(-(1 << 31)), 0, 0x80000000)
+	tstfr(FINT, 0, 1, 2, b32(-1e20), 0, 0x80000000)
+	inf := float32(math.Inf(1))
+	tstfr(FINT, 0, 1, 2, b32(inf), 0, 0x7fffffff)
+	tstfr(FINT, 0, 1, 2, b32(-inf), 0, 0x80000000)
+	tstfr(FINT, 0, 1, 2, 0x7fc00000, 0, 0) // NaN
+	tstfr(FINT, 0, 1, 2, 0xffc00001, 0, 0) // negative NaN
+	tstfr(FFLT, 0, 1, 2, uint32(0xfffffffd), 0, b32(-3))
+	tstfr(FLT, 0, 1, 2, b32(-1), b32(2), 1)
+	tstfr(FEQ, 0, 1, 2, b32(2.5), b32(2.5), 1)
+	tstfr(FNEG, 0, 1, 2, b32(2.5), 0, b32(-2.5))
+	tstfr(FABS, 0, 1, 2, b32(-2.5), 0, b32(2.5))
+	tstfr(FSQRT, 0, 1, 2, b32(6.25), 0, b32(2.5))
 }
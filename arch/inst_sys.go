@@ -3,18 +3,6 @@ package arch
 // InstSys exectues a system instruction
 type instSys struct{}
 
-func sysInfo(cpu *cpu, cmd uint32) (uint32, uint32) {
-	switch cmd {
-	case NCYCLE:
-		ncycle := cpu.ncycle
-		return uint32(ncycle), uint32(ncycle >> 16)
-	case CPUID:
-		return uint32(cpu.index), 0
-	}
-
-	return 0, 0
-}
-
 // I executes the system instruction.
 // Returns any exception encountered.
 func (i *instSys) I(cpu *cpu, in uint32) *Excep {
@@ -48,6 +36,7 @@ func (i *instSys) I(cpu *cpu, in uint32) *Excep {
 		if cpu.calls == nil {
 			return errInvalidInst
 		}
+		cpu.perf.IOCalls++
 		return cpu.calls.invoke()
 	case IRET:
 		if cpu.UserMode() {
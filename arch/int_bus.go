@@ -13,3 +13,25 @@ func intAllCores(bus intBus, code byte) {
 		bus.Interrupt(code, i)
 	}
 }
+
+// IntLine is an interrupt line declared for a device. It issues
+// interrupts of a particular code.
+type IntLine struct {
+	bus  intBus
+	code byte
+}
+
+// Code returns the interrupt code of the line.
+func (l *IntLine) Code() byte { return l.code }
+
+// Issue issues the interrupt to a core. It has no effect if the core
+// does not exist.
+func (l *IntLine) Issue(core byte) {
+	if core >= l.bus.Ncore() {
+		return
+	}
+	l.bus.Interrupt(l.code, core)
+}
+
+// IssueAll issues the interrupt to all cores.
+func (l *IntLine) IssueAll() { intAllCores(l.bus, l.code) }
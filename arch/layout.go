@@ -18,8 +18,11 @@ const (
 	bootArgBase = 0x8   // 8-c
 	clicksBase  = 0x10  // 10-14
 	romBase     = 0x100 // 100-180
+	timerBase   = 0x200 // 200-410 with 32 cores
 )
 
+func basicIOAddr(base uint32) uint32 { return pageBasicIO*PageSize + base }
+
 const (
 	serviceConsole = 1 + iota
 	serviceScreen
@@ -21,8 +21,11 @@ type Machine struct {
 	console *console
 	rand    *devs.Rand
 	ticker  *ticker
+	timer   *timer
 	rom     *rom
 
+	ints map[byte]bool // interrupt codes declared by devices
+
 	cores *multiCore
 
 	// Sections that are loaded into the machine
@@ -54,11 +57,12 @@ func NewMachine(c *Config) *Machine {
 	m.calls = newCalls(m.phyMem.Page(pageRPC), m.phyMem, c.Net)
 	m.cores = newMultiCore(c.Ncore, m.phyMem, m.calls, m.inst)
 
-	// hook-up devices
-	p := m.phyMem.Page(pageBasicIO)
+	m.ints = make(map[byte]bool)
 
-	m.console = newConsole(p, m.cores)
+	// hook-up devices
+	m.console = newConsole(m.cores)
 	m.ticker = newTicker(m.cores)
+	m.timer = newTimer(m.cores)
 
 	m.calls.register(serviceConsole, m.console)
 	m.calls.register(serviceRand, makeRand(c))
@@ -71,7 +75,9 @@ func NewMachine(c *Config) *Machine {
 	m.calls.register(serviceClock, clk)
 
 	m.addDevice(m.ticker)
-	m.addDevice(m.console)
+	m.mustMapDevice(basicIOAddr(consoleBase), consoleRegsSize, m.console)
+	timerSize := timerRegsSize(m.cores.Ncore())
+	m.mustMapDevice(basicIOAddr(timerBase), timerSize, m.timer)
 
 	sys := m.phyMem.Page(pageSysInfo)
 	sys.WriteU32(0, m.phyMem.npage)
@@ -98,9 +104,8 @@ func NewMachine(c *Config) *Machine {
 }
 
 func (m *Machine) mountROM(root string) {
-	p := m.phyMem.Page(pageBasicIO)
-	m.rom = newROM(p, m.phyMem, m.cores, root)
-	m.addDevice(m.rom)
+	m.rom = newROM(m.phyMem, m.cores, root)
+	m.mustMapDevice(basicIOAddr(romBase), romRegsSize, m.rom)
 }
 
 // ReadWord reads a word from the virtual address space.
@@ -113,8 +118,47 @@ func (m *Machine) DumpRegs(core byte) []uint32 {
 	return m.cores.dumpRegs(core)
 }
 
+// PerfCounters returns a snapshot of the performance counters of a core.
+func (m *Machine) PerfCounters(core byte) *PerfCounters {
+	return m.cores.perfCounters(core)
+}
+
 func (m *Machine) addDevice(d device) { m.devices = append(m.devices, d) }
 
+// MapDevice maps a device onto physical memory range [addr, addr+size).
+// The range must be 4-byte aligned, and must not overlap with other
+// devices. If the device has a Tick() method, it will also be ticked on
+// every machine cycle.
+func (m *Machine) MapDevice(addr, size uint32, d MMIODevice) error {
+	if err := m.phyMem.mapDevice(addr, size, d); err != nil {
+		return err
+	}
+	if t, ok := d.(device); ok {
+		m.addDevice(t)
+	}
+	return nil
+}
+
+func (m *Machine) mustMapDevice(addr, size uint32, d MMIODevice) {
+	if err := m.MapDevice(addr, size, d); err != nil {
+		panic(err)
+	}
+}
+
+// DeclareInt declares an interrupt code for a device, and returns the
+// interrupt line for issuing it. The code must be IntDevice or larger,
+// and can only be declared once.
+func (m *Machine) DeclareInt(code byte) (*IntLine, error) {
+	if code < IntDevice {
+		return nil, fmt.Errorf("interrupt code %d is reserved", code)
+	}
+	if m.ints[code] {
+		return nil, fmt.Errorf("interrupt code %d already declared", code)
+	}
+	m.ints[code] = true
+	return &IntLine{bus: m.cores, code: code}, nil
+}
+
 // Tick proceeds the simulation by one tick.
 func (m *Machine) Tick() *CoreExcep {
 	for _, d := range m.devices {
@@ -170,6 +214,7 @@ func (m *Machine) LoadSections(secs []*image.Section) error {
 	if pc, found := image.CodeStart(secs); found {
 		m.cores.setPC(pc)
 	}
+	m.phyMem.WriteU32(AddrImageEnd, image.End(secs)) // ignoring write error
 	m.sections = secs
 
 	return nil
@@ -0,0 +1,110 @@
+package arch
+
+import (
+	"fmt"
+)
+
+// MMIODevice is a memory-mapped IO device. A device is mapped onto a
+// range of the physical memory, and memory accesses inside the range are
+// forwarded to the device, with offsets relative to the start of the
+// range. Word accesses are always 4-byte aligned.
+//
+// If the device also has a Tick() method, it will be ticked on every
+// machine cycle.
+type MMIODevice interface {
+	ReadU8(offset uint32) byte
+	WriteU8(offset uint32, b byte)
+	ReadU32(offset uint32) uint32
+	WriteU32(offset uint32, w uint32)
+}
+
+// MMIORegs is a block of plain registers. It can be embedded in an
+// MMIODevice for keeping the register values, where the device only
+// overrides the accesses that have side effects.
+type MMIORegs []byte
+
+// NewMMIORegs creates a block of zeroed registers of size bytes.
+func NewMMIORegs(size uint32) MMIORegs { return make([]byte, size) }
+
+// ReadU8 reads the byte at offset.
+func (r MMIORegs) ReadU8(offset uint32) byte { return r[offset] }
+
+// WriteU8 writes the byte at offset.
+func (r MMIORegs) WriteU8(offset uint32, b byte) { r[offset] = b }
+
+// ReadU32 reads the word at offset.
+func (r MMIORegs) ReadU32(offset uint32) uint32 {
+	return Endian.Uint32(r[offset : offset+4])
+}
+
+// WriteU32 writes the word at offset.
+func (r MMIORegs) WriteU32(offset uint32, w uint32) {
+	Endian.PutUint32(r[offset:offset+4], w)
+}
+
+// mmioRange is a physical memory range that is mapped to a device.
+type mmioRange struct {
+	start uint32
+	size  uint32
+	dev   MMIODevice
+}
+
+func (r *mmioRange) contains(addr uint32) bool {
+	return addr >= r.start && addr-r.start < r.size
+}
+
+func (r *mmioRange) overlaps(other *mmioRange) bool {
+	return r.start < other.start+other.size &&
+		other.start < r.start+r.size
+}
+
+// device returns the mapped range that contains the address,
+// or nil if the address is not mapped to any device.
+func (p *page) device(addr uint32) *mmioRange {
+	for _, r := range p.mmio {
+		if r.contains(addr) {
+			return r
+		}
+	}
+	return nil
+}
+
+// mapDevice maps a device onto physical memory range [addr, addr+size).
+func (pm *phyMemory) mapDevice(addr, size uint32, d MMIODevice) error {
+	if size == 0 {
+		return fmt.Errorf("mapping device with zero size")
+	}
+	if addr%4 != 0 || size%4 != 0 {
+		return fmt.Errorf("device range %#x+%#x misaligned", addr, size)
+	}
+	end := addr + size
+	if end < addr {
+		return fmt.Errorf("device range %#x+%#x overflows", addr, size)
+	}
+
+	r := &mmioRange{start: addr, size: size, dev: d}
+	first := addr / PageSize
+	last := (end - 1) / PageSize
+	for pn := first; pn <= last; pn++ {
+		p := pm.Page(pn)
+		if p == nil {
+			return fmt.Errorf("device range %#x+%#x out of memory",
+				addr, size,
+			)
+		}
+		for _, other := range p.mmio {
+			if r.overlaps(other) {
+				return fmt.Errorf(
+					"device range %#x+%#x overlaps with %#x+%#x",
+					addr, size, other.start, other.size,
+				)
+			}
+		}
+	}
+
+	for pn := first; pn <= last; pn++ {
+		p := pm.Page(pn)
+		p.mmio = append(p.mmio, r)
+	}
+	return nil
+}
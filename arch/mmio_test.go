@@ -0,0 +1,96 @@
+package arch
+
+import (
+	"testing"
+)
+
+// testDev is a device that counts the accesses to it.
+type testDev struct {
+	MMIORegs
+	nread  int
+	nwrite int
+	nticks int
+}
+
+func (d *testDev) ReadU8(offset uint32) byte {
+	d.nread++
+	return d.MMIORegs.ReadU8(offset)
+}
+
+func (d *testDev) ReadU32(offset uint32) uint32 {
+	d.nread++
+	return d.MMIORegs.ReadU32(offset)
+}
+
+func (d *testDev) WriteU8(offset uint32, b byte) {
+	d.nwrite++
+	d.MMIORegs.WriteU8(offset, b)
+}
+
+func (d *testDev) WriteU32(offset uint32, w uint32) {
+	d.nwrite++
+	d.MMIORegs.WriteU32(offset, w)
+}
+
+func (d *testDev) Tick() { d.nticks++ }
+
+func TestMMIO(t *testing.T) {
+	as := func(cond bool, s string, args ...interface{}) {
+		if !cond {
+			t.Fatalf(s, args...)
+		}
+	}
+
+	m := NewMachine(&Config{MemSize: PageSize * 32})
+	d := &testDev{MMIORegs: NewMMIORegs(16)}
+	const addr = PageSize*pageMin + 0x20
+	err := m.MapDevice(addr, 16, d)
+	as(err == nil, "map device: %s", err)
+
+	err = m.MapDevice(addr+8, 16, new(testDev))
+	as(err != nil, "overlapping devices")
+	err = m.MapDevice(addr+2, 16, new(testDev))
+	as(err != nil, "misaligned device")
+	err = m.MapDevice(PageSize*32-4, 8, new(testDev))
+	as(err != nil, "device out of memory")
+	err = m.MapDevice(addr+16, 4, &testDev{MMIORegs: NewMMIORegs(4)})
+	as(err == nil, "map adjacent device: %s", err)
+
+	mem := m.phyMem
+	as(mem.WriteU32(addr+4, 0x12345678) == nil, "write word")
+	as(mem.WriteU8(addr+9, 0xab) == nil, "write byte")
+	as(mem.WriteU32(addr-4, 7) == nil, "write outside")
+	as(d.nwrite == 2, "device got %d writes", d.nwrite)
+	as(d.MMIORegs.ReadU32(4) == 0x12345678, "word not on device")
+	as(d.MMIORegs.ReadU8(9) == 0xab, "byte not on device")
+
+	w, e := mem.ReadU32(addr + 4)
+	as(e == nil && w == 0x12345678, "read word got %#x", w)
+	b, e := mem.ReadU8(addr + 7)
+	as(e == nil && b == 0x12, "read byte got %#x", b)
+	as(d.nread == 2, "device got %d reads", d.nread)
+
+	p := mem.Page(addr / PageSize)
+	as(p.ReadU32(addr+4) == 0, "device write went to memory")
+	as(p.ReadU32(addr-4) == 7, "plain write did not go to memory")
+
+	m.Tick()
+	m.Tick()
+	as(d.nticks == 2, "device ticked %d times", d.nticks)
+
+	line, err := m.DeclareInt(IntDevice + 3)
+	as(err == nil, "declare int: %s", err)
+	as(line.Code() == IntDevice+3, "wrong int code")
+	_, err = m.DeclareInt(IntDevice + 3)
+	as(err != nil, "interrupt declared twice")
+	_, err = m.DeclareInt(IntTimer)
+	as(err != nil, "reserved interrupt declared")
+
+	line.Issue(0)
+	line.Issue(5) // no such core
+	c := m.cores.cores[0]
+	c.interrupt.Enable()
+	c.interrupt.EnableInt(line.Code())
+	has, code := c.interrupt.Poll()
+	as(has && code == line.Code(), "interrupt not issued")
+}
@@ -96,6 +96,13 @@ func (c *multiCore) dumpRegs(core byte) []uint32 {
 	return ret
 }
 
+func (c *multiCore) perfCounters(core byte) *PerfCounters {
+	if int(core) >= len(c.cores) {
+		panic("out of cores")
+	}
+	return c.cores[core].perfCounters()
+}
+
 func (c *multiCore) setSP(sp, stackSize uint32) {
 	for i, cpu := range c.cores {
 		cpu.regs[SP] = sp + uint32(i+1)*stackSize
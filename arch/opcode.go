@@ -40,11 +40,17 @@ const (
 	MOD   = 19
 	MODU  = 20
 
-	FADD = 0
-	FSUB = 1
-	FMUL = 2
-	FDIV = 3
-	FINT = 4
+	FADD  = 0
+	FSUB  = 1
+	FMUL  = 2
+	FDIV  = 3
+	FINT  = 4  // float to int
+	FFLT  = 5  // int to float
+	FLT   = 6  // float less than
+	FEQ   = 7  // float equal
+	FNEG  = 8  // float negate
+	FABS  = 9  // float absolute value
+	FSQRT = 10 // float square root
 )
 
 // Branch instructions
@@ -7,6 +7,7 @@ const PageSize = 4096
 type page struct {
 	uints []uint32
 	dirty map[uint32]bool
+	mmio  []*mmioRange // device ranges mapped onto this page
 }
 
 // NewPage creates a new empty page.
@@ -75,6 +75,9 @@ func (pm *phyMemory) ReadU8(addr uint32) (byte, *Excep) {
 	if e != nil {
 		return 0, e
 	}
+	if r := p.device(addr); r != nil {
+		return r.dev.ReadU8(addr - r.start), nil
+	}
 	return p.ReadU8(addr), nil
 }
 
@@ -85,8 +88,12 @@ func (pm *phyMemory) WriteU8(addr uint32, v byte) *Excep {
 	if e != nil {
 		return e
 	}
+	if r := p.device(addr); r != nil {
+		r.dev.WriteU8(addr-r.start, v)
+		return nil
+	}
 	p.WriteU8(addr, v)
-	return e
+	return nil
 }
 
 // ReadU32 reads the byte at the given address.
@@ -96,6 +103,9 @@ func (pm *phyMemory) ReadU32(addr uint32) (uint32, *Excep) {
 	if e != nil {
 		return 0, e
 	}
+	if r := p.device(addr); r != nil {
+		return r.dev.ReadU32(addr - r.start), nil
+	}
 	return p.ReadU32(addr), nil
 }
 
@@ -106,6 +116,10 @@ func (pm *phyMemory) WriteU32(addr uint32, v uint32) *Excep {
 	if e != nil {
 		return e
 	}
+	if r := p.device(addr); r != nil {
+		r.dev.WriteU32(addr-r.start, v)
+		return nil
+	}
 	p.WriteU32(addr, v)
 	return nil
 }
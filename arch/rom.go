@@ -20,6 +20,8 @@ const (
 
 	romFilename    = 20
 	romFilenameMax = 100
+
+	romRegsSize = romFilename + romFilenameMax
 )
 
 const (
@@ -40,8 +42,8 @@ const (
 )
 
 type rom struct {
+	MMIORegs
 	intBus intBus
-	p      *pageOffset
 	mem    *phyMemory
 	root   string
 
@@ -56,12 +58,12 @@ type rom struct {
 	IntDone byte
 }
 
-func newROM(p *page, mem *phyMemory, i intBus, root string) *rom {
+func newROM(mem *phyMemory, i intBus, root string) *rom {
 	return &rom{
-		intBus: i,
-		p:      &pageOffset{p, romBase},
-		mem:    mem,
-		root:   root,
+		MMIORegs: NewMMIORegs(romRegsSize),
+		intBus:   i,
+		mem:      mem,
+		root:     root,
 
 		IntDone: IntROM,
 	}
@@ -72,17 +74,17 @@ func (r *rom) interrupt(code byte) {
 }
 
 func (r *rom) readFile() (byte, error) {
-	nameLen := r.p.readU8(romNameLen)
-	offset := r.p.readU32(romOffset)
-	addr := r.p.readU32(romAddr)
-	size := r.p.readU32(romSize)
+	nameLen := r.MMIORegs.ReadU8(romNameLen)
+	offset := r.MMIORegs.ReadU32(romOffset)
+	addr := r.MMIORegs.ReadU32(romAddr)
+	size := r.MMIORegs.ReadU32(romSize)
 
 	if nameLen > romFilenameMax {
 		nameLen = romFilenameMax
 	}
 	name := make([]byte, nameLen)
 	for i := range name {
-		name[i] = r.p.readU8(romFilename + uint32(i))
+		name[i] = r.MMIORegs.ReadU8(romFilename + uint32(i))
 	}
 
 	fullPath := filepath.Join(r.root, string(name))
@@ -114,48 +116,76 @@ func (r *rom) readFile() (byte, error) {
 	return 0, nil
 }
 
+func (r *rom) setState(state byte) {
+	r.state = state
+	r.MMIORegs.WriteU8(romState, state)
+}
+
+// start starts reading the file if a command is issued when idle. A
+// command issued when busy stays in the command register, and starts
+// when the current read finishes.
+func (r *rom) start() {
+	if r.state != romStateIdle {
+		return
+	}
+	cmd := r.MMIORegs.ReadU8(romCmd)
+	if cmd == romCmdIdle {
+		return
+	}
+
+	r.setState(romStateBusy)
+	errCode, err := r.readFile()
+	if err != nil && err != io.EOF {
+		log.Println(err)
+	}
+
+	if len(r.bs) == 0 {
+		r.countDown = 5
+	} else {
+		r.countDown = 10 * len(r.bs)
+	}
+
+	r.err = errCode
+	r.MMIORegs.WriteU8(romCmd, romCmdIdle)
+}
+
+// WriteU8 writes a ROM register, and starts the command if issued.
+func (r *rom) WriteU8(offset uint32, b byte) {
+	r.MMIORegs.WriteU8(offset, b)
+	r.start()
+}
+
+// WriteU32 writes a ROM register word, and starts the command if issued.
+func (r *rom) WriteU32(offset uint32, w uint32) {
+	r.MMIORegs.WriteU32(offset, w)
+	r.start()
+}
+
+// Tick counts down the busy ROM, and finishes the read when the count
+// reaches 0.
 func (r *rom) Tick() {
-	switch r.state {
-	case romStateIdle:
-		cmd := r.p.readU8(romCmd)
-		if cmd != 0 {
-			r.state = romStateBusy
-
-			errCode, err := r.readFile()
-			if err != nil && err != io.EOF {
-				log.Println(err)
-			}
+	if r.state != romStateBusy {
+		return
+	}
+	if r.countDown > 0 {
+		r.countDown--
+		return
+	}
 
-			if len(r.bs) == 0 {
-				r.countDown = 5
-			} else {
-				r.countDown = 10 * len(r.bs)
-			}
+	if r.err == romErrNone {
+		r.MMIORegs.WriteU32(romNread, uint32(len(r.bs)))
 
-			r.err = errCode
-			r.p.writeU8(romCmd, romCmdIdle)
-		}
-	case romStateBusy:
-		if r.countDown > 0 {
-			r.countDown--
-		} else {
-			if r.err == romErrNone {
-				r.p.writeU32(romNread, uint32(len(r.bs)))
-
-				for i, b := range r.bs {
-					err := r.mem.WriteU8(r.addr+uint32(i), b)
-					if err != nil {
-						r.err = romErrMemory
-						break
-					}
-				}
+		for i, b := range r.bs {
+			err := r.mem.WriteU8(r.addr+uint32(i), b)
+			if err != nil {
+				r.err = romErrMemory
+				break
 			}
-
-			r.p.writeU8(romErr, r.err)
-			r.state = romStateIdle
-			r.interrupt(r.IntDone)
 		}
 	}
 
-	r.p.writeU8(romState, r.state)
+	r.MMIORegs.WriteU8(romErr, r.err)
+	r.setState(romStateIdle)
+	r.interrupt(r.IntDone)
+	r.start() // the command latched when busy
 }
@@ -0,0 +1,53 @@
+package arch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestROMBusyCommand(t *testing.T) {
+	as := func(cond bool, s string, args ...interface{}) {
+		if !cond {
+			t.Fatalf(s, args...)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "smlvm-rom")
+	as(err == nil, "make temp dir: %s", err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"a", "b"} {
+		p := filepath.Join(dir, name)
+		err := ioutil.WriteFile(p, []byte(name+name), 0644)
+		as(err == nil, "write file %q: %s", name, err)
+	}
+
+	bus := &testIntBus{ncore: 1}
+	mem := newPhyMemory(PageSize * 4)
+	r := newROM(mem, bus, dir)
+
+	read := func(name string, addr uint32) {
+		r.WriteU8(romFilename, name[0])
+		r.WriteU8(romNameLen, 1)
+		r.WriteU32(romAddr, addr)
+		r.WriteU32(romSize, 8)
+		r.WriteU8(romCmd, romCmdRequest)
+	}
+
+	read("a", PageSize)
+	as(r.state == romStateBusy, "rom is not busy")
+	read("b", PageSize+4) // issued when busy
+
+	for i := 0; i < 100; i++ {
+		r.Tick()
+	}
+	as(r.state == romStateIdle, "rom is still busy")
+	as(len(bus.ints) == 2, "got %d interrupts", len(bus.ints))
+
+	for i, want := range []byte("aa\x00\x00bb") {
+		b, _ := mem.ReadU8(PageSize + uint32(i))
+		as(b == want, "byte %d is %q, want %q", i, b, want)
+	}
+}
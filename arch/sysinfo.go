@@ -1,7 +1,62 @@
 package arch
 
-// SYSINFO commands
+// SYSINFO commands. Each counter is 64-bit, where the lower 32-bit is
+// returned in the first register and the higher 32-bit in the second.
 const (
-	NCYCLE = iota // The number of cycles.
-	CPUID         // The CPU id of the current core.
+	NCYCLE     = iota // The number of cycles.
+	CPUID             // The CPU id of the current core.
+	NINST             // The number of instructions retired.
+	NLOAD             // The number of memory load instructions.
+	NSTORE            // The number of memory store instructions.
+	NPAGEFAULT        // The number of page faults.
+	NINTERRUPT        // The number of interrupts taken.
+	NPAGEWALK         // The number of page table walks.
+	NIOCALL           // The number of IO calls.
 )
+
+// PerfCounters contains the performance counters of a core.
+type PerfCounters struct {
+	Cycles     uint64 // cycles ticked
+	Insts      uint64 // instructions retired
+	Loads      uint64 // memory load instructions retired
+	Stores     uint64 // memory store instructions retired
+	PageFaults uint64 // page faults met
+	Interrupts uint64 // interrupts and faults entered
+
+	// PageWalks counts the address translations through the page table.
+	// The machine has no TLB, so each virtual memory access with a page
+	// table walks it.
+	PageWalks uint64
+
+	IOCalls uint64 // IO calls invoked
+}
+
+func split64(v uint64) (uint32, uint32) {
+	return uint32(v), uint32(v >> 32)
+}
+
+func sysInfo(cpu *cpu, cmd uint32) (uint32, uint32) {
+	c := cpu.perfCounters()
+	switch cmd {
+	case NCYCLE:
+		return split64(c.Cycles)
+	case CPUID:
+		return uint32(cpu.index), 0
+	case NINST:
+		return split64(c.Insts)
+	case NLOAD:
+		return split64(c.Loads)
+	case NSTORE:
+		return split64(c.Stores)
+	case NPAGEFAULT:
+		return split64(c.PageFaults)
+	case NINTERRUPT:
+		return split64(c.Interrupts)
+	case NPAGEWALK:
+		return split64(c.PageWalks)
+	case NIOCALL:
+		return split64(c.IOCalls)
+	}
+
+	return 0, 0
+}
@@ -0,0 +1,64 @@
+package arch
+
+import (
+	"testing"
+)
+
+func TestSysInfo(t *testing.T) {
+	as := func(cond bool, s string, args ...interface{}) {
+		if !cond {
+			t.Fatalf(s, args...)
+		}
+	}
+
+	m := newPhyMemory(PageSize * 32)
+	cpu := newCPU(m, nil, new(instArch8), 3)
+
+	immInst := func(op, d, s, im uint32) uint32 {
+		return op<<24 | (d&0x7)<<21 | (s&0x7)<<18 | im&0xffff
+	}
+	sysInst := func(op, r1, r2 uint32) uint32 {
+		return op<<24 | (r1&0x7)<<21 | (r2&0x7)<<18
+	}
+
+	prog := []uint32{
+		immInst(ADDUI, 1, 0, 1),      // r1 = 0x10000
+		immInst(SW, 1, 1, 0),         // *r1 = r1
+		immInst(LW, 2, 1, 0),         // r2 = *r1
+		immInst(LB, 2, 1, 1),         // r2 = *(r1+1)
+		immInst(ADDI, 3, 0, NLOAD),   // r3 = NLOAD
+		sysInst(SYSINFO, 3, 4),       // r3, r4 = sysinfo(r3)
+		immInst(ADDI, 1, 0, NCYCLE),  // r1 = NCYCLE
+		sysInst(SYSINFO, 1, 2),       // r1, r2 = sysinfo(r1)
+		immInst(ADDI, 2, 0, CPUID),   // r2 = CPUID
+		sysInst(SYSINFO, 2, 0),       // r2 = sysinfo(r2)
+		immInst(ADDI, 0, 0, NSTORE),  // r0 = NSTORE
+		sysInst(SYSINFO, 0, 4),       // r0, r4 = sysinfo(r0)
+		immInst(ADDI, 4, 0, NIOCALL), // r4 = NIOCALL
+		sysInst(SYSINFO, 4, 4),       // r4 = sysinfo(r4)
+	}
+	for i, in := range prog {
+		m.WriteU32(InitPC+uint32(i)*4, in)
+	}
+	for range prog {
+		e := cpu.Tick()
+		as(e == nil, "unexpected exception: %s", e)
+	}
+
+	as(cpu.regs[R3] == 2, "got %d loads", cpu.regs[R3])
+	as(cpu.regs[R1] == 8, "got %d cycles", cpu.regs[R1])
+	as(cpu.regs[R2] == 3, "got cpu id %d", cpu.regs[R2])
+	as(cpu.regs[R0] == 1, "got %d stores", cpu.regs[R0])
+	as(cpu.regs[R4] == 0, "got %d io calls", cpu.regs[R4])
+
+	c := cpu.perfCounters()
+	n := uint64(len(prog))
+	as(c.Cycles == n, "got %d cycles", c.Cycles)
+	as(c.Insts == n, "got %d instructions", c.Insts)
+	as(c.Loads == 2 && c.Stores == 1, "bad load store counts")
+	as(c.PageWalks == 0, "no page table, but got page walks")
+
+	cpu.perf.Cycles = 0x123456789a
+	lo, hi := sysInfo(cpu, NCYCLE)
+	as(lo == 0x3456789a && hi == 0x12, "bad cycle split: %x %x", hi, lo)
+}
@@ -0,0 +1,112 @@
+package arch
+
+// Timer layout, relative to timerBase on the basic IO page.
+const (
+	timerCycle = 0x0 // 64-bit cycle counter, read-only
+
+	timerCores   = 0x10 // start of per-core control blocks
+	timerCoreLen = 0x10 // size of a per-core control block
+)
+
+// timerRegsSize returns the size of the timer registers, which has a
+// control block for each core.
+func timerRegsSize(ncore byte) uint32 {
+	return timerCores + uint32(ncore)*timerCoreLen
+}
+
+// Per-core timer control block layout.
+const (
+	timerCtrl     = 0x0 // control bits
+	timerCode     = 0x1 // interrupt code, 0 for IntTimer
+	timerCompare  = 0x4 // lower 32-bit of the cycle to fire at
+	timerInterval = 0x8 // interval for periodic mode
+)
+
+// Timer control bits.
+const (
+	timerEnable   = 0x1 // timer is armed
+	timerPeriodic = 0x2 // rearms after firing
+)
+
+// timer is a programmable timer device. It counts the cycles, and for
+// each core, it issues an interrupt when the lower 32-bit of the cycle
+// counter reaches the core's compare register. In one-shot mode, the
+// timer disarms itself after firing; in periodic mode, the compare
+// register advances by the interval.
+type timer struct {
+	MMIORegs
+	intBus intBus
+	ncycle uint64
+}
+
+func newTimer(i intBus) *timer {
+	return &timer{
+		MMIORegs: NewMMIORegs(timerRegsSize(i.Ncore())),
+		intBus:   i,
+	}
+}
+
+func timerCoreOffset(core byte) uint32 {
+	return timerCores + uint32(core)*timerCoreLen
+}
+
+// Cycle returns the current cycle count.
+func (t *timer) Cycle() uint64 { return t.ncycle }
+
+func (t *timer) tickCore(core byte) {
+	base := timerCoreOffset(core)
+	ctrl := t.MMIORegs.ReadU8(base + timerCtrl)
+	if ctrl&timerEnable == 0 {
+		return
+	}
+
+	compare := t.MMIORegs.ReadU32(base + timerCompare)
+	if int32(uint32(t.ncycle)-compare) < 0 {
+		return // not yet
+	}
+
+	code := t.MMIORegs.ReadU8(base + timerCode)
+	if code == 0 {
+		code = IntTimer
+	}
+	t.intBus.Interrupt(code, core)
+
+	interval := t.MMIORegs.ReadU32(base + timerInterval)
+	if ctrl&timerPeriodic != 0 && interval > 0 {
+		t.MMIORegs.WriteU32(base+timerCompare, compare+interval)
+	} else {
+		t.MMIORegs.WriteU8(base+timerCtrl, ctrl&^timerEnable)
+	}
+}
+
+// ReadU8 reads a timer register byte.
+func (t *timer) ReadU8(offset uint32) byte {
+	if offset < timerCycle+8 {
+		var buf [8]byte
+		Endian.PutUint64(buf[:], t.ncycle)
+		return buf[offset-timerCycle]
+	}
+	return t.MMIORegs.ReadU8(offset)
+}
+
+// ReadU32 reads a timer register word.
+func (t *timer) ReadU32(offset uint32) uint32 {
+	switch offset {
+	case timerCycle:
+		return uint32(t.ncycle)
+	case timerCycle + 4:
+		return uint32(t.ncycle >> 32)
+	}
+	return t.MMIORegs.ReadU32(offset)
+}
+
+// Tick increases the cycle counter, and fires the timers of the cores
+// that reach their compare value.
+func (t *timer) Tick() {
+	t.ncycle++
+
+	ncore := t.intBus.Ncore()
+	for i := byte(0); i < ncore; i++ {
+		t.tickCore(i)
+	}
+}
@@ -0,0 +1,88 @@
+package arch
+
+import (
+	"testing"
+)
+
+type intRecord struct {
+	core byte
+	code byte
+}
+
+type testIntBus struct {
+	ncore byte
+	ints  []*intRecord
+}
+
+func (b *testIntBus) Ncore() byte { return b.ncore }
+
+func (b *testIntBus) Interrupt(code byte, core byte) {
+	b.ints = append(b.ints, &intRecord{core: core, code: code})
+}
+
+var (
+	_ device     = new(timer)
+	_ MMIODevice = new(timer)
+)
+
+func TestTimer(t *testing.T) {
+	as := func(cond bool, s string, args ...interface{}) {
+		if !cond {
+			t.Fatalf(s, args...)
+		}
+	}
+
+	bus := &testIntBus{ncore: 2}
+	tm := newTimer(bus)
+	p := tm.MMIORegs
+	as(len(p) == timerCores+2*timerCoreLen, "got %d bytes of registers",
+		len(p))
+
+	for i := 0; i < 5; i++ {
+		tm.Tick()
+	}
+	as(len(bus.ints) == 0, "disabled timer fired")
+	as(tm.Cycle() == 5, "cycle is %d", tm.Cycle())
+	as(tm.ReadU32(timerCycle) == 5, "cycle register is not 5")
+	as(tm.ReadU8(timerCycle) == 5, "cycle register byte is not 5")
+
+	// one shot on core 0, at cycle 8
+	core0 := timerCoreOffset(0)
+	p.WriteU32(core0+timerCompare, 8)
+	p.WriteU8(core0+timerCtrl, timerEnable)
+
+	// periodic on core 1, at cycle 7 with interval 3 and code 40
+	core1 := timerCoreOffset(1)
+	p.WriteU32(core1+timerCompare, 7)
+	p.WriteU32(core1+timerInterval, 3)
+	p.WriteU8(core1+timerCode, 40)
+	p.WriteU8(core1+timerCtrl, timerEnable|timerPeriodic)
+
+	var fired []uint64
+	for tm.Cycle() < 14 {
+		n := len(bus.ints)
+		tm.Tick()
+		if len(bus.ints) > n {
+			fired = append(fired, tm.Cycle())
+		}
+	}
+
+	as(len(bus.ints) == 4, "got %d interrupts", len(bus.ints))
+	exp := []*intRecord{
+		{core: 1, code: 40},
+		{core: 0, code: IntTimer},
+		{core: 1, code: 40},
+		{core: 1, code: 40},
+	}
+	for i, r := range exp {
+		got := bus.ints[i]
+		as(*got == *r, "interrupt %d: got %v, want %v", i, got, r)
+	}
+	expCycles := []uint64{7, 8, 10, 13}
+	for i, c := range expCycles {
+		as(fired[i] == c, "fired at %d, want %d", fired[i], c)
+	}
+
+	as(p.ReadU8(core0+timerCtrl)&timerEnable == 0, "one shot not disarmed")
+	as(p.ReadU32(core1+timerCompare) == 16, "periodic not rearmed")
+}
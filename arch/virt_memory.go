@@ -5,6 +5,7 @@ package arch
 type virtMemory struct {
 	phyMem *phyMemory
 	ptable *pageTable
+	nwalk  uint64 // number of page table walks
 }
 
 // NewVirtMemory creates a new virtual address space with no page table.
@@ -29,6 +30,7 @@ func (vm *virtMemory) transRead(addr uint32, ring byte) (uint32, *Excep) {
 	if vm.ptable == nil {
 		return addr, nil
 	}
+	vm.nwalk++
 	return vm.ptable.TranslateRead(addr, ring)
 }
 
@@ -36,6 +38,7 @@ func (vm *virtMemory) transWrite(addr uint32, ring byte) (uint32, *Excep) {
 	if vm.ptable == nil {
 		return addr, nil
 	}
+	vm.nwalk++
 	return vm.ptable.TranslateWrite(addr, ring)
 }
 
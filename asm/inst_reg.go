@@ -48,7 +48,17 @@ var (
 		"fsub": arch.FSUB,
 		"fmul": arch.FMUL,
 		"fdiv": arch.FDIV,
-		"fint": arch.FINT,
+		"flt":  arch.FLT,
+		"feq":  arch.FEQ,
+	}
+
+	// op reg reg
+	opFloat2Map = map[string]uint32{
+		"fint":  arch.FINT,
+		"fflt":  arch.FFLT,
+		"fneg":  arch.FNEG,
+		"fabs":  arch.FABS,
+		"fsqrt": arch.FSQRT,
 	}
 )
 
@@ -113,6 +123,10 @@ func resolveInstReg(log lexing.Logger, ops []*lexing.Token) (*inst, bool) {
 			s2 = resolveReg(log, args[2])
 		}
 		isFloat = 1
+	} else if fn, found = opFloat2Map[opName]; found {
+		// op reg reg
+		argCount(2)
+		isFloat = 1
 	} else {
 		return nil, false
 	}
@@ -0,0 +1,58 @@
+package asm
+
+import (
+	"strings"
+	"testing"
+
+	"shanhu.io/smlvm/asm/parse"
+	"shanhu.io/smlvm/dasm"
+	"shanhu.io/smlvm/lexing"
+)
+
+func TestInstRegFloat(t *testing.T) {
+	resolve := func(s string) (*inst, []*lexing.Error) {
+		var ops []*lexing.Token
+		for _, f := range strings.Fields(s) {
+			ops = append(ops, &lexing.Token{Type: parse.Operand, Lit: f})
+		}
+		log := lexing.NewErrorList()
+		in, found := resolveInstReg(log, ops)
+		if !found {
+			t.Fatalf("%q not found", s)
+		}
+		return in, log.Errs()
+	}
+
+	for _, s := range []string{
+		"fadd r1 r2 r3",
+		"fsub r4 r3 r2",
+		"fmul r1 r1 r1",
+		"fdiv r2 r3 r4",
+		"flt r1 r2 r3",
+		"feq r3 r2 r1",
+		"fint r1 r2",
+		"fflt r3 r4",
+		"fneg r2 r2",
+		"fabs r4 r1",
+		"fsqrt r1 r3",
+	} {
+		in, errs := resolve(s)
+		if errs != nil {
+			t.Errorf("%q: %v", s, errs)
+			continue
+		}
+		if got := dasm.NewLine(0, in.inst).Str; got != s {
+			t.Errorf("%q: disassembled to %q", s, got)
+		}
+	}
+
+	for _, s := range []string{
+		"fint r1 r2 r3",
+		"fsqrt r1",
+		"fadd r1 r2",
+	} {
+		if _, errs := resolve(s); errs == nil {
+			t.Errorf("%q: should fail", s)
+		}
+	}
+}
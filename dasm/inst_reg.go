@@ -38,7 +38,16 @@ var (
 		arch.FSUB: "fsub",
 		arch.FMUL: "fmul",
 		arch.FDIV: "fdiv",
-		arch.FINT: "fint",
+		arch.FLT:  "flt",
+		arch.FEQ:  "feq",
+	}
+
+	opFloat2Map = map[uint32]string{
+		arch.FINT:  "fint",
+		arch.FFLT:  "fflt",
+		arch.FNEG:  "fneg",
+		arch.FABS:  "fabs",
+		arch.FSQRT: "fsqrt",
 	}
 )
 
@@ -68,6 +77,8 @@ func instReg(addr uint32, in uint32) *Line {
 	} else {
 		if opStr, found := opFloatMap[funct]; found {
 			s = fmt.Sprintf("%s %s %s %s", opStr, dest, src1, src2)
+		} else if opStr, found := opFloat2Map[funct]; found {
+			s = fmt.Sprintf("%s %s %s", opStr, dest, src1)
 		}
 	}
 
@@ -9,3 +9,20 @@ func CodeStart(secs []*Section) (uint32, bool) {
 	}
 	return 0, false
 }
+
+// End returns the virtual address right after the last byte of the
+// sections that are loaded into memory.
+func End(secs []*Section) uint32 {
+	var ret uint32
+	for _, s := range secs {
+		switch s.Type {
+		case Code, Data, Zeros:
+		default:
+			continue
+		}
+		if end := s.Addr + s.Size; end > ret {
+			ret = end
+		}
+	}
+	return ret
+}
@@ -8,6 +8,7 @@ import (
 type Func struct {
 	insts []uint32
 	links []*link
+	frame *Frame
 
 	// filled when linking
 	// TODO: this should not be here.
@@ -24,6 +25,10 @@ func (f *Func) AddInst(i uint32) {
 	f.insts = append(f.insts, i)
 }
 
+// SetFrame sets the layout of the stack frame of the function. Only
+// functions with frame layouts are listed in the function table.
+func (f *Func) SetFrame(frame *Frame) { f.frame = frame }
+
 // TooLarge checks if the function size is larger than 4GB.
 func (f *Func) TooLarge() bool {
 	return len(f.insts)*4 >= math.MaxInt32
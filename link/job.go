@@ -40,8 +40,9 @@ func SinglePkg(pkg *Pkg, start string) ([]*image.Section, error) {
 
 // Link performs the linking job and writes the output to out.
 func (j *Job) Link() ([]*image.Section, error) {
-	pkgs := j.Pkgs
+	pkgs := withTables(j.Pkgs)
 	used := traceUsed(pkgs, j.Funcs)
+	fillTables(pkgs, used)
 	main := wrapMain(j.Funcs)
 	funcs, vars, zeros, err := layout(pkgs, used, main, j.InitPC)
 	if err != nil {
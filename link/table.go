@@ -0,0 +1,109 @@
+package link
+
+// TablePkg is the package of the tables that the linker generates.
+const TablePkg = "$link"
+
+// Symbols in TablePkg.
+const (
+	// TableFuncs lists the functions that have frame layouts. It starts
+	// with the number of functions, followed by an entry of the address,
+	// the code size and the offset of the frame layout in the table for
+	// each function, sorted by the addresses. A frame layout is the
+	// frame size, the offset of the saved return address, and the runs
+	// of the pointers.
+	TableFuncs = "funcs"
+
+	// TableRoots lists the variables that have pointers. Each entry is
+	// the address of the variable and the runs of the pointers. It ends
+	// with a zero word.
+	TableRoots = "roots"
+)
+
+// Frame is the layout of a stack frame. All offsets are from the stack
+// pointer.
+type Frame struct {
+	Size    uint32
+	RetAddr uint32   // where the return address is saved
+	Ptrs    []uint32 // words that might hold pointers, sorted
+}
+
+// writeRuns writes the number of runs of the consecutive words in
+// ptrs, followed by the runs. Each run is the offset of the first word
+// and the number of words.
+func writeRuns(v *Var, ptrs []uint32) {
+	var runs []uint32
+	for i, p := range ptrs {
+		n := len(runs)
+		if i > 0 && p == ptrs[i-1]+4 {
+			runs[n-1]++
+			continue
+		}
+		runs = append(runs, p, 1)
+	}
+
+	w := newWriter(nil, v)
+	w.writeU32(uint32(len(runs) / 2))
+	for _, r := range runs {
+		w.writeU32(r)
+	}
+}
+
+// withTables returns the packages with the table package added, where
+// the tables are not filled yet.
+func withTables(pkgs map[string]*Pkg) map[string]*Pkg {
+	ret := make(map[string]*Pkg)
+	for path, p := range pkgs {
+		ret[path] = p
+	}
+
+	p := NewPkg(TablePkg)
+	for _, name := range []string{TableFuncs, TableRoots} {
+		p.DeclareVar(name)
+		p.DefineVar(name, NewVar(4))
+	}
+	ret[TablePkg] = p
+	return ret
+}
+
+// fillTables fills the tables with the used functions and variables.
+// The functions are listed in the order they are laid out.
+func fillTables(pkgs map[string]*Pkg, used []*PkgSym) {
+	var funcs []*PkgSym
+	roots := NewVar(4)
+	for _, ps := range used {
+		switch pkgSym(pkgs, ps).Type {
+		case SymFunc:
+			if pkgFunc(pkgs, ps).frame != nil {
+				funcs = append(funcs, ps)
+			}
+		case SymVar:
+			if v := pkgVar(pkgs, ps); len(v.ptrs) > 0 {
+				roots.WriteLink(ps.Pkg, ps.Sym)
+				writeRuns(roots, v.ptrs)
+			}
+		}
+	}
+	roots.Pad(4)
+
+	tab := NewVar(4)
+	layouts := NewVar(4)
+	w := newWriter(nil, tab)
+	w.writeU32(uint32(len(funcs)))
+	offset := 4 + uint32(len(funcs))*12
+	for _, ps := range funcs {
+		f := pkgFunc(pkgs, ps)
+		tab.WriteLink(ps.Pkg, ps.Sym)
+		w.writeU32(f.Size())
+		w.writeU32(offset + layouts.Size())
+
+		lw := newWriter(nil, layouts)
+		lw.writeU32(f.frame.Size)
+		lw.writeU32(f.frame.RetAddr)
+		writeRuns(layouts, f.frame.Ptrs)
+	}
+	tab.Write(layouts.buf.Bytes())
+
+	p := pkgs[TablePkg]
+	p.DefineVar(TableFuncs, tab)
+	p.DefineVar(TableRoots, roots)
+}
@@ -16,6 +16,7 @@ type Var struct {
 	zeros uint32
 
 	links []*link // symbols
+	ptrs  []uint32
 
 	// filled when linking
 	//
@@ -44,6 +45,10 @@ func NewVar(align uint32) *Var {
 // Zeros set this variable as a BSS section.
 func (v *Var) Zeros(n uint32) { v.zeros = n }
 
+// SetPtrs sets the offsets of the words in the variable that might
+// hold heap pointers. The offsets must be sorted.
+func (v *Var) SetPtrs(ptrs []uint32) { v.ptrs = ptrs }
+
 // IsZeros checks if the variable section is a BSS section.
 func (v *Var) IsZeros() bool { return v.zeros > 0 }
 
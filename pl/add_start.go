@@ -63,10 +63,7 @@ func addTestStart(b *builder, testList codegen.Ref, n int) {
 	b.b.Arith(addr, index, "*", codegen.Num(arch.RegSize))
 	b.b.Arith(addr, base, "+", addr)
 
-	f := codegen.NewFuncPtr(
-		codegen.VoidFuncSig,
-		codegen.NewAddrRef(addr, arch.RegSize, 0, false, true),
-	)
+	f := codegen.NewFuncPtr(codegen.VoidFuncSig, addr)
 
 	testMain := findFunc(b, "testMain", testMainFuncType)
 	if testMain == nil {
@@ -0,0 +1,93 @@
+package pl
+
+import (
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+func buildCallNew(b *builder, expr *tast.CallExpr) *ref {
+	t := expr.Type().(*types.Pointer)
+	ret := b.newTemp(t)
+	size := codegen.Num(uint32(t.T.Size()))
+	typ := b.typeDesc(t.T)
+	b.b.Call([]codegen.Ref{ret.IR()}, b.rt.alloc, size, typ)
+	return ret
+}
+
+// newHeapVar allocates a variable in the heap. The variable is accessed
+// via a local pointer.
+func newHeapVar(b *builder, t types.T, name string) *ref {
+	p := b.newLocal(&types.Pointer{T: t}, name)
+	size := codegen.Num(uint32(t.Size()))
+	b.b.Call([]codegen.Ref{p}, b.rt.alloc, size, b.typeDesc(t))
+	ir := codegen.NewAddrRef(
+		p, t.Size(), 0, types.IsByte(t), t.RegSizeAlign(),
+	)
+	return newAddressableRef(t, ir)
+}
+
+// buildMakeSlice allocates the elements of a slice in the heap.
+func buildMakeSlice(b *builder, t *types.Slice, n *ref) *ref {
+	size := checkArrayIndex(b, n)
+	elemSize := arrayElementSize(t.T)
+	if elemSize > 1 {
+		// panics when the total size overflows
+		max := codegen.Num(^uint32(0) / uint32(elemSize))
+		checkInRange(b, size, max, "u<=")
+	}
+
+	nbyte := b.newPtr()
+	b.b.Arith(nbyte, size, "*", codegen.Snum(elemSize))
+	data := callRuntime(b, b.rt.alloc, nbyte, b.typeDesc(t.T))
+	return newSlice(b, t.T, data, size)
+}
+
+func buildCallAppend(b *builder, expr *tast.CallExpr) *ref {
+	args := b.buildExpr(expr.Args)
+	s := args.At(0)
+	if expr.Dots {
+		return buildAppendSlice(b, s, args.At(1))
+	}
+	nadd := args.Len() - 1
+	if nadd == 0 {
+		return s
+	}
+
+	t := s.Type().(*types.Slice)
+	addr, n, _ := loadArray(b, s)
+	elemSize := arrayElementSize(t.T)
+	data := callRuntime(b, b.rt.sliceGrow,
+		addr, n, codegen.Num(uint32(nadd)), codegen.Snum(elemSize),
+		b.typeDesc(t.T),
+	)
+
+	p := b.newPtr()
+	b.b.Arith(p, n, "*", codegen.Snum(elemSize))
+	b.b.Arith(p, data, "+", p)
+	for i := 1; i <= nadd; i++ {
+		if i > 1 {
+			b.b.Arith(p, p, "+", codegen.Snum(elemSize))
+		}
+		b.b.Assign(elemAt(p, t.T, 0), args.At(i).IR())
+	}
+
+	size := b.newPtr()
+	b.b.Arith(size, n, "+", codegen.Num(uint32(nadd)))
+	return newSlice(b, t.T, data, size)
+}
+
+// buildAppendSlice appends the elements of slice t to slice s.
+func buildAppendSlice(b *builder, s, t *ref) *ref {
+	st := s.Type().(*types.Slice)
+	addr, n, _ := loadArray(b, s)
+	src, nadd, _ := loadArray(b, t)
+	elemSize := arrayElementSize(st.T)
+	data := callRuntime(b, b.rt.sliceAppend,
+		addr, n, src, nadd, codegen.Snum(elemSize), b.typeDesc(st.T),
+	)
+
+	size := b.newPtr()
+	b.b.Arith(size, n, "+", nadd)
+	return newSlice(b, st.T, data, size)
+}
@@ -0,0 +1,60 @@
+package pl
+
+import (
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+func buildArrayLit(b *builder, lit *tast.ArrayLit) *ref {
+	var t *types.Array
+	switch lt := types.Underlying(lit.T).(type) {
+	case *types.Array:
+		t = lt
+	case *types.Slice:
+		if lit.Len == 0 {
+			ret := b.newTemp(lt)
+			b.b.Zero(ret.IR())
+			return ret
+		}
+		t = &types.Array{T: lt.T, N: lit.Len}
+	default:
+		panic("not an array literal")
+	}
+
+	et := t.T
+	size := arrayElementSize(et)
+	_, isSlice := types.Underlying(lit.T).(*types.Slice)
+
+	var array *ref
+	var base codegen.Ref
+	if isSlice && b.rt != nil {
+		// slice literals are backed by an array in the heap
+		n := codegen.Num(uint32(t.Size()))
+		base = b.newPtr()
+		b.b.Call([]codegen.Ref{base}, b.rt.alloc, n, b.typeDesc(et))
+	} else {
+		// without the runtime, they are backed by an array on the stack
+		array = b.newTemp(t)
+		b.b.Zero(array.IR())
+		base = b.newPtr()
+		b.b.Arith(base, nil, "&", array.IR())
+	}
+
+	for i, expr := range lit.Exprs {
+		v := b.buildExpr(expr)
+		dest := codegen.NewAddrRef(
+			base,
+			et.Size(),
+			lit.Keys[i]*size,
+			types.IsByte(et),
+			true,
+		)
+		b.b.Assign(dest, v.IR())
+	}
+
+	if isSlice {
+		return newSlice(b, et, base, codegen.Snum(t.N))
+	}
+	return array
+}
@@ -59,6 +59,9 @@ func opAssign(b *builder, dest, src *ref, op string) {
 		}
 		buildBasicArith(b, dest, dest, src, opOp)
 		return
+	case types.Float32:
+		b.b.Arith(dest.IR(), dest.IR(), "f"+opOp, src.IR())
+		return
 	}
 
 	panic("bug")
@@ -0,0 +1,46 @@
+package ast
+
+import (
+	"shanhu.io/smlvm/lexing"
+)
+
+// ChanTypeExpr is the type expression of a channel, like "chan int"
+type ChanTypeExpr struct {
+	Kw   *lexing.Token
+	Elem Expr
+}
+
+// GoStmt is a statement that starts a function call in a new thread.
+// go <call>
+type GoStmt struct {
+	Kw   *lexing.Token
+	Call Expr
+	Semi *lexing.Token
+}
+
+// SendStmt sends a value into a channel, like "ch <- v".
+type SendStmt struct {
+	Chan  Expr
+	Arrow *lexing.Token
+	Val   Expr
+	Semi  *lexing.Token
+}
+
+// SelectStmt waits on a set of channel operations.
+type SelectStmt struct {
+	Kw     *lexing.Token
+	Lbrace *lexing.Token
+	Cases  []*SelectCase
+	Rbrace *lexing.Token
+	Semi   *lexing.Token
+}
+
+// SelectCase is a case in a select statement. Comm is a *SendStmt, an
+// *ExprStmt of a receive, or an *AssignStmt or *DefineStmt whose right
+// side is a receive. Comm is nil for the default case.
+type SelectCase struct {
+	Kw    *lexing.Token
+	Comm  Stmt
+	Colon *lexing.Token
+	Stmts []Stmt
+}
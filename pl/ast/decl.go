@@ -48,3 +48,12 @@ type ConstDecls struct {
 	Rparen *lexing.Token // optional
 	Semi   *lexing.Token
 }
+
+// TypeDecl declares a named type over another type, like
+// "type Celsius int".
+type TypeDecl struct {
+	Kw   *lexing.Token
+	Name *lexing.Token
+	Type Expr
+	Semi *lexing.Token
+}
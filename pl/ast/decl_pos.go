@@ -19,6 +19,8 @@ func DeclPos(d Decl) *lexing.Pos {
 		return d.Kw.Pos
 	case *Interface:
 		return d.Kw.Pos
+	case *TypeDecl:
+		return d.Kw.Pos
 	default:
 		panic(fmt.Errorf("invalid top-level declaration type: %T", d))
 	}
@@ -21,6 +21,17 @@ type MemberExpr struct {
 	Sub  *lexing.Token
 }
 
+// TypeAssertExpr is a type assertion like "v.(T)". Type is nil and TypeKw
+// is set for "v.(type)" in a type switch.
+type TypeAssertExpr struct {
+	Expr   Expr
+	Dot    *lexing.Token
+	Lparen *lexing.Token
+	Type   Expr
+	TypeKw *lexing.Token
+	Rparen *lexing.Token
+}
+
 // OpExpr is a binary or unary operation that uses an operator
 type OpExpr struct {
 	A  Expr
@@ -60,6 +71,7 @@ type CallExpr struct {
 	Func   Expr
 	Lparen *lexing.Token
 	Args   *ExprList
+	Dots   *lexing.Token // "..." after the last argument, optional
 	Rparen *lexing.Token
 }
 
@@ -76,7 +88,8 @@ type IndexExpr struct {
 // ArrayTypeExpr is the type expression of an array or a slice
 type ArrayTypeExpr struct {
 	Lbrack *lexing.Token
-	Len    Expr // optional
+	Len    Expr          // optional
+	Dots   *lexing.Token // "..." for the length of an array literal
 	Rbrack *lexing.Token
 	Type   Expr
 }
@@ -89,6 +102,45 @@ type ArrayLiteral struct {
 	Rbrace *lexing.Token
 }
 
+// StructLiteral is a struct literal, like "Point{X: 1, Y: 2}". It is
+// also a literal whose type is elided, like "{1, 2}" in "[]Point{{1, 2}}".
+type StructLiteral struct {
+	Type   Expr // an identifier, a member of a package, or nil if elided
+	Lbrace *lexing.Token
+	Fields *ExprList
+	Rbrace *lexing.Token
+}
+
+// FuncLiteral is a function literal, like "func(a int) int { return a }"
+type FuncLiteral struct {
+	Type *FuncTypeExpr
+	Body *Block
+}
+
+// KeyValueExpr is a keyed element in a literal, like "k: v"
+type KeyValueExpr struct {
+	Key   Expr
+	Colon *lexing.Token
+	Value Expr
+}
+
+// MapTypeExpr is the type expression of a map, like "map[K]V"
+type MapTypeExpr struct {
+	Kw     *lexing.Token
+	Lbrack *lexing.Token
+	Key    Expr
+	Rbrack *lexing.Token
+	Val    Expr
+}
+
+// MapLiteral is a map literal, like "map[string]int{"a": 1}"
+type MapLiteral struct {
+	Type   *MapTypeExpr
+	Lbrace *lexing.Token
+	Exprs  *ExprList
+	Rbrace *lexing.Token
+}
+
 // FuncTypeExpr is the type expression of a function pointer
 type FuncTypeExpr struct {
 	Kw      *lexing.Token
@@ -29,14 +29,33 @@ func ExprPos(e Expr) *lexing.Pos {
 		return e.Star.Pos
 	case *IndexExpr:
 		return ExprPos(e.Array)
+	case *InstExpr:
+		return ExprPos(e.Generic)
 	case *MemberExpr:
 		return ExprPos(e.Expr)
+	case *TypeAssertExpr:
+		return ExprPos(e.Expr)
 	case *ArrayTypeExpr:
 		return e.Lbrack.Pos
 	case *ArrayLiteral:
 		return ExprPos(e.Type)
+	case *StructLiteral:
+		if e.Type == nil {
+			return e.Lbrace.Pos
+		}
+		return ExprPos(e.Type)
+	case *FuncLiteral:
+		return e.Type.Kw.Pos
+	case *KeyValueExpr:
+		return ExprPos(e.Key)
 	case *FuncTypeExpr:
 		return e.Kw.Pos
+	case *MapTypeExpr:
+		return e.Kw.Pos
+	case *MapLiteral:
+		return e.Type.Kw.Pos
+	case *ChanTypeExpr:
+		return e.Kw.Pos
 	default:
 		panic(fmt.Errorf("invalid expression type: %T", e))
 	}
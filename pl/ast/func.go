@@ -4,12 +4,14 @@ import (
 	"shanhu.io/smlvm/lexing"
 )
 
-// FuncRecv is the receiver of a struct method
+// FuncRecv is the receiver of a struct method. TypeParams names the
+// type parameters of a generic struct.
 type FuncRecv struct {
 	Lparen     *lexing.Token
 	Recv       *lexing.Token
 	Star       *lexing.Token
 	StructName *lexing.Token
+	TypeParams *TypeParamList
 	Rparen     *lexing.Token
 }
 
@@ -21,10 +23,11 @@ type FuncAlias struct {
 	Name *lexing.Token
 }
 
-// Func is a function
+// Func is a function. TypeParams is not nil for a generic function.
 type Func struct {
-	Kw   *lexing.Token
-	Name *lexing.Token
+	Kw         *lexing.Token
+	Name       *lexing.Token
+	TypeParams *TypeParamList
 
 	Recv *FuncRecv
 	*FuncSig
@@ -7,7 +7,8 @@ import (
 // Para is a function parameter
 type Para struct {
 	Ident *lexing.Token
-	Type  Expr // when Type is missing, Ident also might be the type
+	Dots  *lexing.Token // "..." of a variadic parameter, optional
+	Type  Expr          // when Type is missing, Ident also might be the type
 }
 
 // ParaList is a parameter list
@@ -30,6 +31,14 @@ func (lst *ParaList) Named() bool {
 	return false
 }
 
+// Variadic checks if the last parameter is variadic.
+func (lst *ParaList) Variadic() bool {
+	if lst == nil || len(lst.Paras) == 0 {
+		return false
+	}
+	return lst.Paras[len(lst.Paras)-1].Dots != nil
+}
+
 // Len returns the count of parameters
 func (lst *ParaList) Len() int { return len(lst.Paras) }
 
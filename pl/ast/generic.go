@@ -0,0 +1,33 @@
+package ast
+
+import (
+	"shanhu.io/smlvm/lexing"
+)
+
+// TypeParam is a type parameter of a generic function or struct, like
+// "T any". Constraint is nil when the parameter shares the constraint
+// of the next one, like the "K" in "K, V comparable", and is always nil
+// in the type parameters of a method receiver.
+type TypeParam struct {
+	Ident      *lexing.Token
+	Constraint *lexing.Token
+}
+
+// TypeParamList is a list of type parameters, like
+// "[K comparable, V any]".
+type TypeParamList struct {
+	Lbrack *lexing.Token
+	Params []*TypeParam
+	Commas []*lexing.Token
+	Rbrack *lexing.Token
+}
+
+// InstExpr instantiates a generic function or struct with a list of
+// type arguments, like "Pair[int, string]". An instantiation with only
+// one type argument might also be parsed as an IndexExpr.
+type InstExpr struct {
+	Generic Expr
+	Lbrack  *lexing.Token
+	Types   *ExprList
+	Rbrack  *lexing.Token
+}
@@ -6,12 +6,13 @@ import (
 
 // Interface declares a interface
 type Interface struct {
-	Kw     *lexing.Token
-	Name   *lexing.Token
-	Lbrace *lexing.Token
-	Funcs  []*InterfaceFunc
-	Rbrace *lexing.Token
-	Semi   *lexing.Token
+	Kw      *lexing.Token
+	Name    *lexing.Token
+	KwAfter *lexing.Token
+	Lbrace  *lexing.Token
+	Funcs   []*InterfaceFunc
+	Rbrace  *lexing.Token
+	Semi    *lexing.Token
 }
 
 // InterfaceFunc is a func in interface
@@ -70,8 +70,22 @@ type SwitchStmt struct {
 	Semi   *lexing.Token
 }
 
+// TypeSwitchStmt is a type switch like "switch x := v.(type) {}". Var
+// and Define are nil when the value is not bound to a variable.
+type TypeSwitchStmt struct {
+	Kw     *lexing.Token
+	Var    *lexing.Token
+	Define *lexing.Token
+	Expr   *TypeAssertExpr
+	Lbrace *lexing.Token
+	Cases  []*Case
+	Rbrace *lexing.Token
+	Semi   *lexing.Token
+}
+
 // Case is the inset statement block in switch
 // default is included here, Kw will determine it is case or default
+// In a type switch, Expr is the list of types.
 type Case struct {
 	Kw          *lexing.Token
 	Expr        Expr
@@ -88,10 +102,19 @@ type ForStmt struct {
 	Cond      Expr
 	CondSemi  *lexing.Token
 	Iter      Stmt
+	Range     *RangeClause
 	Body      *Block
 	Semi      *lexing.Token
 }
 
+// RangeClause is the range clause of a for loop, like "k, v := range m".
+type RangeClause struct {
+	Left   *ExprList     // optional
+	Assign *lexing.Token // ":=" or "=", optional
+	Kw     *lexing.Token
+	Expr   Expr
+}
+
 // ReturnStmt is a statement of return.
 // return <expr>
 type ReturnStmt struct {
@@ -100,6 +123,14 @@ type ReturnStmt struct {
 	Semi  *lexing.Token
 }
 
+// DeferStmt is a statement that defers a function call.
+// defer <call>
+type DeferStmt struct {
+	Kw   *lexing.Token
+	Call Expr
+	Semi *lexing.Token
+}
+
 // IncStmt is an "i++" or "i--".
 type IncStmt struct {
 	Expr Expr
@@ -115,6 +146,18 @@ type ContinueStmt struct{ Kw, Label, Semi *lexing.Token }
 // break [<label>]
 type BreakStmt struct{ Kw, Label, Semi *lexing.Token }
 
+// GotoStmt is the goto statement
+// goto <label>
+type GotoStmt struct{ Kw, Label, Semi *lexing.Token }
+
+// LabeledStmt is a statement with a label.
+// <label>: <stmt>
+type LabeledStmt struct {
+	Label *lexing.Token
+	Colon *lexing.Token
+	Stmt  Stmt
+}
+
 // FallthroughStmt is the fallthrough statement
 // fallthrough
 type FallthroughStmt struct{ Kw, Semi *lexing.Token }
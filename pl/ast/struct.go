@@ -4,19 +4,22 @@ import (
 	"shanhu.io/smlvm/lexing"
 )
 
-// Field is a member variable of a struct
+// Field is a member variable of a struct. Idents is nil when the
+// field is an embedded field, which is named after its type.
 type Field struct {
 	Idents *IdentList
 	Type   Expr
 	Semi   *lexing.Token
 }
 
-// Struct declares a structure type
+// Struct declares a structure type. TypeParams is not nil for a
+// generic struct.
 type Struct struct {
-	Kw      *lexing.Token
-	Name    *lexing.Token
-	KwAfter *lexing.Token
-	Lbrace  *lexing.Token
+	Kw         *lexing.Token
+	Name       *lexing.Token
+	TypeParams *TypeParamList
+	KwAfter    *lexing.Token
+	Lbrace     *lexing.Token
 
 	Fields []*Field
 
@@ -30,6 +30,7 @@ func (bareFunc) Prepare(*builds.FileSet) (
 ) {
 	ret := builds.NewImportList()
 	ret.Add("$", BuiltInPkg, nil)
+	ret.Add("$runtime", RuntimePkg, nil)
 	return ret, nil
 }
 
@@ -42,6 +43,8 @@ func buildBareFunc(b *builder, stmts []ast.Stmt) []*lexing.Error {
 	b.f = b.p.NewFunc(":start", nil, codegen.VoidFuncSig)
 	b.fretRef = nil
 	b.b = b.f.NewBlock(nil)
+	b.exit = b.f.End()
+	b.labels = newLabels()
 
 	for _, stmt := range tstmts {
 		buildStmt(b, stmt)
@@ -96,6 +96,9 @@ func TestBareFunc_god(t *testing.T) {
 	o("for i:=0; i<5; i++ { printInt(i); i++; continue }", "0\n2\n4")
 	o("i:=0; for i<3 { printInt(i); i=i+1; continue; break }", "0\n1\n2")
 	o("printChar('x')", "x")
+	o("var b byte = 'z'; printInt(int(b))", "122")
+	o("var n int = 'a'; printInt(n)", "97")
+	o("c:='a' + 1; printChar(c)", "b")
 	o("var a=32; var b=*&a; printInt(b)", "32")
 	o("var a=32; var b=&a; var c=*b; printInt(c)", "32")
 	o("var a=32; var b int = *&*&a; printInt(b)", "32")
@@ -154,10 +157,44 @@ func TestBareFunc_god(t *testing.T) {
 	o("printUint(uint(0x80000000) / 10)", "214748364")
 	o("printUint(uint(0x80000000) % 10)", "8")
 	o("a:=uint(214748364); printUint(a*2)", "429496728")
-	o("a:=12e3; printInt(a)", "12000")
-	o("a:=1e-1000; printInt(a)", "0")
-	o("a:=100e-1; printInt(a)", "10")
-	o("a:=1.23e9; printInt(a)", "1230000000")
+	o("var a int = 12e3; printInt(a)", "12000")
+	o("var a int = 1e-1000; printInt(a)", "0")
+	o("var a int = 100e-1; printInt(a)", "10")
+	o("var a uint = 1.23e9; printUint(a)", "1230000000")
+	o("var a byte = 2.0; printInt(int(a))", "2")
+
+	o("printFloat(1.5)", "1.500000")
+	o("printFloat(-0.25)", "-0.250000")
+	o("a:=0.7; printFloat(a)", "0.700000")
+	o("var a float = 3; a = a*0.5 + 0.25; printFloat(a)", "1.750000")
+	o("a:=2.5; a+=1; a*=2; a/=4; a-=0.5; printFloat(a)", "1.250000")
+	o("a:=1.5; printFloat(-a)", "-1.500000")
+	o("const a = 1.5*2 + 1; printFloat(a)", "4.000000")
+	o("printFloat(float(7) / 2)", "3.500000")
+	o("var a float32 = 1.0 / 4; printFloat(a)", "0.250000")
+	o("a:=3.0; printFloat(a / 2)", "1.500000")
+	o("var a float32 = 1e10; printFloat(a / 1e9)", "10.000000")
+	o("const a int = 3.0; printInt(a)", "3")
+	o("printFloat(5e9); printFloat(-5e9)",
+		"5000000000.000000\n-5000000000.000000")
+	o("printFloat(2147483648.0); printFloat(1.5e10)",
+		"2147483648.000000\n15000000512.000000")
+	o("printFloat(3.4028235e38)",
+		"340282346638528859811704183484516925440.000000")
+	o("a:=1.0; z:=0.0; printFloat(a/z); printFloat(-a/z)", "Inf\n-Inf")
+	o("z:=0.0; printFloat(z/z)", "NaN")
+	o("a:=3.7; printInt(int(a)); printInt(int(-a))", "3\n-3")
+	o("a:=byte(200); printFloat(float32(a))", "200.000000")
+	o("a:=uint(4000000000); printFloat(float32(a))", "4000000000.000000")
+	o("a:=uint(7); printFloat(float32(a))", "7.000000")
+	o("a:=4e9; printUint(uint(a)); a=3.5; printUint(uint(a))",
+		"4000000000\n3")
+	o("a:=2147483648.0; printUint(uint(a))", "2147483648")
+	o("a:=0.5; if a < 1 { printInt(1) }; if a >= 0.5 { printInt(2) }",
+		"1\n2")
+	o("a:=0.5; if a == 0.5 { printInt(1) }; if a != 0.5 { printInt(2) }",
+		"1")
+	o("a:=0.5; b:=a; if a <= b && !(a > b) { printInt(1) }", "1")
 
 	o("a:=3; a+=4; printInt(a)", "7")
 	o("a:=3; a-=4; printInt(a)", "-1")
@@ -186,6 +223,14 @@ func TestBareFunc_god(t *testing.T) {
 
 	o("var a []int = nil; printInt(len(a))", "0")
 	o("a := []int{}; printInt(len(a))", "0")
+	o("a := [3]int{1, 2}; printInt(a[1]+a[2])", "2")
+	o("a := [5]int{2: 7, 9}; printInt(a[3]); printInt(len(a))", "9\n5")
+	o("a := []int{3: 7}; printInt(len(a)); printInt(a[3])", "4\n7")
+	o("a := []int8{2: -1, 0: 3}; printInt(int(a[0]+a[2]))", "2")
+	o("b := 3; a := []int{b, b+1}; printInt(a[0]+a[1])", "7")
+	o("b := 3; a := [2]int{1: b}; printInt(a[0]+a[1])", "3")
+	o("a := []bool{true, 2: true}; if !a[1] { printInt(len(a)) }", "3")
+	o("a := [2]float{1, 2.5}; printFloat(a[0]+a[1])", "3.500000")
 	o("var a byte; if a == 0 { printInt(33) }", "33")
 	o("a:=2; switch a { case 2: printInt(33) }", "33")
 	o("a:=2; switch a { case 1: printInt(3); case 2: printInt(4)}", "4")
@@ -254,10 +299,17 @@ func TestBareFunc_bad(t *testing.T) {
 	o("cannotAlloc", "a := int")
 
 	// array literal
-	o("arrayLit.notInteger", "var a=[]bool {true, false}")
-	o("arrayLit.notConstant", "var a=[]int {true, false}")
-	o("expectConstExpr", "var a int; var b=[]int {a}") // error returned from operand.go
+	o("cannotAssign.typeMismatch", "var a=[]int {true, false}")
 	o("arrayLit.outOfRange", "var a = []int8{256}")
+	o("arrayLit.outOfRange", "var a = [2]int{1, 2, 3}")
+	o("arrayLit.outOfRange", "var a = [2]int{2: 1}")
+	o("arrayLit.duplicateIndex", "var a = []int{1, 0: 2}")
+	o("arrayLit.illegalIndex", "var a = []int{-1: 2}")
+	o("arrayLit.illegalIndex", "var a = []int{true: 2}")
+	o("arrayType.dotsLen", "var a [...]int; _:=a")
+	o("compositeLit.missingType", "var a = []int{{1}}")
+	o("compositeLit.missingType", "var a = []*[]int{{1}}")
+	o("expectConstExpr", "b := 1; var a = []int{b: 2}")
 
 	// pointer
 	o("cannotCast", "var a int; var b = &a+3")
@@ -310,15 +362,23 @@ func TestBareFunc_bad(t *testing.T) {
 
 	// build op
 	o("cannotCast.integerOverFlowed", "a:=12345678987654321")
-	o("cannotCast.integerOverFlowed", "a:=1.2e10")
+	o("cannotCast.floatOverFlowed", "a:=1e40")
 	o("cannotCast.invalidInteger", "a:=19223372036854775808") // too large for int64
 	o("cannotCast.invalidFloat", "a:=10e")
 	o("cannotCast.invalidFloat", "a:=0.2e-")
 
+	// floats
+	o("cannotAssign.typeMismatch", "var a int8 = '\\xff'; _:=a")
+	o("cannotAssign.typeMismatch", "var a int = 1.5; _:=a")
+	o("cannotAssign.typeMismatch", "var a int = 1.2e10; _:=a")
+	o("cannotAssign.typeMismatch", "a:=3.0; var b int = a; _:=b")
+	o("invalidOp", "a:=1.5; b:=a%2.5; _:=b")
+	o("invalidOp", "a:=1.5 % 2; _:=a")
+	o("invalidOp", "a:=3 % 2.0; _:=a")
+	o("invalidOp", "a:=1.5; b:=3; c:=a+b; _:=c")
+	o("divideByZero", "a:=1.5/0")
+
 	// not yet supported
-	o("notYetSupported", "a:=10e-2")
-	o("notYetSupported", "a:=1.2")
-	o("notYetSupported", "var a=[1]int {1}")
 
 	o("expectType", "var a int; var b a")
 
@@ -327,6 +387,11 @@ func TestBareFunc_bad(t *testing.T) {
 	o("caseExpr.notConst", `a:=2; switch a {case a:}`)
 	o("caseExpr.dulplicated", `a:=3; switch a {case 1 :; case 1:}`)
 	o("cannotCast", `a:=int8(1); switch a {case 256 :}`)
+	o("caseExpr.dulplicated", "a:='a'; switch a {case 'a':; case 97:}")
+	o("caseExpr.dulplicated", "a:=3; switch a {case 'a':; case 97:}")
+	o("caseExpr.dulplicated", `a:="x"; switch a {case "x":; case "x":}`)
+	o("caseExpr.notConst", `a:="x"; switch a {case a:}`)
+	o("caseExpr.notConst", `a:="x"; switch a {case 1:}`)
 
 	// const
 	o("missingConstDefine", "const a; _:=a")
@@ -334,6 +399,11 @@ func TestBareFunc_bad(t *testing.T) {
 	o("missingConstDefine", "const a int; _:=a")
 	o("cannotAssign.typeMismatch", "const a uint = 33; b:=a;printInt(b)")
 	o("invalidOp", "const a uint = 33; b:=2;printInt(a+b)")
+	o("invalidOp.typeMismatch", `const a = "a" + 1`)
+	o("invalidOp", `const a = -"a"`)
+	o("invalidOp", `const a = "a" * "b"`)
+	o("invalidOp", `a := "a" - "b"; _ := a`)
+	o("expectConstExpr", `s := "a"; const a = len(s)`)
 
 }
 
@@ -5,8 +5,9 @@ import (
 )
 
 type blockLayer struct {
-	name string
-	b    *codegen.Block
+	name      string
+	b         *codegen.Block
+	labelOnly bool // skipped by top()
 }
 
 type blockStack struct {
@@ -21,11 +22,20 @@ func newBlockStack() *blockStack {
 }
 
 func (s *blockStack) push(b *codegen.Block, name string) bool {
+	return s.pushLayer(&blockLayer{name: name, b: b})
+}
+
+// pushLabelOnly pushes a layer that can only be found by its name.
+func (s *blockStack) pushLabelOnly(b *codegen.Block, name string) bool {
+	return s.pushLayer(&blockLayer{name: name, b: b, labelOnly: true})
+}
+
+func (s *blockStack) pushLayer(layer *blockLayer) bool {
+	name := layer.name
 	if name != "" && s.bmap[name] != nil {
 		return false
 	}
 
-	layer := &blockLayer{name, b}
 	s.bs = append(s.bs, layer)
 	if name != "" {
 		s.bmap[name] = layer
@@ -43,12 +53,12 @@ func (s *blockStack) pop() {
 }
 
 func (s *blockStack) top() *codegen.Block {
-	nlayer := len(s.bs)
-	if nlayer == 0 {
-		return nil
+	for i := len(s.bs) - 1; i >= 0; i-- {
+		if layer := s.bs[i]; !layer.labelOnly {
+			return layer.b
+		}
 	}
-	ret := s.bs[nlayer-1]
-	return ret.b
+	return nil
 }
 
 func (s *blockStack) byName(name string) *codegen.Block {
@@ -20,35 +20,37 @@ type builder struct {
 	f *codegen.Func
 	b *codegen.Block
 
-	panicFunc codegen.Ref // for calling panic
-	fretRef   *ref        // to store return value
-	this      *ref        // not nil when building a method
+	panicFunc codegen.Ref   // for calling panic
+	rt        *runtimeFuncs // nil when building the runtime
+	runtime   bool          // building the runtime
+	golike    bool          // unlabeled breaks break switches and selects
+	fretRef   *ref          // to store return value
+	this      *ref          // not nil when building a method
+
+	exit      *codegen.Block // where the returns jump to
+	deferMark codegen.Ref    // the top of the defer stack on entering
+
+	// the variables of the function that are allocated in the heap
+	heapVars map[*syms.Symbol]bool
 
 	continues *blockStack
 	breaks    *blockStack
+	labels    *labels
 
 	exprFunc func(b *builder, expr tast.Expr) *ref
 	stmtFunc func(b *builder, stmt tast.Stmt)
 
-	anonyCount int // count for "_"
+	anonyCount   int // count for "_"
+	funcLitCount int // count for function literals
+	deferCount   int // count for deferred calls
+	goCount      int // count for go calls
 
-	vTableMap map[*types.Interface]*vTable
-}
+	vTableMap   map[*types.Interface]*vTable
+	structDescs map[types.T]codegen.Ref
+	typeDescs   map[string]codegen.Ref // types for the allocator
 
-func (b *builder) newImplement(i *types.Interface, r *ref, s *types.Struct) {
-	t := b.vTableMap[i]
-	if t == nil {
-		t = newTable(i)
-		b.vTableMap[i] = t
-	}
-	if t.implementMap[s] == nil {
-		slice := make([]*syms.Symbol, len(t.funcs))
-		for i, funcName := range t.funcs {
-			// check how to change []*Symbol to []*ref
-			slice[i] = s.Syms.Query(funcName)
-		}
-		t.implementMap[s] = slice
-	}
+	// the values received by the select cases that are being built
+	selected map[*tast.ChanRecv]*ref
 }
 
 func newBuilder(path string) *builder {
@@ -62,7 +64,10 @@ func newBuilder(path string) *builder {
 		continues: newBlockStack(),
 		breaks:    newBlockStack(),
 
-		vTableMap: make(map[*types.Interface]*vTable),
+		vTableMap:   make(map[*types.Interface]*vTable),
+		structDescs: make(map[types.T]codegen.Ref),
+		typeDescs:   make(map[string]codegen.Ref),
+		selected:    make(map[*tast.ChanRecv]*ref),
 	}
 }
 
@@ -75,27 +80,49 @@ func (b *builder) anonyName(name string) string {
 }
 
 func (b *builder) newTempIR(t types.T) codegen.Ref {
-	return b.f.NewTemp(t.Size(), types.IsByte(t), t.RegSizeAlign())
+	ret := b.f.NewTemp(t.Size(), types.IsByte(t), t.RegSizeAlign())
+	b.markPtrs(ret, t)
+	return ret
 }
 
 func (b *builder) newTemp(t types.T) *ref { return newRef(t, b.newTempIR(t)) }
 
 func (b *builder) newCond() codegen.Ref { return b.f.NewTemp(1, true, false) }
-func (b *builder) newPtr() codegen.Ref  { return b.f.NewTemp(4, true, true) }
+
+func (b *builder) newPtr() codegen.Ref {
+	ret := b.f.NewTemp(4, true, true)
+	ret.(*codegen.Var).Ptrs = []int32{0} // addresses might be in the heap
+	return ret
+}
 
 func (b *builder) newAddressableTemp(t types.T) *ref {
 	return newAddressableRef(t, b.newTempIR(t))
 }
 
 func (b *builder) newLocal(t types.T, name string) codegen.Ref {
-	return b.f.NewLocal(t.Size(), name,
+	ret := b.f.NewLocal(t.Size(), name,
 		types.IsByte(t), t.RegSizeAlign(),
 	)
+	b.markPtrs(ret, t)
+	return ret
+}
+
+// newVar creates local variable s of type t. A variable that is captured
+// by an escaping function literal is allocated in the heap.
+func (b *builder) newVar(s *syms.Symbol, t types.T) *ref {
+	if !b.heapVars[s] || b.rt == nil {
+		return newAddressableRef(t, b.newLocal(t, s.Name()))
+	}
+	return newHeapVar(b, t, s.Name())
 }
 
 func (b *builder) newGlobalVar(t types.T, name string) codegen.Ref {
 	name = b.anonyName(name)
-	return b.p.NewGlobalVar(t.Size(), name, types.IsByte(t), t.RegSizeAlign())
+	ret := b.p.NewGlobalVar(
+		t.Size(), name, types.IsByte(t), t.RegSizeAlign(),
+	)
+	ret.(*codegen.HeapSym).Ptrs = ptrs(t, false)
+	return ret
 }
 
 func (b *builder) buildExpr(expr tast.Expr) *ref {
@@ -48,6 +48,7 @@ func declareBuiltin(b *builder, builtin *link.Pkg) {
 	// compiler.
 	o("PrintInt32", "printInt", types.NewVoidFunc(types.Int))
 	o("PrintUint32", "printUint", types.NewVoidFunc(types.Uint))
+	o("PrintFloat32", "printFloat", types.NewVoidFunc(types.Float32))
 	o("PrintChar", "printChar", types.NewVoidFunc(types.Int8))
 	b.panicFunc = o("Panic", "panic", types.VoidFunc)
 	o("IOCall", "iocall", types.VoidFunc)
@@ -65,6 +66,10 @@ func declareBuiltin(b *builder, builtin *link.Pkg) {
 
 	bi("len")
 	bi("make")
+	bi("delete")
+	bi("new")
+	bi("append")
+	bi("close")
 
 	c := func(name string, r *ref) {
 		// TODO: declare these as typed consts
@@ -98,16 +103,16 @@ func declareBuiltin(b *builder, builtin *link.Pkg) {
 	t("byte", types.Uint8)
 	t("bool", types.Bool)
 	t("float", types.Float32)
+	t("float32", types.Float32)
 	t("string", types.String)
 	t("uintptr", types.Uint)
-	// t("float32", types.Float32)
 }
 
 func isBasicType(t string) bool {
 	switch t {
 	case "int", "uint", "int32", "uint32",
 		"int8", "uint8", "char", "byte",
-		"bool", "float", "string":
+		"bool", "float", "float32", "string":
 		return true
 	}
 	return false
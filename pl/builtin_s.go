@@ -30,9 +30,8 @@ func PrintChar {
 	mov pc ret
 }
 
-// Print a 32-bit unsigned integer
-// when array is implemented, this will be rewritten in glang
-func PrintUint32 {
+// Print a 32-bit unsigned integer without the end line
+func printUint32 {
 	// saving used registers
 	sw ret sp -4
 	addi sp sp -28
@@ -70,15 +69,26 @@ func PrintUint32 {
     j .printloop
 
 .end
-	addi r1 r0 0xa
-	jal PrintChar // print a end line
-
 	lw r2 sp 4
 	lw r3 sp 8
 	addi sp sp 28
 	lw pc sp -4
 }
 
+// Print a 32-bit unsigned integer
+// when array is implemented, this will be rewritten in glang
+func PrintUint32 {
+	sw ret sp -4
+	addi sp sp -4
+
+	jal printUint32
+	addi r1 r0 0xa
+	jal PrintChar // print a end line
+
+	addi sp sp 4
+	lw pc sp -4
+}
+
 // Print a 32-bit signed integer
 // when array is implemented, this will be rewritten in glang
 func PrintInt32 {
@@ -106,6 +116,175 @@ func PrintInt32 {
 	lw pc sp -4
 }
 
+// Print a 32-bit float with 6 digits after the decimal point
+func PrintFloat32 {
+	// saving used registers
+	sw ret sp -4
+	addi sp sp -40
+	sw r1 sp
+	sw r2 sp 4
+	sw r3 sp 8
+
+	sll r2 r1 1
+	srl r2 r2 1 // r2 = |x|
+	ori r3 r0 0x7f80
+	sll r3 r3 16 // r3 = +Inf
+	sltu r3 r3 r2
+	bne r3 r0 .nan
+
+	slt r2 r1 r0 // r2 = sign bit
+	beq r2 r0 .skipsign
+
+	addi r1 r0 0x2d // '-'
+	jal PrintChar
+
+	lw r1 sp
+	fabs r1 r1
+.skipsign
+	ori r3 r0 0x7f80
+	sll r3 r3 16
+	beq r1 r3 .inf
+	ori r3 r0 0x4f00
+	sll r3 r3 16 // r3 = 2^31
+	sltu r3 r1 r3
+	beq r3 r0 .big
+
+	// r2 = integer part, r3 = rounded fraction in millionths
+	fint r2 r1
+	fflt r3 r2
+	fsub r3 r1 r3
+	ori r4 r0 1000
+	mul r4 r4 r4
+	fflt r1 r4
+	fmul r3 r3 r1
+	ori r1 r0 2
+	fflt r1 r1
+	ori r4 r0 1
+	fflt r4 r4
+	fdiv r1 r4 r1 // 0.5
+	fadd r3 r3 r1
+	fint r3 r3
+
+	ori r4 r0 1000
+	mul r4 r4 r4
+	bne r3 r4 .nocarry
+	addi r2 r2 1
+	mov r3 r0
+.nocarry
+	mov r1 r2
+	jal printUint32
+.frac
+	addi r1 r0 0x2e // '.'
+	jal PrintChar
+
+	ori r2 r0 1000
+	ori r4 r0 100
+	mul r2 r2 r4 // 100000
+	ori r4 r0 10
+.fracloop
+	divu r1 r3 r2
+	modu r3 r3 r2
+	addi r1 r1 0x30
+	jal PrintChar
+	divu r2 r2 r4
+	bne r2 r0 .fracloop
+
+.endline
+	addi r1 r0 0xa
+	jal PrintChar // print a end line
+
+	lw r2 sp 4
+	lw r3 sp 8
+	addi sp sp 40
+	lw pc sp -4
+
+.nan
+	addi r1 r0 0x4e // 'N'
+	jal PrintChar
+	addi r1 r0 0x61 // 'a'
+	jal PrintChar
+	addi r1 r0 0x4e // 'N'
+	jal PrintChar
+	j .endline
+
+.inf
+	addi r1 r0 0x49 // 'I'
+	jal PrintChar
+	addi r1 r0 0x6e // 'n'
+	jal PrintChar
+	addi r1 r0 0x66 // 'f'
+	jal PrintChar
+	j .endline
+
+.big
+	// |x| >= 2^31 is an integer m * 2^e, where m has 24 bits and e > 0,
+	// it is printed exactly with five base 1e9 limbs on the stack
+	sll r2 r1 9
+	srl r2 r2 9
+	ori r3 r0 0x80
+	sll r3 r3 16
+	or r2 r2 r3 // r2 = m
+	sw r2 sp 16
+	sw r0 sp 20
+	sw r0 sp 24
+	sw r0 sp 28
+	sw r0 sp 32
+	srl r1 r1 23
+	addi r1 r1 -150 // r1 = e
+	sw r1 sp 12
+	ori r2 r0 0x3b9a
+	sll r2 r2 16
+	ori r2 r2 0xca00 // r2 = 1e9
+.double
+	addi r3 sp 16
+	mov r1 r0 // carry
+.limb
+	lw r4 r3
+	add r4 r4 r4
+	add r4 r4 r1
+	divu r1 r4 r2
+	modu r4 r4 r2
+	sw r4 r3
+	addi r3 r3 4
+	addi r4 sp 36
+	bne r3 r4 .limb
+	lw r4 sp 12
+	addi r4 r4 -1
+	sw r4 sp 12
+	bne r4 r0 .double
+
+	addi r3 sp 32
+.skip
+	lw r1 r3
+	bne r1 r0 .top
+	addi r3 r3 -4
+	j .skip
+.top
+	jal printUint32
+.limbs
+	addi r4 sp 16
+	beq r3 r4 .zerofrac
+	addi r3 r3 -4
+	sw r3 sp 12
+	lw r4 r3
+	ori r2 r0 0x05f5
+	sll r2 r2 16
+	ori r2 r2 0xe100 // r2 = 1e8
+	ori r3 r0 10
+.digits
+	divu r1 r4 r2
+	modu r4 r4 r2
+	addi r1 r1 0x30
+	jal PrintChar
+	divu r2 r2 r3
+	bne r2 r0 .digits
+	lw r3 sp 12
+	j .limbs
+.zerofrac
+	mov r3 r0
+	j .frac
+}
+
 // Panic halts the system immediately with panic exception
 func Panic {
 	panic
@@ -190,4 +369,55 @@ func MemClear {
 .ret
 	mov pc ret
 }
+
+// CallerFrame returns the frame of the caller
+//   r1 - the stack pointer of the caller
+//   r2 - the return address into the caller
+func CallerFrame {
+	mov r1 sp
+	mov r2 ret
+	mov pc ret
+}
+
+// CoreID returns the index of the current core
+//   r1 - the index of the core
+func CoreID {
+	sw r2 sp -4
+	addi r1 r0 1 // CPUID
+	sysinfo r1 r2
+	lw r2 sp -4
+	mov pc ret
+}
+
+// SwitchThread saves the context of the current thread, and switches to
+// another thread. A context is the stack pointer, the program counter
+// and r3. The new thread starts with a zero return address.
+//   r1 - where to save the context of the current thread
+//   r2 - the context of the thread to switch to
+func SwitchThread {
+	sw sp r1
+	sw ret r1 4
+	sw r3 r1 8
+
+	lw sp r2
+	lw r3 r2 8
+	lw r4 r2 4
+	mov ret r0
+	mov pc r4
+}
+
+// TimerHandler handles the timer interrupts for preempting threads. It
+// sets the word whose address is saved at the base of the handler stack.
+func TimerHandler {
+	sw r1 sp -20
+	sw r2 sp -24
+
+	lw r1 sp
+	addi r2 r0 1
+	sw r2 r1
+
+	lw r1 sp -20
+	lw r2 sp -24
+	iret
+}
 `
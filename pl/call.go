@@ -10,7 +10,7 @@ func buildCallLen(b *builder, expr *tast.CallExpr) *ref {
 	args := b.buildExpr(expr.Args)
 	t := args.Type()
 	ret := b.newTemp(types.Int)
-	switch t := t.(type) {
+	switch t := types.Underlying(t).(type) {
 	case *types.Slice:
 		addr := b.newPtr()
 		b.b.Arith(addr, nil, "&", args.IR())
@@ -19,6 +19,12 @@ func buildCallLen(b *builder, expr *tast.CallExpr) *ref {
 	case *types.Array:
 		b.b.Assign(ret.IR(), codegen.Num(uint32(t.N)))
 		return ret
+	case *types.Map:
+		b.b.Call([]codegen.Ref{ret.IR()}, b.rt.mapLen, args.IR())
+		return ret
+	case *types.Chan:
+		b.b.Call([]codegen.Ref{ret.IR()}, b.rt.chanLen, args.IR())
+		return ret
 	}
 	panic("bug")
 }
@@ -26,7 +32,24 @@ func buildCallLen(b *builder, expr *tast.CallExpr) *ref {
 func buildCallMake(b *builder, expr *tast.CallExpr) *ref {
 	args := b.buildExpr(expr.Args)
 	arg0 := args.At(0)
+	t0 := arg0.Type().(*types.Type).T
+	if t, ok := types.Underlying(t0).(*types.Chan); ok {
+		var n *ref
+		if args.Len() > 1 {
+			n = args.At(1)
+		}
+		return buildMakeChan(b, t, n)
+	}
+	if t, ok := t0.(*types.Map); ok {
+		if args.Len() > 1 {
+			checkArrayIndex(b, args.At(1))
+		}
+		return newMap(b, t)
+	}
 	t := arg0.Type().(*types.Type).T.(*types.Slice)
+	if args.Len() == 2 {
+		return buildMakeSlice(b, t, args.At(1))
+	}
 	size := checkArrayIndex(b, args.At(1))
 	start := args.At(2).IR()
 	nilPointerPanic(b, start)
@@ -42,6 +65,14 @@ func buildCallExpr(b *builder, expr *tast.CallExpr) *ref {
 			return buildCallLen(b, expr)
 		case "make":
 			return buildCallMake(b, expr)
+		case "delete":
+			return buildCallDelete(b, expr)
+		case "new":
+			return buildCallNew(b, expr)
+		case "append":
+			return buildCallAppend(b, expr)
+		case "close":
+			return buildCallClose(b, expr)
 		}
 		panic("bug")
 	}
@@ -76,6 +107,8 @@ func wrapFuncPtr(f codegen.Ref, t *types.Func) codegen.Ref {
 		return f
 	case *codegen.Func:
 		return f
+	case *codegen.FuncVal:
+		return f.F
 	}
 	return codegen.NewFuncPtr(makeFuncSig(t), f)
 }
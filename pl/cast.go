@@ -1,6 +1,8 @@
 package pl
 
 import (
+	"math"
+
 	"shanhu.io/smlvm/arch"
 	"shanhu.io/smlvm/pl/codegen"
 	"shanhu.io/smlvm/pl/tast"
@@ -36,12 +38,19 @@ func constNumIr(v int64, t types.T) codegen.Ref {
 			return codegen.Byt(uint8(v), false)
 		case types.Uint8:
 			return codegen.Byt(uint8(v), true)
+		case types.Float32:
+			return constFloatIr(float64(v))
 		}
 	}
 	panic("expect an integer type")
 }
 
+func constFloatIr(v float64) codegen.Ref {
+	return codegen.Num(math.Float32bits(float32(v)))
+}
+
 func buildCast(b *builder, from *ref, t types.T) *ref {
+	t = types.Underlying(t)
 	srcType := from.Type()
 	ret := b.newTemp(t)
 
@@ -50,11 +59,9 @@ func buildCast(b *builder, from *ref, t types.T) *ref {
 		if size == arch.RegSize {
 			return newRef(t, codegen.Num(0))
 		}
-		if _, ok := t.(*types.Interface); ok {
-			b.CodeErrorf(nil, "pl.notYetSupported", "interface not supported")
-			return from
-		}
-		if _, ok := t.(*types.Slice); !ok {
+		switch t.(type) {
+		case *types.Slice, *types.Interface:
+		default:
 			panic("unknown type")
 		}
 		ret := b.newTemp(t)
@@ -64,10 +71,9 @@ func buildCast(b *builder, from *ref, t types.T) *ref {
 
 	if i, ok := t.(*types.Interface); ok {
 		if p, ok := from.Type().(*types.Pointer); ok {
-			s := types.PointerOf(p).(*types.Struct)
-			b.newImplement(i, from, s)
-			b.CodeErrorf(nil, "pl.notYetSupported", "under construction")
-			return from
+			impl := b.newImplement(i, p.T)
+			buildInterface(b, ret.IR(), from.IR(), impl)
+			return ret
 		}
 		b.CodeErrorf(nil, "pl.notYetSupported",
 			"define interface by another interface is not supported yet")
@@ -78,11 +84,34 @@ func buildCast(b *builder, from *ref, t types.T) *ref {
 		if v, ok := types.NumConst(srcType); ok {
 			return newRef(t, constNumIr(v, t))
 		}
+		if v, ok := c.Value.(float64); ok {
+			if types.IsInteger(t) {
+				return newRef(t, constNumIr(int64(v), t))
+			}
+			return newRef(t, constFloatIr(v))
+		}
 		// now only const int is supported
 		return newRef(t, constNumIr(c.Value.(int64), t))
 
 	}
 
+	if types.SameType(t, srcType) {
+		return from
+	}
+	if types.IsBasic(t, types.Float32) && types.IsInteger(srcType) {
+		if types.IsBasic(srcType, types.Uint) {
+			return buildUintToFloat(b, from)
+		}
+		b.b.Arith(ret.IR(), nil, "fflt", from.IR())
+		return ret
+	}
+	if types.IsInteger(t) && types.IsBasic(srcType, types.Float32) {
+		if types.IsBasic(t, types.Uint) {
+			return buildFloatToUint(b, from)
+		}
+		b.b.Arith(ret.IR(), nil, "fint", from.IR())
+		return ret
+	}
 	if types.IsInteger(t) && types.IsInteger(srcType) {
 		b.b.Arith(ret.IR(), nil, "cast", from.IR())
 		return ret
@@ -94,6 +123,41 @@ func buildCast(b *builder, from *ref, t types.T) *ref {
 	panic("cast bug")
 }
 
+// fflt and fint only work on signed integers, so an unsigned value with
+// the highest bit set is converted with the highest bit taken apart.
+var floatHighBit = constFloatIr(1 << 31)
+
+func buildUintToFloat(b *builder, from *ref) *ref {
+	lo := b.newTemp(types.Uint)
+	b.b.Arith(lo.IR(), from.IR(), "&", codegen.Num(0x7fffffff))
+	hi := b.newTemp(types.Uint)
+	b.b.Arith(hi.IR(), from.IR(), "u>>", codegen.Num(31))
+
+	ret := b.newTemp(types.Float32)
+	b.b.Arith(ret.IR(), nil, "fflt", lo.IR())
+	high := b.newTemp(types.Float32)
+	b.b.Arith(high.IR(), nil, "fflt", hi.IR())
+	b.b.Arith(high.IR(), high.IR(), "f*", floatHighBit)
+	b.b.Arith(ret.IR(), ret.IR(), "f+", high.IR())
+	return ret
+}
+
+func buildFloatToUint(b *builder, from *ref) *ref {
+	hi := b.newTemp(types.Uint)
+	b.b.Arith(hi.IR(), from.IR(), "f>=", floatHighBit)
+	high := b.newTemp(types.Float32)
+	b.b.Arith(high.IR(), nil, "fflt", hi.IR())
+	b.b.Arith(high.IR(), high.IR(), "f*", floatHighBit)
+	low := b.newTemp(types.Float32)
+	b.b.Arith(low.IR(), from.IR(), "f-", high.IR())
+
+	ret := b.newTemp(types.Uint)
+	b.b.Arith(ret.IR(), nil, "fint", low.IR())
+	b.b.Arith(hi.IR(), hi.IR(), "<<", codegen.Num(31))
+	b.b.Arith(ret.IR(), ret.IR(), "|", hi.IR())
+	return ret
+}
+
 func buildCasts(b *builder, from *ref, to *tast.Ref, mask []bool) *ref {
 	var ret *ref
 	for i := 0; i < from.Len(); i++ {
@@ -106,3 +170,17 @@ func buildCasts(b *builder, from *ref, to *tast.Ref, mask []bool) *ref {
 	}
 	return ret
 }
+
+// buildInterface assigns an interface value with the receiver pointer
+// and the vtable pointer.
+func buildInterface(b *builder, dest, recv, vtable codegen.Ref) {
+	addr := b.newPtr()
+	b.b.Arith(addr, nil, "&", dest)
+	b.b.Assign(codegen.NewAddrRef(addr, arch.RegSize, 0, false, true), recv)
+	tab := b.newPtr()
+	b.b.Arith(tab, nil, "&", vtable)
+	b.b.Assign(
+		codegen.NewAddrRef(addr, arch.RegSize, arch.RegSize, false, true),
+		tab,
+	)
+}
@@ -0,0 +1,134 @@
+package pl
+
+import (
+	"fmt"
+
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+func chanType(r *ref) *types.Chan {
+	return types.Underlying(r.Type()).(*types.Chan)
+}
+
+func buildMakeChan(b *builder, t *types.Chan, n *ref) *ref {
+	size := codegen.Ref(codegen.Num(0))
+	if n != nil {
+		size = checkArrayIndex(b, n)
+		slot := (t.T.Size() + 3) / 4 * 4
+		if slot > 1 {
+			// panics when the size of the buffer overflows
+			max := codegen.Num(^uint32(0) / uint32(slot))
+			checkInRange(b, size, max, "u<=")
+		}
+	}
+
+	ret := b.newTemp(t)
+	elemSize := codegen.Num(uint32(t.T.Size()))
+	b.b.Call([]codegen.Ref{ret.IR()}, b.rt.chanMake,
+		elemSize, b.typeDesc(t.T), size,
+	)
+	return ret
+}
+
+// refAddr returns the address of a value in a temp.
+func refAddr(b *builder, r *ref) codegen.Ref {
+	ret := b.newPtr()
+	b.b.Arith(ret, nil, "&", r.IR())
+	return ret
+}
+
+func buildSendStmt(b *builder, stmt *tast.SendStmt) {
+	ch := b.buildExpr(stmt.Chan)
+	addr := mapKeyAddr(b, b.buildExpr(stmt.Val))
+	b.b.Call(nil, b.rt.chanSend, ch.IR(), addr)
+}
+
+func buildChanRecv(b *builder, expr *tast.ChanRecv) *ref {
+	if ret, ok := b.selected[expr]; ok {
+		return ret // received by a select
+	}
+
+	ch := b.buildExpr(expr.Chan)
+	ret := b.newTemp(chanType(ch).T)
+	ok := b.newTemp(types.Bool)
+	addr := refAddr(b, ret)
+	b.b.Call([]codegen.Ref{ok.IR()}, b.rt.chanRecv, ch.IR(), addr)
+	if expr.CommaOk {
+		return appendRef(ret, ok)
+	}
+	return ret
+}
+
+func buildCallClose(b *builder, expr *tast.CallExpr) *ref {
+	ch := b.buildExpr(expr.Args)
+	b.b.Call(nil, b.rt.chanClose, ch.IR())
+	return new(ref)
+}
+
+func buildRangeChan(b *builder, stmt *tast.RangeStmt, x *ref) {
+	t := chanType(x)
+	ch := b.newTempIR(t)
+	b.b.Assign(ch, x.IR())
+	v := b.newTemp(t.T)
+	addr := refAddr(b, v)
+
+	condBlock := b.f.NewBlock(b.b)
+	body := b.f.NewBlock(condBlock)
+	next := b.f.NewBlock(body)
+	after := b.f.NewBlock(next)
+	next.Jump(condBlock)
+
+	b.b = condBlock
+	ok := b.newCond()
+	b.b.Call([]codegen.Ref{ok}, b.rt.chanRecv, ch, addr)
+	b.b.JumpIfNot(ok, after)
+
+	b.b = body
+	if stmt.Key != nil {
+		k := b.buildExpr(stmt.Key)
+		b.b.Assign(k.IR(), v.IR())
+	}
+
+	buildRangeBody(b, stmt, next, after)
+}
+
+func buildGoStmt(b *builder, stmt *tast.GoStmt) {
+	if b.rt == nil {
+		b.CodeErrorf(stmt.Kw.Pos, "pl.go.noRuntime",
+			"go is not supported without the runtime")
+		return
+	}
+
+	f := b.buildExpr(stmt.Call.Func)
+	args := b.buildExpr(stmt.Call.Args)
+	vals, size := deferVals(f, args)
+
+	name := fmt.Sprintf(":go_%d", b.goCount)
+	b.goCount++
+	call := buildDeferCall(b, name, f, vals)
+
+	rec := callRuntime(b, b.rt.goRecord,
+		codegen.Num(uint32(size)), b.p.NewFuncVal(call),
+	)
+	for _, v := range vals {
+		b.b.Assign(elemAt(rec, v.r.Type(), v.offset), v.r.IR())
+	}
+	b.b.Call(nil, b.rt.goStart, rec)
+}
+
+// buildPreempt yields to the other threads when the running thread is
+// asked to. It is checked at the start of each iteration of a loop.
+func buildPreempt(b *builder) {
+	if b.rt == nil {
+		return
+	}
+	c := b.newCond()
+	b.b.Arith(c, nil, "?", b.rt.preempt)
+	yield := b.f.NewBlock(b.b)
+	after := b.f.NewBlock(yield)
+	b.b.JumpIfNot(c, after)
+	yield.Call(nil, b.rt.yield)
+	b.b = after
+}
@@ -0,0 +1,174 @@
+package pl
+
+import (
+	"strings"
+	"testing"
+
+	"shanhu.io/smlvm/arch"
+)
+
+func TestChan(t *testing.T) {
+	const N = 50000000
+
+	o := func(input, output string) {
+		for _, ncore := range []int{1, 2, 4} {
+			c := &arch.Config{Ncore: ncore}
+			out, err := singleTestRun(t, input, c, N)
+			if err == errRunFailed {
+				t.Errorf("run failed on %d cores", ncore)
+				return
+			}
+			if !arch.IsHalt(err) {
+				t.Log(input)
+				t.Log(err)
+				t.Errorf("did not halt gracefully on %d cores", ncore)
+				return
+			}
+
+			got := strings.TrimSpace(out)
+			expect := strings.TrimSpace(output)
+			if got != expect {
+				t.Log(input)
+				t.Logf("expect: %s", expect)
+				t.Errorf("got on %d cores: %s", ncore, got)
+				return
+			}
+		}
+	}
+
+	o(`	func worker(c chan int, n int) {
+			for i := 0; i < n; i++ { c <- i }
+			close(c)
+		}
+		func main() {
+			c := make(chan int)
+			go worker(c, 3)
+			for v := range c { printInt(v) }
+		}`,
+		"0\n1\n2",
+	)
+	o(`	func main() {
+			b := make(chan string, 2)
+			b <- "hi"; b <- "abc"
+			printInt(len(b))
+			s := <-b; printInt(len(s))
+			s = <-b; printInt(len(s))
+			close(b)
+			v, ok := <-b
+			if !ok { printInt(len(v)) }
+		}`,
+		"2\n2\n3\n0",
+	)
+	o(`	func main() {
+			done := make(chan bool)
+			go func() { printInt(1); done <- true }()
+			<-done
+			printInt(2)
+		}`,
+		"1\n2",
+	)
+	o(`	struct pair { a, b int }
+		func main() {
+			c := make(chan pair, 1)
+			c <- pair{3, 4}
+			p := <-c
+			printInt(p.a + p.b)
+		}`,
+		"7",
+	)
+	o(`	func main() {
+			a := make(chan int); q := make(chan int)
+			go func() {
+				for i := 0; i < 3; i++ { a <- i }
+				close(q)
+			}()
+			for {
+				select {
+				case v := <-a:
+					printInt(v)
+					continue
+				case _, ok := <-q:
+					if !ok { printInt(100) }
+				}
+				break
+			}
+		}`,
+		"0\n1\n2\n100",
+	)
+	o(`	func main() {
+			c := make(chan int, 1)
+			select {
+			case c <- 5: printInt(1)
+			default: printInt(2)
+			}
+			select {
+			case c <- 6: printInt(1)
+			default: printInt(2)
+			}
+			var x int
+			select { case x = <-c: }
+			printInt(x)
+		}`,
+		"1\n2\n5",
+	)
+
+	// the spawners return before the threads run
+	o(`	func spawn(out chan int) {
+			x := 41
+			go func() {
+				for i := 0; i < 100; i++ {}
+				out <- x + 1
+			}()
+		}
+		func spawn2(out chan int) {
+			y := 5
+			f := func() { out <- y * 2 }
+			go f()
+			y++
+		}
+		func g(n int) int { var a [40]int; a[3] = n; return a[3] }
+		func main() {
+			c := make(chan int)
+			spawn(c)
+			spawn2(c)
+			g(1)
+			a := <-c
+			printInt(a + <-c)
+		}`,
+		"54",
+	)
+
+	// busy threads are preempted
+	o(`	func spin(c chan int) {
+			n := 0
+			for i := 0; i < 20000; i++ { n++ }
+			c <- n
+		}
+		func main() {
+			r := make(chan int)
+			go spin(r); go spin(r)
+			printInt(<-r + <-r)
+		}`,
+		"40000",
+	)
+
+	// many threads that make garbage
+	o(`	func main() {
+			d := make(chan []int, 4)
+			for i := 0; i < 50; i++ {
+				go func(k int) {
+					s := make([]int, 100)
+					s[99] = k
+					d <- s
+				}(i)
+			}
+			sum := 0
+			for i := 0; i < 50; i++ {
+				s := <-d
+				sum += s[99]
+			}
+			printInt(sum)
+		}`,
+		"1225",
+	)
+}
@@ -17,6 +17,7 @@ func BuildPkg(p *Pkg) (*link.Pkg, []*lexing.Error) {
 		}
 		obj := link.NewVar(align)
 		obj.Zeros(uint32(v.size))
+		obj.SetPtrs(ptrOffsets(0, v.Ptrs))
 		p.lib.DefineVar(v.name, obj)
 	}
 
@@ -32,13 +33,16 @@ func BuildPkg(p *Pkg) (*link.Pkg, []*lexing.Error) {
 	}
 
 	if p.tests != nil {
-		v := link.NewVar(regSize)
-		for _, f := range p.tests.funcs {
-			if err := v.WriteLink(p.path, f.name); err != nil {
-				panic(err)
-			}
-		}
-		p.lib.DefineVar(p.tests.name, v)
+		p.tests.define(p)
+	}
+	for _, t := range p.vtables {
+		t.define(p)
+	}
+	for _, d := range p.structDescs {
+		d.define(p)
+	}
+	for _, v := range p.funcValList {
+		v.tab.define(p)
 	}
 
 	return p.lib, nil
@@ -0,0 +1,45 @@
+package codegen
+
+import (
+	"sort"
+
+	"shanhu.io/smlvm/link"
+)
+
+// ptrOffsets returns the sorted offsets of the pointers in a variable at
+// base.
+func ptrOffsets(base int32, ptrs []int32) []uint32 {
+	var ret []uint32
+	for _, p := range ptrs {
+		ret = append(ret, uint32(base+p))
+	}
+	return ret
+}
+
+type uint32s []uint32
+
+func (s uint32s) Len() int           { return len(s) }
+func (s uint32s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s uint32s) Less(i, j int) bool { return s[i] < s[j] }
+
+// frameLayout returns the layout of the stack frame of a function, for
+// the garbage collector to find the pointers on the stack.
+func frameLayout(f *Func) *link.Frame {
+	var ptrs []uint32
+	add := func(vars []*Var) {
+		for _, v := range vars {
+			ptrs = append(ptrs, ptrOffsets(f.frameSize-v.Offset, v.Ptrs)...)
+		}
+	}
+	add(f.sig.args)
+	add(f.sig.rets)
+	add(f.savedRegs)
+	add(f.locals)
+
+	sort.Sort(uint32s(ptrs))
+	return &link.Frame{
+		Size:    uint32(f.frameSize),
+		RetAddr: uint32(f.frameSize - f.retAddr.Offset),
+		Ptrs:    ptrs,
+	}
+}
@@ -18,6 +18,7 @@ type Func struct {
 	savedRegs []*Var
 	locals    []*Var // local variables
 	retAddr   *Var   // saved return address register
+	env       *Var   // saved closure record pointer
 
 	prologue *Block
 	epilogue *Block
@@ -71,6 +72,19 @@ func (f *Func) NewLocal(n int32, name string, u8, regSizeAlign bool) Ref {
 	return ret
 }
 
+// Env returns the local variable that saves the pointer to the function
+// value record that the function is called with. The first word of the
+// record is the address of the function; the closure environment
+// follows.
+func (f *Func) Env() Ref {
+	if f.env == nil {
+		f.env = NewVar(regSize, "<env>", false, true)
+		f.env.Ptrs = []int32{0}
+		f.locals = append(f.locals, f.env)
+	}
+	return f.env
+}
+
 func (f *Func) newTempName() string {
 	ret := fmt.Sprintf("<%d>", f.nvar)
 	f.nvar++
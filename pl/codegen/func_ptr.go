@@ -1,6 +1,7 @@
 package codegen
 
-// FuncPtr is a reference to a function pointer.
+// FuncPtr is a reference to a function value that is called indirectly.
+// See FuncVal for the layout of a function value.
 type FuncPtr struct {
 	sig *FuncSig
 	Ref
@@ -33,16 +33,24 @@ func NewFuncSig(args, rets []*FuncArg) *FuncSig {
 		ret.rets = append(ret.rets, v)
 	}
 
-	ret.regArgs, ret.argRegUsed = layoutFuncArgs(ret, ret.args)
-	ret.regRets, _ = layoutFuncArgs(ret, ret.rets)
+	ret.regArgs, ret.argRegUsed = layoutFuncArgs(ret, ret.args, 0)
+	ret.regRets, _ = layoutFuncArgs(ret, ret.rets, ret.frameSize)
 
 	return ret
 }
 
-func layoutFuncArgs(f *FuncSig, args []*Var) ([]*Var, []bool) {
+// layoutFuncArgs assigns registers to the args that fit in a register,
+// and lays out the rest on the stack starting from offset start. The
+// return values that are not sent via registers, such as structs and
+// arrays, are laid out after the args, in a slot that the caller
+// allocates on its stack, so that writing a return value does not
+// overwrite an arg that is not read yet.
+func layoutFuncArgs(f *FuncSig, args []*Var, start int32) (
+	[]*Var, []bool,
+) {
 	const viaRegMax = 3
 
-	frameSize := int32(0)
+	frameSize := start
 	nreg := uint32(0)
 	regUsed := make([]bool, viaRegMax+1) // only track r1-r3
 	regArgs := make([]*Var, 0, viaRegMax)
@@ -0,0 +1,49 @@
+package codegen
+
+import (
+	"shanhu.io/smlvm/link"
+)
+
+// funcTable is a global variable that holds a list of function pointers.
+// It is used for the test list and for interface vtables, where the
+// first entry is the type descriptor of the receiver.
+type funcTable struct {
+	pkg, name string
+	funcs     []Ref // *Func, *FuncSym or *HeapSym
+}
+
+func newFuncTable(pkg, name string, funcs []Ref) *funcTable {
+	for _, f := range funcs {
+		switch f.(type) {
+		case *Func, *FuncSym, *HeapSym:
+		default:
+			panic("not a function or variable symbol")
+		}
+	}
+	return &funcTable{pkg: pkg, name: name, funcs: funcs}
+}
+
+func (t *funcTable) String() string     { return t.name }
+func (t *funcTable) RegSizeAlign() bool { return true }
+func (t *funcTable) Size() int32 {
+	return regSize * int32(len(t.funcs))
+}
+
+func (t *funcTable) define(p *Pkg) {
+	v := link.NewVar(regSize)
+	for _, f := range t.funcs {
+		var err error
+		switch f := f.(type) {
+		case *Func:
+			err = v.WriteLink(f.pkg, f.name)
+		case *FuncSym:
+			err = v.WriteLink(f.pkg, f.name)
+		case *HeapSym:
+			err = v.WriteLink(f.pkg, f.name)
+		}
+		if err != nil {
+			panic(err)
+		}
+	}
+	p.lib.DefineVar(t.name, v)
+}
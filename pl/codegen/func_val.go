@@ -0,0 +1,53 @@
+package codegen
+
+import (
+	"fmt"
+)
+
+// FuncVal is a function value. A function value is a pointer to a
+// record whose first word is the address of the function's code; the
+// rest of the record, if any, is the environment of a closure. When
+// calling a function value, the pointer to the record is passed in
+// register r4.
+//
+// A FuncVal refers to the static record of a function symbol, which has
+// no environment.
+type FuncVal struct {
+	F   Ref // *Func or *FuncSym
+	tab *funcTable
+}
+
+func (v *FuncVal) String() string { return "&" + v.F.String() }
+
+// Size returns the size of a function value.
+func (v *FuncVal) Size() int32 { return regSize }
+
+// RegSizeAlign returns true. A function value is always word aligned.
+func (v *FuncVal) RegSizeAlign() bool { return true }
+
+func funcSymName(f Ref) string {
+	switch f := f.(type) {
+	case *Func:
+		return f.pkg + "." + f.name
+	case *FuncSym:
+		return f.pkg + "." + f.name
+	}
+	panic("not a function symbol")
+}
+
+// NewFuncVal returns the static function value of a function symbol.
+// f must be a *Func or a function symbol created by NewFuncSym.
+func (p *Pkg) NewFuncVal(f Ref) *FuncVal {
+	key := funcSymName(f)
+	if ret, found := p.funcVals[key]; found {
+		return ret
+	}
+
+	name := fmt.Sprintf(":fv_%d", len(p.funcVals))
+	tab := newFuncTable(p.path, name, []Ref{f})
+	p.lib.DeclareVar(tab.name)
+	ret := &FuncVal{F: f, tab: tab}
+	p.funcVals[key] = ret
+	p.funcValList = append(p.funcValList, ret)
+	return ret
+}
@@ -45,10 +45,11 @@ func genCallOp(g *gener, b *Block, op *CallOp) {
 		jal := b.inst(asm.jal(0))
 		jal.sym = &linkSym{link.FillLink, f.pkg, f.name}
 	case *FuncPtr:
-		// function pointer, set PC manually
+		// function value, r4 points to the record, whose first word
+		// is the address of the code.
 		loadRef(b, _r4, f.Ref)
 		b.inst(asm.addi(_ret, _pc, 4))
-		b.inst(asm.ori(_pc, _r4, 0))
+		b.inst(asm.lw(_pc, _r4, 0))
 	default:
 		panic("bug")
 	}
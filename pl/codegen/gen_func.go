@@ -13,7 +13,8 @@ import (
 - r1, the first arg or return, if not used, should keep the value
 - r2, the second arg or return
 - r3, the third arg or return
-- r4, free form temp
+- r4, free form temp; when calling a function value, it points to the
+  function value record
 - sp, stack pointer
 - ret, return address
 - pc, the program counter
@@ -51,6 +52,7 @@ func layoutLocals(f *Func) {
 		// save this register
 		v := NewVar(regSize, "", false, true)
 		v.ViaReg = uint32(i)
+		v.Ptrs = []int32{0} // might be a pointer of the caller
 		f.savedRegs = append(f.savedRegs, v)
 	}
 
@@ -78,6 +80,10 @@ func makePrologue(g *gener, f *Func) {
 	// move the sp
 	b.inst(asm.addi(_sp, _sp, -f.frameSize))
 
+	if f.env != nil {
+		saveVar(b, _r4, f.env) // the function value record
+	}
+
 	for _, v := range f.sig.args {
 		if v.ViaReg == 0 {
 			continue // skip args not sent in via register
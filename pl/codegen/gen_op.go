@@ -19,6 +19,10 @@ var basicOpFuncs = map[string]func(
 	"|":   asm.or,
 	"^":   asm.xor,
 	"nor": asm.nor,
+	"f+":  asm.fadd,
+	"f-":  asm.fsub,
+	"f*":  asm.fmul,
+	"f/":  asm.fdiv,
 }
 
 func genArithOp(g *gener, b *Block, op *ArithOp) {
@@ -68,6 +72,21 @@ func genArithOp(g *gener, b *Block, op *ArithOp) {
 			case "u<=":
 				b.inst(asm.sltu(_r4, _r1, _r4))
 				b.inst(asm.xori(_r4, _r4, 1))
+			case "f==":
+				b.inst(asm.feq(_r4, _r4, _r1))
+			case "f!=":
+				b.inst(asm.feq(_r4, _r4, _r1))
+				b.inst(asm.xori(_r4, _r4, 1))
+			case "f>":
+				b.inst(asm.flt(_r4, _r1, _r4))
+			case "f<":
+				b.inst(asm.flt(_r4, _r4, _r1))
+			case "f>=":
+				b.inst(asm.flt(_r4, _r4, _r1))
+				b.inst(asm.xori(_r4, _r4, 1))
+			case "f<=":
+				b.inst(asm.flt(_r4, _r1, _r4))
+				b.inst(asm.xori(_r4, _r4, 1))
 			case "<<":
 				b.inst(asm.sllv(_r4, _r4, _r1))
 			case ">>":
@@ -135,6 +154,15 @@ func genArithOp(g *gener, b *Block, op *ArithOp) {
 		case "^":
 			loadRef(b, _r4, op.B)
 			b.inst(asm.nor(_r4, _r0, _r4))
+		case "f-":
+			loadRef(b, _r4, op.B)
+			b.inst(asm.fneg(_r4, _r4))
+		case "fint": // float to int
+			loadRef(b, _r4, op.B)
+			b.inst(asm.fint(_r4, _r4))
+		case "fflt": // int to float
+			loadRef(b, _r4, op.B)
+			b.inst(asm.fflt(_r4, _r4))
 		case "&": // fetches the address of the block
 			loadAddr(b, _r4, op.B)
 		case "<0":
@@ -6,6 +6,10 @@ type HeapSym struct {
 	size         int32
 	u8           bool
 	regSizeAlign bool
+
+	// Ptrs are the offsets of the words in the variable that might hold
+	// heap pointers.
+	Ptrs []int32
 }
 
 // NewHeapSym creates a new heap var symbol (global var).
@@ -16,7 +16,9 @@ func loadAddr(b *Block, reg uint32, r Ref) {
 		}
 	case *HeapSym:
 		loadSym(b, reg, r.pkg, r.name)
-	case *testList:
+	case *funcTable:
+		loadSym(b, reg, r.pkg, r.name)
+	case *heapDat:
 		loadSym(b, reg, r.pkg, r.name)
 	default:
 		panic(fmt.Errorf("load addr of %T", r))
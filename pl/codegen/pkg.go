@@ -13,11 +13,16 @@ type Pkg struct {
 
 	path string
 
-	funcs   []*Func
-	vars    []*HeapSym
-	tests   *testList
-	strPool *strPool
-	datPool *datPool
+	funcs       []*Func
+	vars        []*HeapSym
+	tests       *funcTable
+	vtables     []*funcTable
+	structDescs []*structDesc
+	strPool     *strPool
+	datPool     *datPool
+
+	funcVals    map[string]*FuncVal
+	funcValList []*FuncVal
 
 	// helper functions required for generating
 	g *gener
@@ -31,6 +36,8 @@ func NewPkg(path string) *Pkg {
 		strPool: newStrPool(path),
 		datPool: newDatPool(path),
 		g:       newGener(),
+
+		funcVals: make(map[string]*FuncVal),
 	}
 }
 
@@ -61,13 +68,29 @@ func (p *Pkg) NewTestList(name string, funcs []*Func) Ref {
 		panic("tests already built")
 	}
 
-	ret := newTestList(p.path, name, funcs)
+	var refs []Ref
+	for _, f := range funcs {
+		refs = append(refs, f)
+	}
+	ret := newFuncTable(p.path, name, refs)
 	p.lib.DeclareVar(ret.name)
 	p.tests = ret
 
 	return ret
 }
 
+// NewVTable creates a global variable of a list of function symbols for
+// implementing an interface. The first entry is the type descriptor of
+// the receiver, and each function must be a *Func or a function symbol
+// created by NewFuncSym.
+func (p *Pkg) NewVTable(funcs []Ref) Ref {
+	name := fmt.Sprintf(":vtable_%d", len(p.vtables))
+	ret := newFuncTable(p.path, name, funcs)
+	p.lib.DeclareVar(ret.name)
+	p.vtables = append(p.vtables, ret)
+	return ret
+}
+
 // HookBuiltin uses the builtin package that provides neccessary
 // builtin functions for IR generation
 func (p *Pkg) HookBuiltin(pkg *link.Pkg) error {
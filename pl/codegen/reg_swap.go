@@ -158,6 +158,8 @@ func loadRef(b *Block, reg uint32, r Ref) {
 		loadSym(b, reg, r.pkg, r.name)
 	case *FuncPtr:
 		loadRef(b, reg, r.Ref)
+	case *FuncVal:
+		loadAddr(b, reg, r.tab)
 	case *AddrRef:
 		if r.size == 0 {
 			return
@@ -202,7 +204,7 @@ func loadRef(b *Block, reg uint32, r Ref) {
 func canViaReg(r Ref) bool {
 	switch r := r.(type) {
 	case *Var:
-		return r.size <= 1 || r.size == regSize
+		return r.size <= 1 || (r.size == regSize && r.regSizeAlign)
 	case *number:
 		return true
 	case *byt:
@@ -211,10 +213,12 @@ func canViaReg(r Ref) bool {
 		return true
 	case *FuncSym:
 		return true
+	case *FuncVal:
+		return true
 	case *AddrRef:
 		return r.size <= 1 || (r.size == regSize && r.regSizeAlign)
 	case *HeapSym:
-		return r.size <= 1 || r.size == regSize
+		return r.size <= 1 || (r.size == regSize && r.regSizeAlign)
 	}
 	return false
 }
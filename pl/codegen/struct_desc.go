@@ -0,0 +1,40 @@
+package codegen
+
+import (
+	"encoding/binary"
+
+	"shanhu.io/smlvm/link"
+)
+
+// structDesc is the descriptor of a struct type. The first word of a
+// vtable links to the descriptor of the receiver's type, so the dynamic
+// type of an interface value can be checked by comparing addresses. The
+// descriptor holds the name of the type as a string.
+type structDesc struct {
+	name string
+	str  *strConst
+}
+
+// StructDescName returns the symbol name of the descriptor of struct t.
+func StructDescName(t string) string { return ":struct_" + t }
+
+// NewStructDesc creates the descriptor of struct t declared in the
+// package. The descriptors in other packages are referenced with
+// NewHeapSym and StructDescName.
+func (p *Pkg) NewStructDesc(t string) Ref {
+	d := &structDesc{name: StructDescName(t), str: p.strPool.addString(t)}
+	p.lib.DeclareVar(d.name)
+	p.structDescs = append(p.structDescs, d)
+	return NewHeapSym(p.path, d.name, regSize*2, false, true)
+}
+
+func (d *structDesc) define(p *Pkg) {
+	v := link.NewVar(regSize)
+	if err := v.WriteLink(d.str.pkg, d.str.name); err != nil {
+		panic(err)
+	}
+	n := make([]byte, regSize)
+	binary.LittleEndian.PutUint32(n, uint32(len(d.str.str)))
+	v.Write(n)
+	p.lib.DefineVar(d.name, v)
+}
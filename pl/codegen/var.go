@@ -13,6 +13,10 @@ type Var struct {
 
 	U8 bool // if this var is a unsigned byte
 
+	// Ptrs are the offsets of the words in the variable that might hold
+	// heap pointers.
+	Ptrs []int32
+
 	// reg is the register allocated
 	// valid values are in range [1, 4] for normal values
 	// and also ret register is 6
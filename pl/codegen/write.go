@@ -21,5 +21,6 @@ func writeFunc(p *Pkg, f *Func) {
 		writeBlock(lfunc, b)
 	}
 
+	lfunc.SetFrame(frameLayout(f))
 	p.lib.DefineFunc(f.name, lfunc)
 }
@@ -74,6 +74,8 @@ func CanBeZero(r Ref) bool {
 		return false
 	case *Func:
 		return false
+	case *FuncVal:
+		return false
 	}
 	return true
 }
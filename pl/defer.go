@@ -0,0 +1,123 @@
+package pl
+
+import (
+	"fmt"
+
+	"shanhu.io/smlvm/arch"
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// startDefers saves the top of the defer stack when a function with
+// deferred calls starts, and adds the block that runs the deferred calls
+// before the function returns.
+func startDefers(b *builder) {
+	b.deferMark = b.newTempIR(types.Uint)
+	b.b.Call([]codegen.Ref{b.deferMark}, b.rt.deferMark)
+	b.exit = b.f.NewBlock(b.b)
+	b.exit.Call(nil, b.rt.deferRun, b.deferMark)
+}
+
+// deferVal is a value saved in the record of a deferred call.
+type deferVal struct {
+	r      *ref
+	offset int32
+}
+
+func isStaticFunc(f codegen.Ref) bool {
+	switch f.(type) {
+	case *codegen.FuncSym, *codegen.Func, *codegen.FuncVal:
+		return true
+	}
+	return false
+}
+
+// buildDeferCall builds the function that makes a deferred call with
+// the function and the arguments saved in the record. The function is
+// called as a closure, where the record is the environment.
+func buildDeferCall(
+	b *builder, name string, f *ref, vals []*deferVal,
+) *codegen.Func {
+	irFunc := b.p.NewFunc(name, nil, codegen.VoidFuncSig)
+	f0, b0 := b.f, b.b
+	b.f, b.b = irFunc, irFunc.NewBlock(nil)
+	defer func() { b.f, b.b = f0, b0 }()
+
+	env := irFunc.Env()
+	load := func(v *deferVal) codegen.Ref {
+		return elemAt(env, v.r.Type(), v.offset)
+	}
+
+	funcType := f.Type().(*types.Func)
+	sig := funcType
+	fir := f.IR()
+	if !isStaticFunc(fir) {
+		fir = load(vals[0])
+		vals = vals[1:]
+		nilFuncPointerPanic(b, fir)
+	}
+	if f.recv != nil {
+		sig = f.recvFunc
+	}
+
+	var args []codegen.Ref
+	for _, v := range vals {
+		args = append(args, load(v))
+	}
+	var rets []codegen.Ref
+	for _, t := range funcType.RetTypes {
+		rets = append(rets, b.newTempIR(t))
+	}
+	b.b.Call(rets, wrapFuncPtr(fir, sig), args...)
+	return irFunc
+}
+
+// deferVals lays out the values saved in the record of a call to
+// function f with args, and returns the values and the size of the
+// record.
+func deferVals(f, args *ref) ([]*deferVal, int32) {
+	// the function, the receiver and the arguments, in this order
+	var saved []*ref
+	if !isStaticFunc(f.IR()) {
+		saved = append(saved, newRef(f.Type(), f.IR()))
+	}
+	if f.recv != nil {
+		saved = append(saved, f.recv)
+	}
+	for i := 0; i < args.Len(); i++ {
+		saved = append(saved, args.At(i))
+	}
+
+	// the record starts with the code and the link
+	offset := int32(2 * arch.RegSize)
+	var vals []*deferVal
+	for _, r := range saved {
+		vals = append(vals, &deferVal{r: r, offset: offset})
+		offset += (r.Type().Size() + 3) / 4 * 4
+	}
+	return vals, offset
+}
+
+func buildDeferStmt(b *builder, stmt *tast.DeferStmt) {
+	if b.rt == nil {
+		b.CodeErrorf(stmt.Kw.Pos, "pl.defer.noRuntime",
+			"defer is not supported without the runtime")
+		return
+	}
+
+	f := b.buildExpr(stmt.Call.Func)
+	args := b.buildExpr(stmt.Call.Args)
+	vals, size := deferVals(f, args)
+
+	name := fmt.Sprintf(":defer_%d", b.deferCount)
+	b.deferCount++
+	call := buildDeferCall(b, name, f, vals)
+
+	rec := callRuntime(b, b.rt.deferPush,
+		codegen.Num(uint32(size)), b.p.NewFuncVal(call),
+	)
+	for _, v := range vals {
+		b.b.Assign(elemAt(rec, v.r.Type(), v.offset), v.r.IR())
+	}
+}
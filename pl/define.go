@@ -10,8 +10,7 @@ func buildDefine(b *builder, d *tast.Define) {
 	for _, sym := range d.Left {
 		name := sym.Name()
 		t := sym.ObjType.(types.T)
-		v := b.newLocal(t, name)
-		r := newAddressableRef(t, v)
+		r := b.newVar(sym, t)
 		sym.Obj = &objVar{name: name, ref: r}
 		refs = append(refs, r)
 	}
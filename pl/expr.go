@@ -33,6 +33,20 @@ func buildExpr(b *builder, expr tast.Expr) *ref {
 		return buildIndexExpr(b, expr)
 	case *tast.ExprList:
 		return buildExprList(b, expr)
+	case *tast.ArrayLit:
+		return buildArrayLit(b, expr)
+	case *tast.StructLit:
+		return buildStructLit(b, expr)
+	case *tast.FuncLit:
+		return buildFuncLit(b, expr)
+	case *tast.MapLit:
+		return buildMapLit(b, expr)
+	case *tast.MapIndex:
+		return buildMapIndex(b, expr)
+	case *tast.ChanRecv:
+		return buildChanRecv(b, expr)
+	case *tast.TypeAssert:
+		return buildTypeAssert(b, expr)
 	}
 	panic(fmt.Errorf("buildExpr not implemented for %T", expr))
 }
@@ -0,0 +1,31 @@
+package pl
+
+import (
+	"shanhu.io/smlvm/pl/types"
+)
+
+func binaryOpFloat(b *builder, op string, A, B *ref) *ref {
+	switch op {
+	case "+", "-", "*", "/":
+		ret := b.newTemp(types.Float32)
+		b.b.Arith(ret.IR(), A.IR(), "f"+op, B.IR())
+		return ret
+	case "==", "!=", ">", "<", ">=", "<=":
+		ret := b.newTemp(types.Bool)
+		b.b.Arith(ret.IR(), A.IR(), "f"+op, B.IR())
+		return ret
+	}
+	panic("bug")
+}
+
+func unaryOpFloat(b *builder, op string, B *ref) *ref {
+	switch op {
+	case "+":
+		return B
+	case "-":
+		ret := b.newTemp(types.Float32)
+		b.b.Arith(ret.IR(), nil, "f-", B.IR())
+		return ret
+	}
+	panic("bug")
+}
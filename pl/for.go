@@ -5,6 +5,7 @@ import (
 )
 
 func buildForStmt(b *builder, stmt *tast.ForStmt) {
+	label := b.labels.take()
 	if stmt.Init != nil {
 		b.buildStmt(stmt.Init)
 	}
@@ -16,8 +17,9 @@ func buildForStmt(b *builder, stmt *tast.ForStmt) {
 		iter.Jump(body)
 
 		b.b = body
-		b.breaks.push(after, "")
-		b.continues.push(iter, "")
+		b.breaks.push(after, label)
+		b.continues.push(iter, label)
+		buildPreempt(b)
 
 		b.buildStmt(stmt.Body)
 
@@ -43,8 +45,9 @@ func buildForStmt(b *builder, stmt *tast.ForStmt) {
 	b.b.JumpIfNot(c.IR(), after)
 
 	b.b = body
-	b.breaks.push(after, "")
-	b.continues.push(iter, "")
+	b.breaks.push(after, label)
+	b.continues.push(iter, label)
+	buildPreempt(b)
 
 	b.buildStmt(stmt.Body)
 
@@ -59,14 +62,22 @@ func buildForStmt(b *builder, stmt *tast.ForStmt) {
 	b.b = after
 }
 
-func buildContinueStmt(b *builder) {
+func buildContinueStmt(b *builder, stmt *tast.ContinueStmt) {
 	after := b.f.NewBlock(b.b)
-	b.b.Jump(b.continues.top())
+	if stmt.Label != "" {
+		b.b.Jump(b.continues.byName(stmt.Label))
+	} else {
+		b.b.Jump(b.continues.top())
+	}
 	b.b = after
 }
 
-func buildBreakStmt(b *builder) {
+func buildBreakStmt(b *builder, stmt *tast.BreakStmt) {
 	after := b.f.NewBlock(b.b)
-	b.b.Jump(b.breaks.top())
+	if stmt.Label != "" {
+		b.b.Jump(b.breaks.byName(stmt.Label))
+	} else {
+		b.b.Jump(b.breaks.top())
+	}
 	b.b = after
 }
@@ -21,8 +21,48 @@ func makeRetRef(ts []*types.Arg, irs []codegen.Ref) *ref {
 	return ret
 }
 
+// bindHeapRets moves the named return values that are captured by
+// escaping function literals to the heap, and copies them back on
+// returning, after the deferred calls.
+func bindHeapRets(b *builder, f *tast.Func) {
+	var copyBack *codegen.Block
+	for i, s := range f.NamedRets {
+		if s == nil || !b.heapVars[s] || b.rt == nil {
+			continue
+		}
+		if copyBack == nil {
+			if b.exit == b.f.End() {
+				copyBack = b.f.NewBlock(b.b)
+				b.exit = copyBack
+			} else {
+				copyBack = b.f.NewBlock(b.exit)
+			}
+		}
+
+		slot := b.fretRef.At(i)
+		v := b.newVar(s, slot.Type())
+		copyBack.Assign(slot.IR(), v.IR())
+		if b.fretRef.IsSingle() {
+			b.fretRef = v
+		} else {
+			b.fretRef.lst[i] = v
+		}
+		s.Obj = &objVar{s.Name(), v}
+	}
+}
+
 func buildFunc(b *builder, f *tast.Func, irFunc *codegen.Func) {
 	b.f = irFunc
+	b.heapVars = f.HeapVars
+	t := f.Sym.ObjType.(*types.Func)
+	b.markFuncPtrs(irFunc, t)
+
+	b.b = b.f.NewBlock(nil)
+	b.exit, b.deferMark = b.f.End(), nil
+	b.labels = newLabels()
+	if f.HasDefer {
+		startDefers(b)
+	}
 
 	if f.Receiver != nil {
 		// bind the receiver
@@ -40,17 +80,21 @@ func buildFunc(b *builder, f *tast.Func, irFunc *codegen.Func) {
 		args = args[1:] // skip <this>
 	}
 	for i, s := range f.Args {
-		if s != nil {
-			t := s.ObjType.(types.T)
-			ref := newAddressableRef(t, args[i])
-			s.Obj = &objVar{s.Name(), ref}
+		if s == nil {
+			continue
 		}
+		t := s.ObjType.(types.T)
+		ref := newAddressableRef(t, args[i])
+		if b.heapVars[s] && b.rt != nil {
+			v := b.newVar(s, t)
+			b.b.Assign(v.IR(), ref.IR())
+			ref = v
+		}
+		s.Obj = &objVar{s.Name(), ref}
 	}
 
 	// bind named return symbols
 	rets := irFunc.RetRefs()
-
-	t := f.Sym.ObjType.(*types.Func)
 	b.fretRef = makeRetRef(t.Rets, rets)
 	if f.NamedRets != nil {
 		for i, s := range f.NamedRets {
@@ -58,9 +102,9 @@ func buildFunc(b *builder, f *tast.Func, irFunc *codegen.Func) {
 				s.Obj = &objVar{s.Name(), b.fretRef.At(i)}
 			}
 		}
+		bindHeapRets(b, f)
 	}
 
-	b.b = b.f.NewBlock(nil)
 	for _, stmt := range f.Body {
 		b.buildStmt(stmt)
 	}
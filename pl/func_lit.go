@@ -0,0 +1,115 @@
+package pl
+
+import (
+	"fmt"
+
+	"shanhu.io/smlvm/arch"
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// funcVal returns the function value of a function symbol reference.
+func funcVal(b *builder, r *ref) *ref {
+	switch f := r.IR().(type) {
+	case *codegen.Func, *codegen.FuncSym:
+		return newRef(r.Type(), b.p.NewFuncVal(f))
+	}
+	return r
+}
+
+// buildFuncLitBody builds the function of a function literal. The
+// captured variables are accessed via the pointers saved in the closure
+// record.
+func buildFuncLitBody(
+	b *builder, lit *tast.FuncLit, irFunc *codegen.Func,
+) {
+	f, blk, fret, this := b.f, b.b, b.fretRef, b.this
+	exit, deferMark, heapVars := b.exit, b.deferMark, b.heapVars
+	continues, breaks, labels := b.continues, b.breaks, b.labels
+	b.continues, b.breaks = newBlockStack(), newBlockStack()
+	b.this = nil
+
+	objs := make([]interface{}, len(lit.Captures))
+	for i, s := range lit.Captures {
+		objs[i] = s.Obj
+	}
+
+	if len(lit.Captures) > 0 {
+		env := irFunc.Env()
+		for i, s := range lit.Captures {
+			t := s.ObjType.(types.T)
+			offset := int32(i+1) * arch.RegSize
+			ptr := codegen.NewAddrRef(env, arch.RegSize, offset, false, true)
+			ir := codegen.NewAddrRef(
+				ptr, t.Size(), 0, types.IsByte(t), t.RegSizeAlign(),
+			)
+			s.Obj = &objVar{s.Name(), newAddressableRef(t, ir)}
+		}
+	}
+
+	buildFunc(b, lit.Func, irFunc)
+
+	for i, s := range lit.Captures {
+		s.Obj = objs[i]
+	}
+	b.f, b.b, b.fretRef, b.this = f, blk, fret, this
+	b.exit, b.deferMark, b.heapVars = exit, deferMark, heapVars
+	b.continues, b.breaks, b.labels = continues, breaks, labels
+}
+
+// buildClosure builds the closure record of a function literal. The
+// first word of the record is the address of the function; it is
+// followed by the pointers to the captured variables. The record of an
+// escaping function literal is allocated in the heap, and the others are
+// on the stack frame.
+func buildClosure(
+	b *builder, irFunc *codegen.Func, lit *tast.FuncLit,
+) codegen.Ref {
+	captures := lit.Captures
+	n := int32(len(captures)) + 1
+	ret := b.newPtr()
+	if lit.Escapes {
+		t := &types.Array{T: &types.Pointer{T: types.Uint}, N: n}
+		size := codegen.Num(uint32(t.Size()))
+		b.b.Call([]codegen.Ref{ret}, b.rt.alloc, size, b.typeDesc(t))
+	} else {
+		record := b.f.NewTemp(n*arch.RegSize, false, true)
+		var offsets []int32
+		for i := int32(1); i < n; i++ {
+			offsets = append(offsets, i*arch.RegSize)
+		}
+		record.(*codegen.Var).Ptrs = offsets
+		b.b.Arith(ret, nil, "&", record)
+	}
+
+	b.b.Assign(codegen.NewAddrRef(ret, arch.RegSize, 0, false, true), irFunc)
+	for i, s := range captures {
+		v := s.Obj.(*objVar)
+		offset := int32(i+1) * arch.RegSize
+		dest := codegen.NewAddrRef(ret, arch.RegSize, offset, false, true)
+		b.b.Arith(dest, nil, "&", v.IR())
+	}
+	return ret
+}
+
+func buildFuncLit(b *builder, lit *tast.FuncLit) *ref {
+	t := lit.Func.Sym.ObjType.(*types.Func)
+	name := fmt.Sprintf(":func_%d", b.funcLitCount)
+	b.funcLitCount++
+	irFunc := b.p.NewFunc(name, lit.Func.Sym.Pos, makeFuncSig(t))
+
+	var ret *ref
+	if len(lit.Captures) == 0 {
+		ret = newRef(t, b.p.NewFuncVal(irFunc))
+	} else if lit.Escapes && b.rt == nil {
+		b.CodeErrorf(lit.Func.Sym.Pos, "pl.funcLit.noRuntime",
+			"escaping closures are not supported without the runtime")
+		return newRef(t, codegen.Num(0))
+	} else {
+		ret = newRef(t, buildClosure(b, irFunc, lit))
+	}
+
+	buildFuncLitBody(b, lit, irFunc)
+	return ret
+}
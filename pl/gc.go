@@ -0,0 +1,98 @@
+package pl
+
+import (
+	"encoding/binary"
+
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// ptrs returns the offsets of the words in a value of type t that might
+// hold heap pointers. When withUint is true, uint words are also counted,
+// as the runtime saves addresses in uint values.
+func ptrs(t types.T, withUint bool) []int32 {
+	switch t := types.Underlying(t).(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan,
+		*types.Interface, *types.Func:
+		return []int32{0}
+	case types.Basic:
+		if withUint && t == types.Uint {
+			return []int32{0}
+		}
+	case *types.Struct:
+		var ret []int32
+		for _, f := range t.Fields {
+			for _, p := range ptrs(f.T, withUint) {
+				ret = append(ret, f.Offset()+p)
+			}
+		}
+		return ret
+	case *types.Array:
+		elem := ptrs(t.T, withUint)
+		if len(elem) == 0 {
+			return nil
+		}
+		size := arrayElementSize(t.T)
+		var ret []int32
+		for i := int32(0); i < t.N; i++ {
+			for _, p := range elem {
+				ret = append(ret, i*size+p)
+			}
+		}
+		return ret
+	}
+	return nil
+}
+
+// markPtrs records the pointers in a local variable of type t for the
+// garbage collector to scan the stack.
+func (b *builder) markPtrs(v codegen.Ref, t types.T) {
+	v.(*codegen.Var).Ptrs = ptrs(t, b.runtime)
+}
+
+// markFuncPtrs records the pointers in the arguments and the return
+// values of a function.
+func (b *builder) markFuncPtrs(f *codegen.Func, t *types.Func) {
+	for i, v := range f.ArgRefs() {
+		b.markPtrs(v, t.Args[i].T)
+	}
+	for i, v := range f.RetRefs() {
+		b.markPtrs(v, t.Rets[i].T)
+	}
+}
+
+// typeDesc returns the address of the type of t for the allocator, or 0
+// if t has no pointers. The type is an array of words: the size of an
+// element, the number of runs of pointers, and the runs, where each run
+// is an offset and a number of words.
+func (b *builder) typeDesc(t types.T) codegen.Ref {
+	offsets := ptrs(t, false)
+	if len(offsets) == 0 {
+		return codegen.Num(0)
+	}
+
+	var runs []uint32
+	for i, p := range offsets {
+		if i > 0 && p == offsets[i-1]+4 {
+			runs[len(runs)-1]++
+			continue
+		}
+		runs = append(runs, uint32(p), 1)
+	}
+	words := []uint32{uint32(arrayElementSize(t)), uint32(len(runs) / 2)}
+	words = append(words, runs...)
+	bs := make([]byte, len(words)*4)
+	for i, w := range words {
+		binary.LittleEndian.PutUint32(bs[i*4:], w)
+	}
+
+	key := string(bs)
+	dat, ok := b.typeDescs[key]
+	if !ok {
+		dat = b.p.NewHeapDat(bs, 4, true)
+		b.typeDescs[key] = dat
+	}
+	ret := b.newPtr()
+	b.b.Arith(ret, nil, "&", dat)
+	return ret
+}
@@ -0,0 +1,230 @@
+package pl
+
+import (
+	"strings"
+	"testing"
+
+	"shanhu.io/smlvm/arch"
+)
+
+// gcTestConfig is a machine with little memory, so that a program runs
+// out of memory if the garbage is not collected.
+var gcTestConfig = &arch.Config{
+	MemSize:      gcTestMemSize,
+	InitSP:       gcTestMemSize - gcTestStackSize,
+	StackPerCore: gcTestStackSize,
+}
+
+const (
+	gcTestMemSize   = 256 << 10
+	gcTestStackSize = 0x2000
+)
+
+func TestGC(t *testing.T) {
+	const N = 10000000
+
+	o := func(input, output string) {
+		out, err := singleTestRun(t, input, gcTestConfig, N)
+		if err == errRunFailed {
+			return
+		}
+		if !arch.IsHalt(err) {
+			t.Log(input)
+			t.Log(err)
+			t.Error("did not halt gracefully")
+			return
+		}
+
+		got := strings.TrimSpace(out)
+		expect := strings.TrimSpace(output)
+		if got != expect {
+			t.Log(input)
+			t.Logf("expect: %s", expect)
+			t.Errorf("got: %s", got)
+		}
+	}
+
+	o(`	struct node {
+			v    int
+			next *node
+			buf  []int
+		}
+		func build(n int) *node {
+			var head *node
+			for i := 0; i < n; i++ {
+				p := new(node)
+				p.v = i
+				p.next = head
+				p.buf = make([]int, 16)
+				p.buf[15] = i
+				head = p
+			}
+			return head
+		}
+		func sum(p *node) int {
+			ret := 0
+			for p != nil {
+				ret += p.v + p.buf[15]
+				p = p.next
+			}
+			return ret
+		}
+		var keep *node
+		func main() {
+			keep = build(100)
+			total := 0
+			for i := 0; i < 40; i++ {
+				total += sum(build(50))
+			}
+			printInt(total)
+			printInt(sum(keep))
+		}`,
+		"98000\n9900",
+	)
+
+	o(`	struct item {
+			id   int
+			data []int
+		}
+		func fill(n int) map[int]*item {
+			m := make(map[int]*item)
+			for i := 0; i < n; i++ {
+				it := new(item)
+				it.id = i
+				it.data = make([]int, 8)
+				it.data[7] = i * 2
+				m[i] = it
+			}
+			return m
+		}
+		func main() {
+			keep := fill(20)
+			total := 0
+			for i := 0; i < 40; i++ {
+				m := fill(30)
+				for k, v := range m {
+					total += k + v.data[7]
+				}
+				delete(keep, i%20)
+				keep[i%20] = m[i%30]
+			}
+			printInt(total)
+			sum := 0
+			for k, v := range keep {
+				sum += k*1000 + v.id
+			}
+			printInt(sum)
+		}`,
+		"52200\n190290",
+	)
+
+	o(`	func grow(n int) []*int {
+			var ret []*int
+			for i := 0; i < n; i++ {
+				p := new(int)
+				*p = i
+				ret = append(ret, p)
+			}
+			return ret
+		}
+		func main() {
+			var last []*int
+			total := 0
+			for i := 0; i < 40; i++ {
+				s := grow(100)
+				total += *s[99]
+				if i == 10 {
+					last = s
+				}
+			}
+			printInt(total)
+			printInt(*last[42])
+		}`,
+		"3960\n42",
+	)
+
+	// the argument of a deferred call is only kept by the defer record
+	o(`	struct node {
+			buf  [30]int
+			next *node
+		}
+		func show(p *node) { printInt(p.buf[3]) }
+		func churn(n int) {
+			var l *node
+			for i := 0; i < n; i++ {
+				p := new(node)
+				p.next = l
+				l = p
+				if i%20 == 19 { l = nil }
+			}
+		}
+		func work(v int) {
+			p := new(node)
+			p.buf[3] = v
+			defer show(p)
+			p = nil
+			churn(400)
+		}
+		func main() {
+			for i := 0; i < 3; i++ { work(i) }
+		}`,
+		"0\n1\n2",
+	)
+	// closures are only kept by function values
+	o(`	struct node { v int; next *node }
+		struct holder { f func() int; next *holder }
+		func mk(k int) func() int { return func() int { return k } }
+		func build() *holder {
+			var h *holder
+			for i := 0; i < 50; i++ {
+				p := new(holder)
+				p.f = mk(i)
+				p.next = h
+				h = p
+			}
+			return h
+		}
+		func churn() {
+			var l *node
+			for i := 0; i < 10000; i++ {
+				p := new(node)
+				p.v = -1
+				p.next = l
+				l = p
+				if i%1000 == 999 { l = nil }
+			}
+		}
+		func main() {
+			h := build()
+			churn()
+			sum := 0
+			for ; h != nil; h = h.next { sum += h.f() }
+			printInt(sum)
+		}`,
+		"1225",
+	)
+}
+
+func TestGCOutOfMemory(t *testing.T) {
+	const N = 10000000
+
+	// everything allocated is still in use
+	input := `
+		struct node {
+			buf  [60]int
+			next *node
+		}
+		var head *node
+		func main() {
+			for i := 0; i < 10000; i++ {
+				p := new(node)
+				p.next = head
+				head = p
+			}
+		}`
+	_, err := singleTestRun(t, input, gcTestConfig, N)
+	if err != errRunFailed && !arch.IsPanic(err) {
+		t.Log(err)
+		t.Error("should panic")
+	}
+}
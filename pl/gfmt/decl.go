@@ -98,3 +98,7 @@ func printImportDecls(f *formatter, d *ast.ImportDecls) {
 	f.ShiftTab()
 	f.printToken(d.Rparen)
 }
+
+func printTypeDecl(f *formatter, d *ast.TypeDecl) {
+	f.printExprs(d.Kw, " ", d.Name, " ", d.Type)
+}
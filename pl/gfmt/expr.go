@@ -36,6 +36,9 @@ func printExpr(f *formatter, expr interface{}) {
 		if expr.Args != nil {
 			printExprList(f, expr.Lparen, expr.Rparen, expr.Args)
 		}
+		if expr.Dots != nil {
+			f.printToken(expr.Dots)
+		}
 		f.printToken(expr.Rparen)
 	case *ast.IndexExpr:
 		if expr.Colon != nil {
@@ -51,8 +54,12 @@ func printExpr(f *formatter, expr interface{}) {
 		} else {
 			f.printExprs(expr.Array, expr.Lbrack, expr.Index, expr.Rbrack)
 		}
+	case *ast.InstExpr:
+		f.printExprs(expr.Generic, expr.Lbrack, expr.Types, expr.Rbrack)
 	case *ast.ArrayTypeExpr:
-		if expr.Len == nil {
+		if expr.Dots != nil {
+			f.printExprs(expr.Lbrack, expr.Dots, expr.Rbrack, expr.Type)
+		} else if expr.Len == nil {
 			f.printExprs(expr.Lbrack, expr.Rbrack, expr.Type)
 		} else {
 			f.printExprs(expr.Lbrack, expr.Len, expr.Rbrack, expr.Type)
@@ -60,10 +67,51 @@ func printExpr(f *formatter, expr interface{}) {
 	case *ast.FuncTypeExpr:
 		f.printToken(expr.Kw)
 		printFuncSig(f, expr.FuncSig)
+	case *ast.MapTypeExpr:
+		f.printExprs(expr.Kw, expr.Lbrack, expr.Key, expr.Rbrack, expr.Val)
+	case *ast.ChanTypeExpr:
+		f.printExprs(expr.Kw, " ", expr.Elem)
+	case *ast.MapLiteral:
+		f.printExprs(expr.Type)
+		if expr.Exprs != nil {
+			f.printToken(expr.Lbrace)
+			printExprList(f, expr.Lbrace, expr.Rbrace, expr.Exprs)
+			f.printToken(expr.Rbrace)
+		} else {
+			f.printExprs(expr.Lbrace, expr.Rbrace)
+		}
 	case *ast.MemberExpr:
 		f.printExprs(expr.Expr, expr.Dot, expr.Sub)
+	case *ast.TypeAssertExpr:
+		f.printExprs(expr.Expr, expr.Dot, expr.Lparen)
+		if expr.Type != nil {
+			f.printExprs(expr.Type)
+		} else {
+			f.printToken(expr.TypeKw)
+		}
+		f.printToken(expr.Rparen)
+	case *ast.StructLiteral:
+		if expr.Type != nil {
+			f.printExprs(expr.Type)
+		}
+		if expr.Fields != nil {
+			f.printToken(expr.Lbrace)
+			printExprList(f, expr.Lbrace, expr.Rbrace, expr.Fields)
+			f.printToken(expr.Rbrace)
+		} else {
+			f.printExprs(expr.Lbrace, expr.Rbrace)
+		}
+	case *ast.FuncLiteral:
+		f.printToken(expr.Type.Kw)
+		printFuncSig(f, expr.Type.FuncSig)
+		f.printStr(" ")
+		printStmt(f, expr.Body)
+	case *ast.KeyValueExpr:
+		f.printExprs(expr.Key, expr.Colon, " ", expr.Value)
 	case *ast.ArrayLiteral:
-		if expr.Type.Len != nil {
+		if expr.Type.Dots != nil {
+			f.printExprs(expr.Type.Lbrack, expr.Type.Dots, expr.Type.Rbrack)
+		} else if expr.Type.Len != nil {
 			f.printExprs(expr.Type.Lbrack, expr.Type.Len, expr.Type.Rbrack)
 		} else {
 			f.printExprs(expr.Type.Lbrack, expr.Type.Rbrack)
@@ -16,6 +16,8 @@ func printTopDecl(f *formatter, d ast.Decl) {
 		printConstDecls(f, d)
 	case *ast.Interface:
 		printInterface(f, d)
+	case *ast.TypeDecl:
+		printTypeDecl(f, d)
 	default:
 		f.errorf(nil, "invalid top-level declaration type: %T", d)
 	}
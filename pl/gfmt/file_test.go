@@ -170,6 +170,98 @@ func TestFormatFile(t *testing.T) {
 		}
 	`)
 
+	o(`
+		func main() {
+			var a = [5]int{ 2 :3,4:  5 }
+		}`, `
+		func main() {
+			var a = [5]int{2: 3, 4: 5}
+		}
+	`)
+	o(`
+		func main() {
+			a := [ ... ]P{ {1,2}, 3:{ y:4 } }
+		}`, `
+		func main() {
+			a := [...]P{{1, 2}, 3: {y: 4}}
+		}
+	`)
+	o(`
+		func main() {
+			p := &P{ x:3,y : 4 }
+		}`, `
+		func main() {
+			p := &P{x: 3, y: 4}
+		}
+	`)
+	o(`
+		func main() {
+			m := map [string]int{"a":1}
+			for k,v:=range m{}
+			for range m {}
+		}`, `
+		func main() {
+			m := map[string]int{"a": 1}
+			for k, v := range m {}
+			for range m {}
+		}
+	`)
+	o(`
+		func main() {
+			ch:=make(chan  int)
+			go  f(ch)
+			ch<-3
+			select{
+			case v,ok:=<-ch: f(v)
+			default:
+			}
+		}`, `
+		func main() {
+			ch := make(chan int)
+			go f(ch)
+			ch <- 3
+			select {
+			case v, ok := <-ch:
+				f(v)
+			default:
+			}
+		}
+	`)
+	o(`
+		func f(a int, b ...int) {}
+		func main() { f(1, b ...) }`, `
+		func f(a int, b ...int) {}
+
+		func main() {
+			f(1, b...)
+		}
+	`)
+	o(`
+		func main() {
+			L: for { break  L }
+			goto   M
+		M:
+		}`, `
+		func main() {
+			L:
+			for {
+				break L
+			}
+			goto M
+			M:
+		}
+	`)
+	o(`
+		func main() {
+			f := func(a int)int{ return a }
+		}`, `
+		func main() {
+			f := func(a int) int {
+				return a
+			}
+		}
+	`)
+
 	o(`
 		func main() {
 			f(
@@ -203,6 +295,20 @@ func TestFormatFile(t *testing.T) {
 		}
 	`)
 
+	o(`
+		func main() {
+			switch x:=a.( type ) {case *A,nil:
+			p:=x.(  *B ); default:
+			}}`, `
+		func main() {
+			switch x := a.(type) {
+			case *A, nil:
+				p := x.(*B)
+			default:
+			}
+		}
+	`)
+
 	o(`
 		func main() {a:=2;     if true {} else {}
 		if false {{}} else {  _ := 2;}
@@ -255,6 +361,25 @@ func TestFormatFile(t *testing.T) {
 			c d
 		}
 	`)
+	o(`
+		type  A   []int; type F func( a A )
+	`, `
+		type A []int
+
+		type F func(a A)
+	`)
+	o(`
+		func main() {}; struct s { a.A; *B
+			c    int }
+	`, `
+		func main() {}
+
+		struct s {
+			a.A
+			*B
+			c int
+		}
+	`)
 	o(`
 		func main() {}; struct s {  
 			}
@@ -263,4 +388,23 @@ func TestFormatFile(t *testing.T) {
 
 		struct s {}
 	`)
+	o(`
+		struct Pair[K,V   any] { k K; v V }
+		func (p *Pair[K,V]) Key() K { return p.k }
+		func Max[T integer](a, b T) T { x := Pair[T,[]T]{}; return b }
+	`, `
+		struct Pair[K, V any] {
+			k K
+			v V
+		}
+
+		func (p *Pair[K, V]) Key() K {
+			return p.k
+		}
+
+		func Max[T integer](a, b T) T {
+			x := Pair[T, []T]{}
+			return b
+		}
+	`)
 }
@@ -7,12 +7,30 @@ import (
 func printFunc(f *formatter, fn *ast.Func) {
 	f.printExprs(fn.Kw, " ")
 	if r := fn.Recv; r != nil {
-		f.printExprs(
-			r.Lparen, r.Recv, " ", r.Star, r.StructName, r.Rparen, " ",
-		)
+		f.printExprs(r.Lparen, r.Recv, " ", r.Star, r.StructName)
+		printTypeParams(f, r.TypeParams)
+		f.printExprs(r.Rparen, " ")
 	}
 	f.printExprs(fn.Name)
+	printTypeParams(f, fn.TypeParams)
 	printFuncSig(f, fn.FuncSig)
 	f.printStr(" ")
 	printStmt(f, fn.Body)
 }
+
+func printTypeParams(f *formatter, lst *ast.TypeParamList) {
+	if lst == nil {
+		return
+	}
+	f.printToken(lst.Lbrack)
+	for i, param := range lst.Params {
+		f.printToken(param.Ident)
+		if param.Constraint != nil {
+			f.printExprs(" ", param.Constraint)
+		}
+		if i < len(lst.Commas) {
+			f.printExprs(lst.Commas[i], " ")
+		}
+	}
+	f.printToken(lst.Rbrack)
+}
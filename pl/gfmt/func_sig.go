@@ -13,6 +13,9 @@ func printParaList(f *formatter, lst *ast.ParaList) {
 				f.printStr(" ")
 			}
 		}
+		if para.Dots != nil {
+			f.printToken(para.Dots)
+		}
 
 		if para.Type != nil {
 			f.printExpr(para.Type)
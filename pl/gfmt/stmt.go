@@ -70,6 +70,11 @@ func printStmt(f *formatter, stmt ast.Stmt) {
 				printStmt(f, stmt.Iter)
 			}
 			f.printSpace()
+		} else if r := stmt.Range; r != nil {
+			if r.Left != nil {
+				f.printExprs(r.Left, " ", r.Assign, " ")
+			}
+			f.printExprs(r.Kw, " ", r.Expr, " ")
 		} else if stmt.Cond != nil {
 			f.printExprs(stmt.Cond, " ")
 		}
@@ -79,17 +84,14 @@ func printStmt(f *formatter, stmt ast.Stmt) {
 		if stmt.Expr != nil {
 			f.printExprs(stmt.Expr, " ")
 		}
-		if !sameLine(stmt.Lbrace, stmt.Rbrace) || len(stmt.Cases) > 0 {
-			f.printToken(stmt.Lbrace)
-			f.printEndl()
-			for _, c := range stmt.Cases {
-				printCase(f, c)
-			}
-			f.printToken(stmt.Rbrace)
-		} else {
-			f.printToken(stmt.Lbrace)
-			f.printToken(stmt.Rbrace)
+		printCases(f, stmt.Lbrace, stmt.Cases, stmt.Rbrace)
+	case *ast.TypeSwitchStmt:
+		f.printExprs(stmt.Kw, " ")
+		if stmt.Var != nil {
+			f.printExprs(stmt.Var, " ", stmt.Define, " ")
 		}
+		f.printExprs(stmt.Expr, " ")
+		printCases(f, stmt.Lbrace, stmt.Cases, stmt.Rbrace)
 	case *ast.AssignStmt:
 		f.printExprs(stmt.Left, " ", stmt.Assign, " ", stmt.Right)
 	case *ast.DefineStmt:
@@ -103,6 +105,17 @@ func printStmt(f *formatter, stmt ast.Stmt) {
 		if stmt.Exprs != nil {
 			f.printExprs(" ", stmt.Exprs)
 		}
+	case *ast.DeferStmt:
+		f.printToken(stmt.Kw)
+		f.printExprs(" ", stmt.Call)
+	case *ast.GoStmt:
+		f.printToken(stmt.Kw)
+		f.printExprs(" ", stmt.Call)
+	case *ast.SendStmt:
+		f.printExprs(stmt.Chan, " ", stmt.Arrow, " ", stmt.Val)
+	case *ast.SelectStmt:
+		f.printExprs(stmt.Kw, " ")
+		printSelectCases(f, stmt)
 	case *ast.ContinueStmt:
 		f.printToken(stmt.Kw)
 		if stmt.Label != nil {
@@ -113,6 +126,14 @@ func printStmt(f *formatter, stmt ast.Stmt) {
 		if stmt.Label != nil {
 			f.printExprs(" ", stmt.Label)
 		}
+	case *ast.GotoStmt:
+		f.printExprs(stmt.Kw, " ", stmt.Label)
+	case *ast.LabeledStmt:
+		f.printExprs(stmt.Label, stmt.Colon)
+		if _, ok := stmt.Stmt.(*ast.EmptyStmt); !ok {
+			f.printEndl()
+			printStmt(f, stmt.Stmt)
+		}
 	case *ast.FallthroughStmt:
 		f.printToken(stmt.Kw)
 	case *ast.VarDecls:
@@ -124,6 +145,23 @@ func printStmt(f *formatter, stmt ast.Stmt) {
 	}
 }
 
+func printCases(
+	f *formatter, lbrace *lexing.Token, cases []*ast.Case,
+	rbrace *lexing.Token,
+) {
+	if !sameLine(lbrace, rbrace) || len(cases) > 0 {
+		f.printToken(lbrace)
+		f.printEndl()
+		for _, c := range cases {
+			printCase(f, c)
+		}
+		f.printToken(rbrace)
+	} else {
+		f.printToken(lbrace)
+		f.printToken(rbrace)
+	}
+}
+
 func printCase(f *formatter, c *ast.Case) {
 	f.printExprs(c.Kw)
 	if c.Expr != nil {
@@ -142,3 +180,28 @@ func printCase(f *formatter, c *ast.Case) {
 	}
 	f.ShiftTab()
 }
+
+func printSelectCases(f *formatter, stmt *ast.SelectStmt) {
+	if sameLine(stmt.Lbrace, stmt.Rbrace) && len(stmt.Cases) == 0 {
+		f.printExprs(stmt.Lbrace, stmt.Rbrace)
+		return
+	}
+	f.printToken(stmt.Lbrace)
+	f.printEndl()
+	for _, c := range stmt.Cases {
+		f.printToken(c.Kw)
+		if c.Comm != nil {
+			f.printSpace()
+			printStmt(f, c.Comm)
+		}
+		f.printToken(c.Colon)
+		f.printEndl()
+		f.Tab()
+		for _, s := range c.Stmts {
+			printStmt(f, s)
+			f.printGap()
+		}
+		f.ShiftTab()
+	}
+	f.printToken(stmt.Rbrace)
+}
@@ -5,7 +5,9 @@ import (
 )
 
 func printStruct(f *formatter, d *ast.Struct) {
-	f.printExprs(d.Kw, " ", d.Name, " ", d.Lbrace)
+	f.printExprs(d.Kw, " ", d.Name)
+	printTypeParams(f, d.TypeParams)
+	f.printExprs(" ", d.Lbrace)
 	if len(d.Fields) == 0 {
 		f.printToken(d.Rbrace)
 		return
@@ -16,8 +18,10 @@ func printStruct(f *formatter, d *ast.Struct) {
 		if i > 0 {
 			f.printGap()
 		}
-		printIdents(f, field.Idents)
-		f.printSpace()
+		if field.Idents != nil {
+			printIdents(f, field.Idents)
+			f.printSpace()
+		}
 		f.printExprs(field.Type)
 	}
 	f.printEndl()
@@ -60,15 +60,34 @@ func binaryOpUint(b *builder, op string, A, B *ref, t types.T) *ref {
 }
 
 func binaryOpConst(b *builder, op string, A, B *ref) *ref {
-	va, _ := types.NumConst(A.Type())
-	vb, _ := types.NumConst(B.Type())
-
 	br := func(b bool) *ref {
 		if b {
 			return refTrue
 		}
 		return refFalse
 	}
+
+	if sa, ok := types.StringConst(A.Type()); ok {
+		sb, _ := types.StringConst(B.Type())
+		switch op {
+		case "==":
+			return br(sa == sb)
+		case "!=":
+			return br(sa != sb)
+		case ">":
+			return br(sa > sb)
+		case "<":
+			return br(sa < sb)
+		case ">=":
+			return br(sa >= sb)
+		case "<=":
+			return br(sa <= sb)
+		}
+		panic("bug")
+	}
+
+	va, _ := types.NumConst(A.Type())
+	vb, _ := types.NumConst(B.Type())
 	switch op {
 	case "==":
 		return br(va == vb)
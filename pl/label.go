@@ -0,0 +1,60 @@
+package pl
+
+import (
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+)
+
+// labels saves the labeled blocks of the function being built.
+type labels struct {
+	blocks  map[string]*codegen.Block
+	pending map[string][]*codegen.Block // gotos that jump forward
+
+	// the label of the loop or switch statement to build next
+	next string
+}
+
+func newLabels() *labels {
+	return &labels{
+		blocks:  make(map[string]*codegen.Block),
+		pending: make(map[string][]*codegen.Block),
+	}
+}
+
+// take returns the label of the loop or switch statement being built,
+// or "" if the statement has no label.
+func (ls *labels) take() string {
+	ret := ls.next
+	ls.next = ""
+	return ret
+}
+
+func buildLabeledStmt(b *builder, stmt *tast.LabeledStmt) {
+	blk := b.f.NewBlock(b.b)
+	b.b = blk
+
+	ls := b.labels
+	ls.blocks[stmt.Label] = blk
+	for _, g := range ls.pending[stmt.Label] {
+		g.Jump(blk)
+	}
+	delete(ls.pending, stmt.Label)
+
+	switch stmt.Stmt.(type) {
+	case *tast.ForStmt, *tast.RangeStmt, *tast.SwitchStmt,
+		*tast.TypeSwitchStmt, *tast.SelectStmt:
+		ls.next = stmt.Label
+	}
+	b.buildStmt(stmt.Stmt)
+}
+
+func buildGotoStmt(b *builder, stmt *tast.GotoStmt) {
+	after := b.f.NewBlock(b.b)
+	ls := b.labels
+	if blk := ls.blocks[stmt.Label]; blk != nil {
+		b.b.Jump(blk)
+	} else {
+		ls.pending[stmt.Label] = append(ls.pending[stmt.Label], b.b)
+	}
+	b.b = after
+}
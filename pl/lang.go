@@ -16,7 +16,8 @@ import (
 )
 
 type lang struct {
-	golike bool
+	golike  bool
+	runtime bool // building the runtime package
 }
 
 // Lang returns the G language builder for the building system
@@ -37,6 +38,9 @@ func (l *lang) Prepare(src *builds.FileSet) (
 ) {
 	ret := builds.NewImportList()
 	ret.Add("$", BuiltInPkg, nil)
+	if !l.runtime {
+		ret.Add("$runtime", RuntimePkg, nil)
+	}
 
 	if f := src.OnlyFile(); f != nil {
 		if errs := listImport(f.Path, f, l.golike, ret); errs != nil {
@@ -71,6 +75,12 @@ func initBuilder(b *builder, imp map[string]*builds.Import) {
 	}
 
 	declareBuiltin(b, builtin.Lib)
+
+	if rt, ok := imp["$runtime"]; ok {
+		declareRuntime(b, rt.Lib)
+	} else {
+		declareRuntimeBuiltin(b, builtin.Lib)
+	}
 }
 
 // parse all files
@@ -206,6 +216,7 @@ func (l *lang) Compile(pinfo *builds.PkgInfo) (
 	if es = b.Errs(); es != nil {
 		return nil, es
 	}
+	b.golike = l.golike
 
 	p := newPkg(asts)
 	if es := p.build(b, pinfo); es != nil {
@@ -0,0 +1,132 @@
+package pl
+
+import (
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// callRuntime calls a runtime function that returns a word.
+func callRuntime(b *builder, f codegen.Ref, args ...codegen.Ref) codegen.Ref {
+	ret := b.newTempIR(types.Uint)
+	b.b.Call([]codegen.Ref{ret}, f, args...)
+	return ret
+}
+
+// mapKeyAddr saves a key into a temp and returns the address of the temp.
+func mapKeyAddr(b *builder, k *ref) codegen.Ref {
+	tmp := b.newTempIR(k.Type())
+	b.b.Assign(tmp, k.IR())
+	ret := b.newPtr()
+	b.b.Arith(ret, nil, "&", tmp)
+	return ret
+}
+
+// mapValOffset is the offset of the value from the key in a map entry.
+func mapValOffset(t *types.Map) int32 { return (t.Key.Size() + 3) / 4 * 4 }
+
+// elemAt is the element of type t at offset of address p.
+func elemAt(p codegen.Ref, t types.T, offset int32) codegen.Ref {
+	return codegen.NewAddrRef(
+		p,                             // base
+		t.Size(),                      // size
+		offset,                        // offset
+		types.IsBasic(t, types.Uint8), // is byte?
+		t.RegSizeAlign(),              // is aligned?
+	)
+}
+
+func newMap(b *builder, t *types.Map) *ref {
+	ret := b.newTemp(t)
+	keySize := codegen.Num(uint32(t.Key.Size()))
+	valSize := codegen.Num(uint32(t.Val.Size()))
+	keyType := b.typeDesc(t.Key)
+	valType := b.typeDesc(t.Val)
+	b.b.Call([]codegen.Ref{ret.IR()}, b.rt.mapMake,
+		keySize, valSize, keyType, valType,
+	)
+	return ret
+}
+
+func buildMapLit(b *builder, lit *tast.MapLit) *ref {
+	t := types.Underlying(lit.Type()).(*types.Map)
+	ret := newMap(b, t)
+	for i, k := range lit.Keys {
+		addr := mapKeyAddr(b, b.buildExpr(k))
+		v := b.buildExpr(lit.Vals[i])
+		p := callRuntime(b, b.rt.mapAssign, ret.IR(), addr)
+		b.b.Assign(elemAt(p, t.Val, 0), v.IR())
+	}
+	return ret
+}
+
+func buildMapIndex(b *builder, expr *tast.MapIndex) *ref {
+	m := b.buildExpr(expr.Map)
+	t := m.Type().(*types.Map)
+	addr := mapKeyAddr(b, b.buildExpr(expr.Key))
+
+	if expr.Assign {
+		p := callRuntime(b, b.rt.mapAssign, m.IR(), addr)
+		return newAddressableRef(t.Val, elemAt(p, t.Val, 0))
+	}
+
+	p := callRuntime(b, b.rt.mapAccess, m.IR(), addr)
+	ret := b.newTemp(t.Val)
+	b.b.Zero(ret.IR())
+	found := b.newCond()
+	b.b.Arith(found, nil, "?", p)
+
+	body := b.f.NewBlock(b.b)
+	after := b.f.NewBlock(body)
+	b.b.JumpIfNot(found, after)
+	b.b = body
+	b.b.Assign(ret.IR(), elemAt(p, t.Val, 0))
+	b.b = after
+
+	if expr.CommaOk {
+		return appendRef(ret, newRef(types.Bool, found))
+	}
+	return ret
+}
+
+func buildCallDelete(b *builder, expr *tast.CallExpr) *ref {
+	args := b.buildExpr(expr.Args)
+	addr := mapKeyAddr(b, args.At(1))
+	b.b.Call(nil, b.rt.mapDelete, args.At(0).IR(), addr)
+	return new(ref)
+}
+
+func buildRangeMap(b *builder, stmt *tast.RangeStmt, x *ref) {
+	t := x.Type().(*types.Map)
+	m := b.newTempIR(t)
+	b.b.Assign(m, x.IR())
+	iter := b.f.NewTemp(8, false, true)
+	iter.(*codegen.Var).Ptrs = []int32{4} // the current entry
+	b.b.Zero(iter)
+	iterAddr := b.newPtr()
+	b.b.Arith(iterAddr, nil, "&", iter)
+
+	condBlock := b.f.NewBlock(b.b)
+	body := b.f.NewBlock(condBlock)
+	next := b.f.NewBlock(body)
+	after := b.f.NewBlock(next)
+	next.Jump(condBlock)
+
+	b.b = condBlock
+	p := callRuntime(b, b.rt.mapNext, m, iterAddr)
+	found := b.newCond()
+	b.b.Arith(found, nil, "?", p)
+	b.b.JumpIfNot(found, after)
+
+	b.b = body
+	if stmt.Key != nil {
+		k := b.buildExpr(stmt.Key)
+		b.b.Assign(k.IR(), elemAt(p, t.Key, 0))
+	}
+	if stmt.Value != nil {
+		v := b.buildExpr(stmt.Value)
+		b.b.Assign(v.IR(), elemAt(p, t.Val, mapValOffset(t)))
+	}
+
+	buildRangeBody(b, stmt, next, after)
+}
@@ -1,6 +1,7 @@
 package pl
 
 import (
+	"shanhu.io/smlvm/arch"
 	"shanhu.io/smlvm/asm"
 	"shanhu.io/smlvm/pl/codegen"
 	"shanhu.io/smlvm/pl/tast"
@@ -23,12 +24,10 @@ func buildPkgSym(b *builder, m *tast.MemberExpr, pkg *types.Pkg) *ref {
 			if !types.SameType(m.Type(), types.VoidFunc) {
 				panic("bug")
 			}
-			return newRef(
-				types.VoidFunc,
-				codegen.NewFuncSym(
-					sym.Pkg(), sym.Name(), codegen.VoidFuncSig,
-				),
+			f := codegen.NewFuncSym(
+				sym.Pkg(), sym.Name(), codegen.VoidFuncSig,
 			)
+			return newRef(types.VoidFunc, b.p.NewFuncVal(f))
 		}
 		panic("bug")
 	}
@@ -39,7 +38,7 @@ func buildPkgSym(b *builder, m *tast.MemberExpr, pkg *types.Pkg) *ref {
 	case tast.SymVar:
 		return sym.Obj.(*objVar).ref
 	case tast.SymFunc:
-		return sym.Obj.(*objFunc).ref
+		return funcVal(b, sym.Obj.(*objFunc).ref)
 	}
 	panic("bug")
 }
@@ -55,14 +54,11 @@ func buildMember(b *builder, m *tast.MemberExpr) *ref {
 		return buildPkgSym(b, m, pkg)
 	}
 
-	pt := types.PointerOf(t)
-	var tstruct *types.Struct
-	if pt != nil {
-		tstruct = pt.(*types.Struct)
-	} else {
-		tstruct = t.(*types.Struct)
+	if i, ok := t.(*types.Interface); ok {
+		return buildInterfaceMethod(b, m, obj, i)
 	}
 
+	pt := types.PointerOf(t)
 	addr := b.newPtr()
 	if pt != nil {
 		b.b.Assign(addr, obj.IR())
@@ -77,11 +73,39 @@ func buildMember(b *builder, m *tast.MemberExpr) *ref {
 	if sym.Type == tast.SymField {
 		return buildField(b, addr, sym.Obj.(*types.Field))
 	} else if sym.Type == tast.SymFunc {
-		recv := newRef(types.NewPointer(tstruct), addr)
 		method := sym.Obj.(*objFunc)
 		ft := method.Type().(*types.Func)
+		recv := newRef(ft.Args[0].T, addr)
 		return newRecvRef(ft, recv, method.IR())
 	}
 
 	panic("bug")
 }
+
+// buildInterfaceMethod loads the receiver and the method function value
+// from an interface value. The function value is the address of the
+// method's slot in the vtable.
+func buildInterfaceMethod(
+	b *builder, m *tast.MemberExpr, obj *ref, i *types.Interface,
+) *ref {
+	addr := b.newPtr()
+	b.b.Arith(addr, nil, "&", obj.IR())
+	tab := b.newPtr()
+	b.b.Assign(tab, codegen.NewAddrRef(
+		addr, arch.RegSize, arch.RegSize, false, true,
+	))
+	nilPointerPanic(b, tab)
+
+	recv := b.newPtr()
+	b.b.Assign(recv, codegen.NewAddrRef(addr, arch.RegSize, 0, false, true))
+
+	index := b.vTable(i).methodIndex(m.Sym.Name())
+	f := b.newPtr()
+	offset := codegen.Num(uint32(index) * arch.RegSize)
+	b.b.Arith(f, tab, "+", offset)
+
+	ft := m.Sym.ObjType.(*types.Func)
+	this := &types.Arg{T: types.NewPointer(i)}
+	recvFunc := types.NewFunc(this, ft.Args, ft.Rets)
+	return newRecvRef(recvFunc, newRef(this.T, recv), f)
+}
@@ -116,6 +116,17 @@ func TestMultiFile(t *testing.T) {
 			func main() { printInt(f()) }`,
 	}, "33")
 
+	o(files{
+		"a/a.g": `struct A { I, J int }`,
+		"main/m.g": `
+			import ("a")
+			func main() {
+				v := a.A{I: 3}
+				w := &a.A{4, 5}
+				printInt(v.I + w.J)
+			}`,
+	}, "8")
+
 	// A bug found when writing mempair.
 	o(files{
 		"a/a.g": `struct A { I int }`,
@@ -131,6 +142,105 @@ func TestMultiFile(t *testing.T) {
 				printInt(v.I)
 			}`,
 	}, "33")
+
+	// type assertions on a vtable built in another package
+	o(files{
+		"a/a.g": `
+			struct A { I int }
+			func (a *A) Get() int { return a.I }
+			interface G { Get() int }
+			func New(i int) G {
+				ret := new(A)
+				ret.I = i
+				return ret
+			}`,
+		"main/m.g": `
+			import ("a")
+			struct B {}
+			func (b *B) Get() int { return 0 }
+			func main() {
+				g := a.New(33)
+				printInt(g.(*a.A).I)
+				_, ok := g.(*B)
+				if !ok { printInt(44) }
+				switch v := g.(type) {
+				case *B:
+					printInt(0)
+				case *a.A:
+					printInt(v.Get() + 22)
+				}
+			}`,
+	}, "33\n44\n55")
+
+	// embedding a struct from another package
+	o(files{
+		"a/a.g": `
+			struct A { I int; j int }
+			func (a *A) Get() int { return a.I + a.j }
+			func (a *A) Set(i int) { a.I, a.j = i, 1 }`,
+		"b/b.g": `
+			import ("a")
+			struct B { a.A }`,
+		"main/m.g": `
+			import ("b")
+			struct C { *b.B }
+			interface G { Get() int }
+			func main() {
+				c := &C{new(b.B)}
+				c.Set(32)
+				var g G = c
+				printInt(g.Get())
+				var g2 G = c.B
+				printInt(g2.Get() + c.I)
+			}`,
+	}, "33\n65")
+
+	// named types from another package
+	o(files{
+		"a/a.g": `
+			type Meters int
+			func (m *Meters) Double() Meters { return *m * 2 }`,
+		"main/m.g": `
+			import ("a")
+			interface D { Double() a.Meters }
+			func main() {
+				m := a.Meters(21)
+				var d D = &m
+				printInt(int(d.Double() + m.Double()))
+			}`,
+	}, "84")
+
+	// generics from another package
+	o(files{
+		"a/a.g": `
+			func twice(x int) int { return x * 2 }
+			func Max[T integer](a, b T) T {
+				if a > b { return a }
+				return b
+			}
+			struct Box[T any] { v T }
+			func (b *Box[T]) Set(v T) { b.v = v }
+			func (b *Box[T]) Get() T { return b.v }
+			func Double(b *Box[int]) int { return twice(b.v) }`,
+		"b/b.g": `
+			import ("a")
+			func NewBox(v int) *a.Box[int] {
+				ret := new(a.Box[int])
+				ret.Set(v)
+				return ret
+			}`,
+		"main/m.g": `
+			import ("a"; "b")
+			interface Getter { Get() int }
+			func main() {
+				box := b.NewBox(a.Max(3, 21))
+				var g Getter = box
+				printInt(g.Get() + a.Double(box))
+				var c a.Box[string]
+				c.Set("hi")
+				printInt(len(c.Get()))
+			}`,
+	}, "63\n2")
 }
 
 func TestMultiFileBad(t *testing.T) {
@@ -172,6 +282,23 @@ func TestMultiFileBad(t *testing.T) {
 		"main/a.g": `import ("a"); func main() { a.f() };`,
 	})
 
+	// using private fields in struct literals
+	o(files{
+		"a/a.g":    `struct A { I, j int }`,
+		"main/a.g": `import ("a"); func main() { _ := a.A{j: 1} };`,
+	})
+	o(files{
+		"a/a.g":    `struct A { I, j int }`,
+		"main/a.g": `import ("a"); func main() { _ := a.A{1, 2} };`,
+	})
+
+	// using private fields promoted from another package
+	o(files{
+		"a/a.g": `struct A { I, j int }`,
+		"main/a.g": `import ("a"); struct B { a.A }
+			func main() { var b B; b.j = 1 };`,
+	})
+
 	o(files{
 		"asm/a/a.g": `
 			func A {
@@ -14,10 +14,14 @@ func buildOpExpr(b *builder, expr *tast.OpExpr) *ref {
 
 func buildUnaryOpExpr(b *builder, expr *tast.OpExpr) *ref {
 	op := expr.Op.Lit
+	if lit, ok := expr.B.(*tast.StructLit); ok && op == "&" && b.rt != nil {
+		return buildStructLitPtr(b, lit, expr.R().T)
+	}
+
 	B := b.buildExpr(expr.B)
 	btyp := B.Type()
 	if op == "&" {
-		ret := b.newTemp(&types.Pointer{T: btyp})
+		ret := b.newTemp(expr.R().T)
 		b.b.Arith(ret.IR(), nil, op, B.IR())
 		return ret
 	} else if types.IsConst(btyp) {
@@ -26,6 +30,8 @@ func buildUnaryOpExpr(b *builder, expr *tast.OpExpr) *ref {
 		return unaryOpInt(b, op, B)
 	} else if types.IsBasic(btyp, types.Bool) {
 		return unaryOpBool(b, op, B)
+	} else if types.IsBasic(btyp, types.Float32) {
+		return unaryOpFloat(b, op, B)
 	}
 	panic("bug")
 }
@@ -64,6 +70,8 @@ func buildBinaryOpExpr(b *builder, expr *tast.OpExpr) *ref {
 			return binaryOpUint(b, op, A, B, t)
 		case types.Bool:
 			return binaryOpBool(b, op, A, B)
+		case types.Float32:
+			return binaryOpFloat(b, op, A, B)
 		}
 		panic("bug")
 	}
@@ -74,8 +82,14 @@ func buildBinaryOpExpr(b *builder, expr *tast.OpExpr) *ref {
 		return binaryOpPtr(b, op, A, B)
 	} else if types.BothFuncPointer(atyp, btyp) {
 		return binaryOpPtr(b, op, A, B)
+	} else if types.BothMap(atyp, btyp) {
+		return binaryOpPtr(b, op, A, B)
+	} else if types.BothChan(atyp, btyp) {
+		return binaryOpPtr(b, op, A, B)
 	} else if types.BothSlice(atyp, btyp) {
 		return binaryOpSlice(b, op, A, B)
+	} else if types.BothInterface(atyp, btyp) {
+		return binaryOpInterface(b, op, A, B)
 	}
 	panic("bug")
 }
@@ -14,8 +14,16 @@ func buildConst(b *builder, c *tast.Const) *ref {
 		return newRef(c.T, nil)
 	}
 
+	if _, ok := types.StringConst(c.T); ok {
+		// string consts are only for comparing
+		return newRef(c.T, nil)
+	}
+
 	if ct, ok := c.T.(*types.Const); ok {
 		// typed consts
+		if v, ok := ct.Value.(float64); ok {
+			return newRef(ct.Type, constFloatIr(v))
+		}
 		return newRef(ct.Type, constNumIr(ct.Value.(int64), ct.Type))
 	}
 
@@ -27,14 +35,24 @@ func buildConst(b *builder, c *tast.Const) *ref {
 	}
 
 	// build slice literal
-	if t, ok := c.T.(*types.Slice); ok {
+	if t, ok := types.Underlying(c.T).(*types.Slice); ok {
 		if bt, ok := t.T.(types.Basic); ok {
 			switch bt {
-			case types.Int, types.Uint, types.Int8, types.Uint8, types.Bool:
+			case types.Int, types.Uint, types.Int8, types.Uint8,
+				types.Bool, types.Float32:
 				bs := c.ConstValue.([]byte)
-				ret := b.newTemp(t)
+				ret := b.newTemp(c.T)
 				ref := b.p.NewHeapDat(bs, bt.Size(), bt.RegSizeAlign())
 				b.b.Arith(ret.IR(), nil, "makeDat", ref)
+				if len(bs) == 0 || b.rt == nil {
+					return ret
+				}
+
+				// every evaluation gets a new copy of the data
+				empty := b.newTemp(t)
+				b.b.Zero(empty.IR())
+				cp := buildAppendSlice(b, empty, newRef(t, ret.IR()))
+				b.b.Assign(ret.IR(), cp.IR())
 				return ret
 			default:
 				panic("other const slices not supported")
@@ -44,6 +62,16 @@ func buildConst(b *builder, c *tast.Const) *ref {
 		}
 	}
 
+	// build constant array literal
+	if t, ok := types.Underlying(c.T).(*types.Array); ok {
+		bt := t.T.(types.Basic)
+		bs := c.ConstValue.([]byte)
+		ret := b.newTemp(c.T)
+		ref := b.p.NewHeapDat(bs, bt.Size(), bt.RegSizeAlign())
+		b.b.Assign(ret.IR(), ref)
+		return ret
+	}
+
 	panic("other const types not supported")
 }
 
@@ -66,7 +94,7 @@ func buildIdent(b *builder, id *tast.Ident) *ref {
 	case tast.SymFunc:
 		v := s.Obj.(*objFunc)
 		if !v.isMethod {
-			return v.ref
+			return funcVal(b, v.ref)
 		}
 		if b.this == nil {
 			panic("this missing")
@@ -77,7 +105,7 @@ func buildIdent(b *builder, id *tast.Ident) *ref {
 	case tast.SymField:
 		v := s.Obj.(*types.Field)
 		return buildField(b, b.this.IR(), v)
-	case tast.SymImport:
+	case tast.SymImport, tast.SymNamed, tast.SymStruct, tast.SymType:
 		t := s.ObjType.(types.T)
 		return newRef(t, nil)
 	}
@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"shanhu.io/smlvm/lexing"
 	"shanhu.io/smlvm/pl/ast"
 )
 
@@ -22,10 +23,27 @@ func parseCallExpr(p *parser, lead ast.Expr) *ast.CallExpr {
 		}
 	}
 
-	lst := parseExprListClosed(p, ")")
+	// the last argument can be followed by "..."
+	var dots *lexing.Token
+	n, dotsAt := 0, 0
+	parseArg := func(p *parser) ast.Expr {
+		expr := p.parseExpr()
+		if expr != nil && p.SeeOp("...") {
+			dots, dotsAt = p.Shift(), n
+		}
+		n++
+		return expr
+	}
+
+	lst := parseListClosed(p, ")", parseArg)
 	if p.InError() {
 		return nil
 	}
+	if dots != nil && dotsAt != lst.Len()-1 {
+		p.CodeErrorf(dots.Pos, "pl.call.dotsNotLast",
+			"can only use ... with the last argument")
+		return nil
+	}
 	rp := p.ExpectOp(")")
 	if rp == nil {
 		return nil
@@ -34,6 +52,7 @@ func parseCallExpr(p *parser, lead ast.Expr) *ast.CallExpr {
 	return &ast.CallExpr{
 		Func:   lead,
 		Args:   lst,
+		Dots:   dots,
 		Lparen: lp,
 		Rparen: rp,
 	}
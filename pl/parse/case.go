@@ -5,10 +5,12 @@ import (
 	"shanhu.io/smlvm/pl/ast"
 )
 
-func parseCases(p *parser) []*ast.Case {
+// parseCases parses the cases of a switch. The cases of a type switch
+// list types.
+func parseCases(p *parser, typeSwitch bool) []*ast.Case {
 	var ret []*ast.Case
 	for !(p.SeeOp("}") || p.See(lexing.EOF)) {
-		if c := parseCase(p); c != nil {
+		if c := parseCase(p, typeSwitch); c != nil {
 			ret = append(ret, c)
 		}
 		p.skipErrStmt()
@@ -16,13 +18,21 @@ func parseCases(p *parser) []*ast.Case {
 	return ret
 }
 
-func parseCase(p *parser) *ast.Case {
+func parseCase(p *parser, typeSwitch bool) *ast.Case {
 	ret := new(ast.Case)
 	if p.SeeKeyword("case") {
 		ret.Kw = p.Shift()
-		ret.Expr = parseExpr(p)
-		if ret.Expr == nil {
-			return nil
+		if typeSwitch {
+			lst := parseTypeList(p)
+			if lst == nil {
+				return nil
+			}
+			ret.Expr = lst
+		} else {
+			ret.Expr = parseExpr(p)
+			if ret.Expr == nil {
+				return nil
+			}
 		}
 	} else if p.SeeKeyword("default") {
 		ret.Kw = p.Shift()
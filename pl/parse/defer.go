@@ -0,0 +1,13 @@
+package parse
+
+import (
+	"shanhu.io/smlvm/pl/ast"
+)
+
+func parseDeferStmt(p *parser) *ast.DeferStmt {
+	ret := new(ast.DeferStmt)
+	ret.Kw = p.ExpectKeyword("defer")
+	ret.Call = p.parseExpr()
+	ret.Semi = p.ExpectSemi()
+	return ret
+}
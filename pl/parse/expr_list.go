@@ -4,11 +4,15 @@ import (
 	"shanhu.io/smlvm/pl/ast"
 )
 
-func parseExprListClosed(p *parser, closeWith string) *ast.ExprList {
+func parseListClosed(
+	p *parser, closeWith string, f func(p *parser) ast.Expr,
+) *ast.ExprList {
 	ret := new(ast.ExprList)
+	p.exprLev++
+	defer func() { p.exprLev-- }()
 
 	for {
-		expr := p.parseExpr()
+		expr := f(p)
 		if expr == nil {
 			return nil
 		}
@@ -31,6 +35,41 @@ func parseExprListClosed(p *parser, closeWith string) *ast.ExprList {
 	}
 }
 
+func parseExprListClosed(p *parser, closeWith string) *ast.ExprList {
+	return parseListClosed(p, closeWith, (*parser).parseExpr)
+}
+
+// parseElemExpr parses a key or a value in a literal, which can be a
+// literal with the type elided.
+func parseElemExpr(p *parser) ast.Expr {
+	if p.SeeOp("{") {
+		return parseStructLit(p, nil)
+	}
+	return p.parseExpr()
+}
+
+// parseElem parses an element in a literal, which can be keyed.
+func parseElem(p *parser) ast.Expr {
+	expr := parseElemExpr(p)
+	if expr == nil {
+		return nil
+	}
+	if !p.SeeOp(":") {
+		return expr
+	}
+
+	colon := p.Shift()
+	value := parseElemExpr(p)
+	if value == nil {
+		return nil
+	}
+	return &ast.KeyValueExpr{Key: expr, Colon: colon, Value: value}
+}
+
+func parseElemListClosed(p *parser, closeWith string) *ast.ExprList {
+	return parseListClosed(p, closeWith, parseElem)
+}
+
 func parseExprList(p *parser) *ast.ExprList {
 	ret := new(ast.ExprList)
 	for {
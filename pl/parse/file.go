@@ -22,9 +22,9 @@ func parseTopDecl(p *parser) ast.Decl {
 	} else if !p.golike && p.SeeKeyword("struct") {
 		return parseStruct(p)
 	} else if !p.golike && p.SeeLit(Ident, "type") {
-		return parseStruct(p)
-	} else if p.SeeKeyword("type") && p.golike {
-		return parseStruct(p)
+		return parseTypeDecl(p)
+	} else if p.golike && p.SeeKeyword("type") {
+		return parseTypeDecl(p)
 	} else if p.SeeKeyword("import") {
 		p.CodeErrorfHere("pl.multiImport",
 			"only one import block allowed at the head")
@@ -22,6 +22,9 @@ func TestFile_good(t *testing.T) {
 		"func f() (a int, b int) {}",
 		"func f(int) (a int, b int) {}",
 		"func f(int) (a int, b int,) {}",
+		"func f(a ...int) {}",
+		"func f(a int, b ...string) {}",
+		"func f(...int) {}",
 		`func f(int) (
 			a int,
 			b int,
@@ -45,6 +48,19 @@ func TestFile_good(t *testing.T) {
 		}`,
 		`interface T {
 		}`,
+		`struct A { B }`,
+		`struct A { *B; a int }`,
+		`struct A { b.B; *c.C }`,
+		"type A int",
+		"type A map[string]*b.B",
+		"func Max[T integer](a, b T) T { return a }",
+		"func F[K, V comparable, E any]() { var p Pair[K, []V]; _ := p }",
+		`struct List[T any] { items []T; next *List[T] }
+		func (l *List[T]) Push(v T) {}
+		func (p *Pair[K, V]) Get(k K) {}`,
+		"func f() { a := Max[int](1, 2); p := b.Pair[int, *b.B]{} }",
+		"func f(ch chan int) chan bool { go g(<-ch); return nil }",
+		"struct A { ch chan *A }",
 	} {
 		buf := strings.NewReader(s)
 		f, _, es := File("test.g", buf, false)
@@ -97,6 +113,9 @@ func TestFile_bad(t *testing.T) {
 	o("expectOp", "func f)")
 	o("expectOp", "func f; {}")
 	o("expectReturnList", "func f(a int) () {}")
+	o("variadic.notLast", "func f(a ...int, b int) {}")
+	o("variadic.notLast", "func f(...int, int) {}")
+	o("variadic.inRets", "func f() (a ...int) {}")
 	o("expectType", "func f(,a) {}")
 	o("expectType", "func f(a int) (,a) {}")
 	o("expectOp", "func f(a b int) (,a) {}")
@@ -107,6 +126,59 @@ func TestFile_bad(t *testing.T) {
 	o("multiImport", "import (); import()")
 	o("expectType", `var (a "a")`)
 	o("expectType", `var (a "a";)`)
+	o("expectType", "struct A { a, b }")
+	o("generic.missingConstraint", "func F[K, V]() {}")
+}
+
+func TestFile_golike(t *testing.T) {
+	for _, s := range []string{
+		"package main",
+		"package main; func f() {}",
+		"package main; type A struct { a int }",
+		"package main; type I interface { f(a int) int }",
+		"package main; type I interface {}",
+		"package main; type A int; type F func(a A) []A",
+		`package main
+		import ( "a" )
+		type A struct {}
+		func (a *A) f() {}
+		func g(a int) {
+			switch a {
+			case 1:
+				fallthrough
+			default:
+			}
+		}`,
+	} {
+		buf := strings.NewReader(s)
+		f, _, es := File("test.g", buf, true)
+		if es != nil {
+			t.Log(s)
+			for _, e := range es {
+				t.Log(e)
+			}
+			t.Fail()
+		} else if f == nil {
+			t.Log(s)
+			t.Log("returned nil")
+			t.Fail()
+		}
+	}
+
+	for _, s := range []string{
+		"func f() {}",
+		"package main; struct A {}",
+		"package main; interface I {}",
+		"package main; type A",
+		"package main; func f() { if true return }",
+	} {
+		buf := strings.NewReader(s)
+		_, _, es := File("test.g", buf, true)
+		if es == nil {
+			t.Log(s)
+			t.Error("should fail")
+		}
+	}
 }
 
 func TestFileTokens(t *testing.T) {
@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"shanhu.io/smlvm/lexing"
 	"shanhu.io/smlvm/pl/ast"
 )
 
@@ -12,9 +13,16 @@ func parseForStmt(p *parser) *ast.ForStmt {
 
 	ret := new(ast.ForStmt)
 	ret.Kw = p.Shift()
-	if !p.SeeOp("{") {
+	lev := p.enterCtrl()
+	if p.SeeKeyword("range") {
+		ret.Range = parseRangeClause(p, nil, nil)
+	} else if !p.SeeOp("{") {
+		p.forRange = true
 		stmt, expr := parseSimpleStmtOrExpr(p, true)
-		if stmt != nil { // seeing a semicolon ending
+		p.forRange = false
+		if r, ok := stmt.(*ast.RangeClause); ok {
+			ret.Range = r
+		} else if stmt != nil { // seeing a semicolon ending
 			ret.ThreeFold = true
 
 			ret.Init = stmt
@@ -31,10 +39,10 @@ func parseForStmt(p *parser) *ast.ForStmt {
 		} else if expr != nil {
 			ret.Cond = expr
 		}
-
-		if p.InError() {
-			return ret
-		}
+	}
+	p.exitCtrl(lev)
+	if p.InError() {
+		return ret
 	}
 
 	ret.Body = parseBlock(p)
@@ -45,3 +53,13 @@ func parseForStmt(p *parser) *ast.ForStmt {
 	ret.Semi = p.ExpectSemi()
 	return ret
 }
+
+// range <expr>
+func parseRangeClause(
+	p *parser, left *ast.ExprList, assign *lexing.Token,
+) *ast.RangeClause {
+	ret := &ast.RangeClause{Left: left, Assign: assign}
+	ret.Kw = p.ExpectKeyword("range")
+	ret.Expr = p.parseExpr()
+	return ret
+}
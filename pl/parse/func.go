@@ -21,6 +21,9 @@ func parseFunc(p *parser) *ast.Func {
 		}
 		recv.Star = p.ExpectOp("*")
 		recv.StructName = p.Expect(Ident)
+		if p.SeeOp("[") {
+			recv.TypeParams = parseTypeParams(p, false)
+		}
 		recv.Rparen = p.ExpectOp(")")
 
 		ret.Recv = recv
@@ -31,6 +34,9 @@ func parseFunc(p *parser) *ast.Func {
 	}
 
 	ret.Name = p.Expect(Ident)
+	if p.SeeOp("[") {
+		ret.TypeParams = parseTypeParams(p, true)
+	}
 	if p.InError() {
 		return nil
 	}
@@ -0,0 +1,18 @@
+package parse
+
+import (
+	"shanhu.io/smlvm/pl/ast"
+)
+
+func parseFuncLit(p *parser, t *ast.FuncTypeExpr) ast.Expr {
+	// the body is a new statement context.
+	lev := p.exprLev
+	p.exprLev = 0
+	defer func() { p.exprLev = lev }()
+
+	body := parseBlock(p)
+	if p.InError() {
+		return nil
+	}
+	return &ast.FuncLiteral{Type: t, Body: body}
+}
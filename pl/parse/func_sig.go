@@ -13,11 +13,17 @@ func parsePara(p *parser) *ast.Para {
 			ret.Type = parseMemberExpr(p, ast.NewOperand(ident))
 		} else {
 			ret.Ident = ident
-			if !(p.SeeOp(",") || p.SeeOp(")")) {
+			if p.SeeOp("...") {
+				ret.Dots = p.Shift()
+				ret.Type = p.parseType()
+			} else if !(p.SeeOp(",") || p.SeeOp(")")) {
 				ret.Type = p.parseType()
 			}
 		}
 	} else {
+		if p.SeeOp("...") {
+			ret.Dots = p.Shift()
+		}
 		ret.Type = p.parseType()
 	}
 	return ret
@@ -56,6 +62,12 @@ func parseParaList(p *parser) *ast.ParaList {
 	}
 
 	ret.Rparen = p.ExpectOp(")")
+	for i, para := range ret.Paras {
+		if para.Dots != nil && i != len(ret.Paras)-1 {
+			p.CodeErrorf(para.Dots.Pos, "pl.variadic.notLast",
+				"can only use ... with the last parameter")
+		}
+	}
 	return ret
 }
 
@@ -66,7 +78,8 @@ func seeType(p *parser) bool {
 	if p.SeeOp("*", "[", "(") {
 		return true
 	}
-	if p.SeeKeyword("func") {
+	if p.SeeKeyword("func") || p.SeeKeyword("map") ||
+		p.SeeKeyword("chan") {
 		return true
 	}
 	return false
@@ -87,6 +100,12 @@ func parseFuncSig(p *parser) *ast.FuncSig {
 			p.CodeErrorf(ret.Rets.Rparen.Pos, "pl.expectReturnList",
 				"expect return list in \"()\" after the function")
 		}
+		for _, para := range ret.Rets.Paras {
+			if para.Dots != nil {
+				p.CodeErrorf(para.Dots.Pos, "pl.variadic.inRets",
+					"cannot use ... in the return list")
+			}
+		}
 	} else if seeType(p) {
 		ret.RetType = p.parseType()
 	}
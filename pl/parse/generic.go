@@ -0,0 +1,66 @@
+package parse
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+)
+
+// parseTypeParams parses a list of type parameters, like
+// "[K comparable, V any]". The type parameters of a method receiver
+// only have names, like "[K, V]", and withConstraint is false for them.
+func parseTypeParams(p *parser, withConstraint bool) *ast.TypeParamList {
+	ret := new(ast.TypeParamList)
+	ret.Lbrack = p.ExpectOp("[")
+	for !p.See(lexing.EOF) {
+		param := &ast.TypeParam{Ident: p.Expect(Ident)}
+		if withConstraint && p.See(Ident) {
+			param.Constraint = p.Shift()
+		}
+		if p.InError() {
+			return nil
+		}
+		ret.Params = append(ret.Params, param)
+		if !p.SeeOp(",") {
+			break
+		}
+		ret.Commas = append(ret.Commas, p.Shift())
+	}
+	ret.Rbrack = p.ExpectOp("]")
+	if p.InError() {
+		return nil
+	}
+
+	last := ret.Params[len(ret.Params)-1]
+	if withConstraint && last.Constraint == nil {
+		p.CodeErrorf(last.Ident.Pos, "pl.generic.missingConstraint",
+			"missing constraint of type parameter %s", last.Ident.Lit)
+		return nil
+	}
+	return ret
+}
+
+// parseTypeArgs parses the type arguments that instantiate a generic
+// type, like the "[int, string]" in "Pair[int, string]".
+func parseTypeArgs(p *parser, generic ast.Expr) ast.Expr {
+	ret := &ast.InstExpr{
+		Generic: generic,
+		Lbrack:  p.ExpectOp("["),
+		Types:   new(ast.ExprList),
+	}
+	for !p.See(lexing.EOF) {
+		t := p.parseType()
+		if t == nil {
+			return nil
+		}
+		ret.Types.Exprs = append(ret.Types.Exprs, t)
+		if !p.SeeOp(",") {
+			break
+		}
+		ret.Types.Commas = append(ret.Types.Commas, p.Shift())
+	}
+	ret.Rbrack = p.ExpectOp("]")
+	if p.InError() {
+		return nil
+	}
+	return ret
+}
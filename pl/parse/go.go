@@ -0,0 +1,13 @@
+package parse
+
+import (
+	"shanhu.io/smlvm/pl/ast"
+)
+
+func parseGoStmt(p *parser) *ast.GoStmt {
+	ret := new(ast.GoStmt)
+	ret.Kw = p.ExpectKeyword("go")
+	ret.Call = p.parseExpr()
+	ret.Semi = p.ExpectSemi()
+	return ret
+}
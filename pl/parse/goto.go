@@ -0,0 +1,27 @@
+package parse
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+)
+
+func parseGotoStmt(p *parser) *ast.GotoStmt {
+	ret := new(ast.GotoStmt)
+	ret.Kw = p.ExpectKeyword("goto")
+	ret.Label = p.Expect(Ident)
+	ret.Semi = p.ExpectSemi()
+	return ret
+}
+
+func parseLabeledStmt(p *parser, label *lexing.Token) *ast.LabeledStmt {
+	ret := new(ast.LabeledStmt)
+	ret.Label = label
+	ret.Colon = p.ExpectOp(":")
+	if p.SeeOp("}") {
+		// a label at the end of a block
+		ret.Stmt = new(ast.EmptyStmt)
+		return ret
+	}
+	ret.Stmt = p.parseStmt()
+	return ret
+}
@@ -14,7 +14,9 @@ func parseElse(p *parser) *ast.ElseStmt {
 
 	if p.SeeKeyword("if") {
 		ret.If = p.Shift()
+		lev := p.enterCtrl()
 		ret.Expr = parseExpr(p)
+		p.exitCtrl(lev)
 	}
 
 	if p.InError() {
@@ -70,7 +72,9 @@ func parseIfStmt(p *parser) *ast.IfStmt {
 
 	ret := new(ast.IfStmt)
 	ret.If = p.Shift()
+	lev := p.enterCtrl()
 	ret.Expr = parseExpr(p)
+	p.exitCtrl(lev)
 	if p.InError() {
 		return ret
 	}
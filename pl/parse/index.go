@@ -4,7 +4,7 @@ import (
 	"shanhu.io/smlvm/pl/ast"
 )
 
-func parseIndexExpr(p *parser, lead ast.Expr) *ast.IndexExpr {
+func parseIndexExpr(p *parser, lead ast.Expr) ast.Expr {
 	if !p.SeeOp("[") {
 		panic("parseIndexExpr() must start with '['")
 	}
@@ -13,12 +13,17 @@ func parseIndexExpr(p *parser, lead ast.Expr) *ast.IndexExpr {
 
 	ret.Array = lead
 	ret.Lbrack = p.Shift()
+	p.exprLev++
+	defer func() { p.exprLev-- }()
 
 	if !p.SeeOp(":") {
 		ret.Index = p.parseExpr()
 		if ret.Index == nil {
 			return nil
 		}
+		if p.SeeOp(",") {
+			return parseInstExprRest(p, ret)
+		}
 	}
 
 	if p.SeeOp(":") {
@@ -35,3 +40,26 @@ func parseIndexExpr(p *parser, lead ast.Expr) *ast.IndexExpr {
 
 	return ret
 }
+
+// parseInstExprRest parses the rest of the type arguments when an index
+// expression turns out to be an instantiation like "Pair[int, string]".
+func parseInstExprRest(p *parser, index *ast.IndexExpr) ast.Expr {
+	ret := &ast.InstExpr{
+		Generic: index.Array,
+		Lbrack:  index.Lbrack,
+		Types:   &ast.ExprList{Exprs: []ast.Expr{index.Index}},
+	}
+	for p.SeeOp(",") {
+		ret.Types.Commas = append(ret.Types.Commas, p.Shift())
+		t := p.parseExpr()
+		if t == nil {
+			return nil
+		}
+		ret.Types.Exprs = append(ret.Types.Exprs, t)
+	}
+	ret.Rbrack = p.ExpectOp("]")
+	if p.InError() {
+		return nil
+	}
+	return ret
+}
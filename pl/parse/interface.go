@@ -15,7 +15,10 @@ func parseInterface(p *parser) *ast.Interface {
 		Name:   p.Expect(Ident),
 		Lbrace: p.ExpectOp("{"),
 	}
+	return parseInterfaceBody(p, ret)
+}
 
+func parseInterfaceBody(p *parser, ret *ast.Interface) *ast.Interface {
 	for !p.SeeOp("}") && !p.See(lexing.EOF) {
 		name := p.Expect(Ident)
 		if p.InError() {
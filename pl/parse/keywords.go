@@ -10,13 +10,14 @@ func keywordSet(s ...string) map[string]struct{} {
 
 var gKeywords = keywordSet(
 	"func", "var", "const", "struct", "import", "interface",
-	"if", "else", "for", "break", "continue", "return",
-	"switch", "case", "default", "fallthrough",
+	"if", "else", "for", "break", "continue", "return", "defer",
+	"goto", "switch", "case", "default", "fallthrough",
+	"map", "range", "go", "chan", "select",
 )
 
 var golikeKeywords = keywordSet(
-	"func", "var", "const", "struct", "import",
-	"if", "else", "for",
-	"break", "continue", "return",
-	"package", "type",
+	"func", "var", "const", "struct", "import", "interface",
+	"if", "else", "for", "break", "continue", "return", "defer",
+	"goto", "switch", "case", "default", "fallthrough",
+	"package", "type", "map", "range", "go", "chan", "select",
 )
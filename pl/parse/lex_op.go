@@ -46,7 +46,7 @@ func lexOperator(x *lexing.Lexer, r rune) *lexing.Token {
 			if r3 == '=' {
 				x.Next()
 			}
-		} else if r2 == '=' {
+		} else if r2 == '=' || (r == '<' && r2 == '-') {
 			x.Next()
 		}
 	default:
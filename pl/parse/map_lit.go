@@ -0,0 +1,22 @@
+package parse
+
+import (
+	"shanhu.io/smlvm/pl/ast"
+)
+
+func parseMapLit(p *parser, t *ast.MapTypeExpr) ast.Expr {
+	ret := new(ast.MapLiteral)
+	ret.Type = t
+	ret.Lbrace = p.Shift()
+	if !p.SeeOp("}") {
+		ret.Exprs = parseElemListClosed(p, "}")
+		if p.InError() {
+			return nil
+		}
+	}
+	ret.Rbrace = p.ExpectOp("}")
+	if p.InError() {
+		return nil
+	}
+	return ret
+}
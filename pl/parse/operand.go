@@ -12,7 +12,9 @@ func parseOperand(p *parser) ast.Expr {
 		return ast.NewOperand(p.Shift())
 	} else if p.SeeOp("(") {
 		lp := p.Shift()
+		p.exprLev++
 		expr := p.parseExpr()
+		p.exprLev--
 		rp := p.ExpectOp(")")
 		if rp == nil {
 			return nil
@@ -36,7 +38,7 @@ func parseOperand(p *parser) ast.Expr {
 		ret.Type = t.(*ast.ArrayTypeExpr)
 		ret.Lbrace = p.Shift()
 		if !p.SeeOp("}") {
-			ret.Exprs = parseExprListClosed(p, "}")
+			ret.Exprs = parseElemListClosed(p, "}")
 			if p.InError() {
 				return nil
 			}
@@ -46,7 +48,27 @@ func parseOperand(p *parser) ast.Expr {
 			return nil
 		}
 		return ret
-	} else if p.SeeOp("*") || p.SeeKeyword("func") {
+	} else if p.SeeKeyword("func") {
+		t := p.parseType()
+		if t == nil {
+			return nil
+		}
+		if !p.SeeOp("{") {
+			return t
+		}
+		return parseFuncLit(p, t.(*ast.FuncTypeExpr))
+	} else if p.SeeKeyword("map") {
+		t := p.parseType()
+		if t == nil {
+			return nil
+		}
+		if !p.SeeOp("{") {
+			return t
+		}
+		return parseMapLit(p, t.(*ast.MapTypeExpr))
+	} else if p.SeeKeyword("chan") {
+		return p.parseType()
+	} else if p.SeeOp("*") {
 		return p.parseType()
 	}
 
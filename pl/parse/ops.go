@@ -5,7 +5,7 @@ import (
 )
 
 func parseUnaryExpr(p *parser) ast.Expr {
-	if p.SeeOp("+", "-", "!", "^", "&") {
+	if p.SeeOp("+", "-", "!", "^", "&", "<-") {
 		t := p.Shift()
 		expr := parseUnaryExpr(p)
 		return &ast.OpExpr{A: nil, Op: t, B: expr}
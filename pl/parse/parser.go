@@ -18,6 +18,15 @@ type parser struct {
 	stmtFunc func(p *parser) ast.Stmt
 
 	golike bool
+
+	// exprLev is the nesting level of the expression being parsed. It
+	// is negative in control clauses, where a '{' after a type name
+	// starts a block rather than a struct literal.
+	exprLev int
+
+	// forRange is true when parsing the header of a for loop, where a
+	// range clause is allowed.
+	forRange bool
 }
 
 func makeTokener(f string, r io.Reader, golike bool) lexing.Tokener {
@@ -55,6 +64,15 @@ func (p *parser) parseType() ast.Expr {
 	return p.typeFunc(p)
 }
 
+// enterCtrl enters a control clause and returns the previous level.
+func (p *parser) enterCtrl() int {
+	lev := p.exprLev
+	p.exprLev = -1
+	return lev
+}
+
+func (p *parser) exitCtrl(lev int) { p.exprLev = lev }
+
 func (p *parser) parseExpr() ast.Expr {
 	if p.exprFunc == nil {
 		return nil
@@ -16,7 +16,9 @@ func parsePrimaryExpr(p *parser) ast.Expr {
 		} else if p.SeeOp("[") {
 			ret = parseIndexExpr(p, ret)
 		} else if p.SeeOp(".") {
-			ret = parseMemberExpr(p, ret)
+			ret = parseDotExpr(p, ret)
+		} else if p.SeeOp("{") && p.exprLev >= 0 && isTypeName(ret) {
+			ret = parseStructLit(p, ret)
 		} else {
 			break
 		}
@@ -0,0 +1,82 @@
+package parse
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+)
+
+func parseSelectStmt(p *parser) *ast.SelectStmt {
+	ret := new(ast.SelectStmt)
+	ret.Kw = p.ExpectKeyword("select")
+	ret.Lbrace = p.ExpectOp("{")
+	if ret.Lbrace == nil {
+		return ret
+	}
+	for !(p.SeeOp("}") || p.See(lexing.EOF)) {
+		if c := parseSelectCase(p); c != nil {
+			ret.Cases = append(ret.Cases, c)
+		}
+		p.skipErrStmt()
+	}
+	ret.Rbrace = p.ExpectOp("}")
+	ret.Semi = p.ExpectSemi()
+	return ret
+}
+
+func isRecvExpr(e ast.Expr) bool {
+	op, ok := e.(*ast.OpExpr)
+	return ok && op.A == nil && op.Op.Lit == "<-"
+}
+
+func isRecvList(lst *ast.ExprList) bool {
+	return lst != nil && lst.Len() == 1 && isRecvExpr(lst.Exprs[0])
+}
+
+func isSelectComm(s ast.Stmt) bool {
+	switch s := s.(type) {
+	case *ast.SendStmt:
+		return true
+	case *ast.ExprStmt:
+		return isRecvExpr(s.Expr)
+	case *ast.AssignStmt:
+		return s.Assign.Lit == "=" && isRecvList(s.Right)
+	case *ast.DefineStmt:
+		return isRecvList(s.Right)
+	}
+	return false
+}
+
+func parseSelectCase(p *parser) *ast.SelectCase {
+	ret := new(ast.SelectCase)
+	if p.SeeKeyword("case") {
+		ret.Kw = p.Shift()
+		pos := p.Token().Pos
+		ret.Comm = parseSimpleStmtNoSemi(p)
+		if p.InError() {
+			return nil
+		}
+		if !isSelectComm(ret.Comm) {
+			p.CodeErrorf(pos, "pl.select.badCase",
+				"select case must be a send or a receive")
+			return nil
+		}
+	} else if p.SeeKeyword("default") {
+		ret.Kw = p.Shift()
+	} else {
+		p.CodeErrorfHere("pl.select.missingCase",
+			"must start with keyword case/default in select")
+		return nil
+	}
+	ret.Colon = p.ExpectOp(":")
+	if ret.Colon == nil {
+		return nil
+	}
+	for !(p.SeeKeyword("case") || p.SeeKeyword("default") ||
+		p.SeeOp("}") || p.See(lexing.EOF)) {
+		if stmt := p.parseStmt(); stmt != nil {
+			ret.Stmts = append(ret.Stmts, stmt)
+		}
+		p.skipErrStmt()
+	}
+	return ret
+}
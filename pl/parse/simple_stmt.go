@@ -30,6 +30,9 @@ func parseSimpleStmtOrExpr(p *parser, needSemi bool) (ast.Stmt, ast.Expr) {
 		ret := new(ast.AssignStmt)
 		ret.Left = exprs
 		ret.Assign = p.Shift()
+		if ret.Assign.Lit == "=" && p.forRange && p.SeeKeyword("range") {
+			return parseRangeClause(p, exprs, ret.Assign), nil
+		}
 		ret.Right = parseExprList(p)
 		if needSemi {
 			ret.Semi = p.ExpectSemi()
@@ -40,6 +43,9 @@ func parseSimpleStmtOrExpr(p *parser, needSemi bool) (ast.Stmt, ast.Expr) {
 		ret := new(ast.DefineStmt)
 		ret.Left = exprs
 		ret.Define = p.Shift()
+		if p.forRange && p.SeeKeyword("range") {
+			return parseRangeClause(p, exprs, ret.Define), nil
+		}
 		ret.Right = parseExprList(p)
 		if needSemi {
 			ret.Semi = p.ExpectSemi()
@@ -61,6 +67,17 @@ func parseSimpleStmtOrExpr(p *parser, needSemi bool) (ast.Stmt, ast.Expr) {
 		return ret, nil
 	}
 
+	if exprs.Len() == 1 && p.SeeOp("<-") {
+		ret := new(ast.SendStmt)
+		ret.Chan = exprs.Exprs[0]
+		ret.Arrow = p.Shift()
+		ret.Val = p.parseExpr()
+		if needSemi {
+			ret.Semi = p.ExpectSemi()
+		}
+		return ret, nil
+	}
+
 	if exprs.Len() != 1 {
 		p.ErrorfHere("expect expression, but got a list")
 		p.BailOut()
@@ -68,6 +85,11 @@ func parseSimpleStmtOrExpr(p *parser, needSemi bool) (ast.Stmt, ast.Expr) {
 	}
 
 	expr := exprs.Exprs[0]
+	if needSemi && !p.forRange && p.SeeOp(":") {
+		if op, ok := expr.(*ast.Operand); ok && op.Token.Type == Ident {
+			return parseLabeledStmt(p, op.Token), nil
+		}
+	}
 
 	if !needSemi {
 		ret := new(ast.ExprStmt)
@@ -23,10 +23,18 @@ func parseStmt(p *parser) ast.Stmt {
 			return parseSwitchStmt(p)
 		case "return":
 			return parseReturnStmt(p, true)
+		case "defer":
+			return parseDeferStmt(p)
+		case "go":
+			return parseGoStmt(p)
+		case "select":
+			return parseSelectStmt(p)
 		case "break":
 			return parseBreakStmt(p, true)
 		case "continue":
 			return parseContinueStmt(p, true)
+		case "goto":
+			return parseGotoStmt(p)
 		case "else":
 			// a common error case where else leads a statement.
 			p.CodeErrorfHere(
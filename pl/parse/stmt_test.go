@@ -58,6 +58,22 @@ func TestStmts_good(t *testing.T) {
 		"[]int{3, 4, 5, 6}",
 		"[]int{3, 4, 5, 6,}",
 		"[]uint{}",
+		"[3]int{1: 2, 3}",
+		"A{}",
+		"a.A{x: 3, y: 4}",
+		"&A{3, 4}",
+		"func() {}",
+		"func(a int) int { return a }(3)",
+		"[][]int{[]int{}, []int{3: 4}}",
+		"[...]int{1, 2}",
+		"[2][2]int{{1, 2}, {}}",
+		"map[P]P{{1, 2}: {x: 3}}",
+		"f(a...)",
+		"f(a, b...)",
+		"f(a, b...,)",
+		"a.(*A)",
+		"a.(b.A).x",
+		"a.([]int)[3]",
 	} {
 		buf := strings.NewReader(s)
 		stmts, es := Stmts("test.g", buf)
@@ -119,6 +135,9 @@ func TestStmts_good(t *testing.T) {
 		"if true return 3",
 		"if true { return }",
 		"if true { return; break }",
+		"defer f()",
+		"defer a.b(3, 4)",
+		"defer func() { print(3) }()",
 		`for true {
 			print(3)
 			read()
@@ -140,6 +159,13 @@ func TestStmts_good(t *testing.T) {
 		"a--",
 		"ret := (a.b & 0x1) > 0",
 		"a := []int{3,4}",
+		"a := A{x: 3}",
+		"if x == a { }",
+		"if x == (A{}) { }",
+		"for a := (A{}); a.x < 3; a.x++ { a = A{} }",
+		"switch x { case A{}.x: }",
+		"f := func() { a := A{} }",
+		"for func() bool { return true }() { }",
 		"switch 0 { }",
 		"switch 0 { case 3: }",
 		`switch 0 {
@@ -147,6 +173,40 @@ func TestStmts_good(t *testing.T) {
 			case 4:
 		}`,
 		"switch 0 { default: }",
+		"m := map[string]int{}",
+		`m := map[int]string{1: "a", 2: "b",}`,
+		"var m map[*A][]int",
+		"for k, v := range m { }",
+		"for k = range m { }",
+		"for range m { }",
+		"for _, v := range []int{1, 2} { }",
+		"goto a",
+		"f := func(a ...int) {}",
+		"a: for { break a }",
+		"a: for { continue a }",
+		"a:\nswitch 0 { case 3: break a }",
+		"a: a++",
+		"{ a: }",
+		"t, ok := a.(*A)",
+		"switch a.(type) { }",
+		"switch x := a.(type) { case *A, nil: default: }",
+		"a: switch x := a.(type) { case *A: break a }",
+		"go f(3)",
+		"go func() { a++ }()",
+		"ch <- 3",
+		"ch := make(chan int, 3)",
+		"var ch chan []chan int",
+		"v := <-ch",
+		"v, ok := <-ch",
+		"_ := <-<-chs",
+		`select {
+			case v := <-a:
+			case v, ok = <-b: x++
+			case c <- 3: break
+			case <-d:
+			default:
+		}`,
+		"select {}",
 	} {
 		buf := strings.NewReader(s)
 		stmts, es := Stmts("test.g", buf)
@@ -210,6 +270,7 @@ func TestStmts_bad(t *testing.T) {
 	o("missingSemi", "var a b c = 3, 4")
 	o("missingSemi", "var a b = c d")
 	o("missingSemi", "a]")
+	o("missingSemi", "defer f() g()")
 
 	o("missingSemi", "var (a int, b int);")
 
@@ -233,6 +294,7 @@ func TestStmts_bad(t *testing.T) {
 	o("expectOperand", "a[")
 	o("expectOperand", "++i")
 	o("expectOperand", "a := []int{,}")
+	o("expectOperand", "a := []int{3:}")
 
 	o("expectOp", "{")
 	o("expectOp", "if true { ")
@@ -264,6 +326,10 @@ func TestStmts_bad(t *testing.T) {
 	o("invalidFallthrough", `switch 2 { case 2:
 		if true {fallthrough}}`)
 	o("invalidFallthrough", "fallthrough")
+	o("typeSwitch.expectIdent", "switch a.b := c.(type) {}")
+	o("typeSwitch.expectTypeSwitch", "switch x := a {}")
+	o("expectType", "a.(3)")
+	o("expectType", "switch a.(type) { case 3: }")
 
 	o("lexing.unexpected", "var = 3")
 	o("lexing.unexpected", "var \n ()")
@@ -279,6 +345,11 @@ func TestStmts_bad(t *testing.T) {
 
 	o("illegalChar", "@")
 	o("invalidDotDot", "..")
+	o("call.dotsNotLast", "f(a..., b)")
 	o("incOnExprList", "a,b++")
+	o("select.badCase", "select { case a: }")
+	o("select.badCase", "select { case a = 3: }")
+	o("select.badCase", "select { case a, b := <-c, <-d: }")
+	o("select.missingCase", "select { a++ }")
 
 }
@@ -6,52 +6,23 @@ import (
 )
 
 func parseStruct(p *parser) *ast.Struct {
-	var ret *ast.Struct
-	if !p.golike {
-		if p.SeeKeyword("struct") {
-			ret = &ast.Struct{
-				Kw:     p.ExpectKeyword("struct"),
-				Name:   p.Expect(Ident),
-				Lbrace: p.ExpectOp("{"),
-			}
-		} else if p.SeeLit(Ident, "type") {
-			ret = &ast.Struct{
-				Kw:      p.Expect(Ident),
-				Name:    p.Expect(Ident),
-				KwAfter: p.ExpectKeyword("struct"),
-				Lbrace:  p.ExpectOp("{"),
-			}
-			p.CodeErrorf(
-				ret.Kw.Pos, "pl.invalidStructDecl",
-				`G langauge uses "struct %s {}" `+
-					`rather than "type %s struct {}"`,
-				ret.Name.Lit, ret.Name.Lit,
-			)
-		} else {
-			panic("expect type or struct")
-		}
-	} else {
-		if !p.SeeKeyword("type") {
-			panic("expect keyword type")
-		}
-		ret = &ast.Struct{
-			Kw:      p.ExpectKeyword("type"),
-			Name:    p.Expect(Ident),
-			KwAfter: p.ExpectKeyword("struct"),
-			Lbrace:  p.ExpectOp("{"),
-		}
+	if !p.SeeKeyword("struct") {
+		panic("expect keyword struct")
 	}
+	ret := &ast.Struct{
+		Kw:   p.Shift(),
+		Name: p.Expect(Ident),
+	}
+	if p.SeeOp("[") {
+		ret.TypeParams = parseTypeParams(p, true)
+	}
+	ret.Lbrace = p.ExpectOp("{")
+	return parseStructBody(p, ret)
+}
 
+func parseStructBody(p *parser, ret *ast.Struct) *ast.Struct {
 	for !p.SeeOp("}") && !p.See(lexing.EOF) {
-		idents := parseIdentList(p)
-		if p.skipErrStmt() {
-			continue
-		}
-
-		field := new(ast.Field)
-		field.Idents = idents
-		field.Type = p.parseType()
-
+		field := parseField(p)
 		if p.skipErrStmt() {
 			continue
 		}
@@ -69,3 +40,31 @@ func parseStruct(p *parser) *ast.Struct {
 
 	return ret
 }
+
+// parseField parses a field declaration, which is either a list of
+// names followed by a type, or an embedded type like "T", "*T" or
+// "pkg.T".
+func parseField(p *parser) *ast.Field {
+	field := new(ast.Field)
+	if p.SeeOp("*") {
+		field.Type = p.parseType()
+		return field
+	}
+
+	idents := parseIdentList(p)
+	if p.InError() {
+		return field
+	}
+	if len(idents.Idents) == 1 && (p.SeeSemi() || p.SeeOp(".")) {
+		var t ast.Expr = &ast.Operand{Token: idents.Idents[0]}
+		if p.SeeOp(".") {
+			t = parseMemberExpr(p, t)
+		}
+		field.Type = t
+		return field
+	}
+
+	field.Idents = idents
+	field.Type = p.parseType()
+	return field
+}
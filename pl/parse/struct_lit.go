@@ -0,0 +1,39 @@
+package parse
+
+import (
+	"shanhu.io/smlvm/pl/ast"
+)
+
+// isTypeName checks if an expression can be the type of a struct
+// literal, which is either an identifier or a member of a package.
+func isTypeName(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.Operand:
+		return expr.Token.Type == Ident
+	case *ast.MemberExpr:
+		op, ok := expr.Expr.(*ast.Operand)
+		return ok && op.Token.Type == Ident
+	case *ast.IndexExpr:
+		return expr.Colon == nil && isTypeName(expr.Array)
+	case *ast.InstExpr:
+		return isTypeName(expr.Generic)
+	}
+	return false
+}
+
+func parseStructLit(p *parser, t ast.Expr) ast.Expr {
+	ret := new(ast.StructLiteral)
+	ret.Type = t
+	ret.Lbrace = p.Shift()
+	if !p.SeeOp("}") {
+		ret.Fields = parseElemListClosed(p, "}")
+		if p.InError() {
+			return nil
+		}
+	}
+	ret.Rbrace = p.ExpectOp("}")
+	if p.InError() {
+		return nil
+	}
+	return ret
+}
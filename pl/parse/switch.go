@@ -1,27 +1,66 @@
 package parse
 
 import (
+	"shanhu.io/smlvm/lexing"
 	"shanhu.io/smlvm/pl/ast"
 )
 
-func parseSwitchStmt(p *parser) *ast.SwitchStmt {
+func parseSwitchStmt(p *parser) ast.Stmt {
 	if !p.SeeKeyword("switch") {
 		panic("must start with keyword switch")
 	}
-	ret := new(ast.SwitchStmt)
-	ret.Kw = p.Shift()
-	ret.Expr = p.parseExpr()
+	kw := p.Shift()
+	lev := p.enterCtrl()
+	expr := p.parseExpr()
+	var v, define *lexing.Token
+	if expr != nil && p.SeeOp(":=") {
+		if op, ok := expr.(*ast.Operand); ok && op.Token.Type == Ident {
+			v = op.Token
+		} else {
+			p.CodeErrorf(ast.ExprPos(expr), "pl.typeSwitch.expectIdent",
+				"expect an identifier before :=")
+		}
+		define = p.Shift()
+		expr = p.parseExpr()
+	}
+	p.exitCtrl(lev)
 	if p.InError() {
+		return &ast.SwitchStmt{Kw: kw, Expr: expr}
+	}
+
+	if t, ok := expr.(*ast.TypeAssertExpr); ok && t.Type == nil {
+		ret := &ast.TypeSwitchStmt{Kw: kw, Var: v, Define: define, Expr: t}
+		if !seeSwitchBody(p) {
+			return ret
+		}
+		ret.Lbrace = p.Shift()
+		ret.Cases = parseCases(p, true)
+		ret.Rbrace = p.ExpectOp("}")
+		ret.Semi = p.ExpectSemi()
 		return ret
 	}
-	if !p.SeeOp("{") {
-		p.CodeErrorfHere("missingSwitchBody",
-			"missing switch body, need '{'")
+	if define != nil {
+		p.CodeErrorf(define.Pos, "pl.typeSwitch.expectTypeSwitch",
+			"expect a type switch after :=")
+		return &ast.SwitchStmt{Kw: kw, Expr: expr}
+	}
+
+	ret := &ast.SwitchStmt{Kw: kw, Expr: expr}
+	if !seeSwitchBody(p) {
 		return ret
 	}
 	ret.Lbrace = p.Shift()
-	ret.Cases = parseCases(p)
+	ret.Cases = parseCases(p, false)
 	ret.Rbrace = p.ExpectOp("}")
 	ret.Semi = p.ExpectSemi()
 	return ret
 }
+
+func seeSwitchBody(p *parser) bool {
+	if !p.SeeOp("{") {
+		p.CodeErrorfHere("missingSwitchBody",
+			"missing switch body, need '{'")
+		return false
+	}
+	return true
+}
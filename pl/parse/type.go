@@ -6,9 +6,12 @@ import (
 
 func parseType(p *parser) ast.Expr {
 	if p.See(Ident) {
-		ret := ast.NewOperand(p.Shift())
+		var ret ast.Expr = ast.NewOperand(p.Shift())
 		if p.SeeOp(".") {
-			return parseMemberExpr(p, ret)
+			ret = parseMemberExpr(p, ret)
+		}
+		if ret != nil && p.SeeOp("[") {
+			return parseTypeArgs(p, ret)
 		}
 		return ret
 	} else if p.SeeOp("*") {
@@ -19,7 +22,9 @@ func parseType(p *parser) ast.Expr {
 	} else if p.SeeOp("[") {
 		ret := new(ast.ArrayTypeExpr)
 		ret.Lbrack = p.Shift()
-		if !p.SeeOp("]") {
+		if p.SeeOp("...") {
+			ret.Dots = p.Shift()
+		} else if !p.SeeOp("]") {
 			ret.Len = p.parseExpr()
 			if ret.Len == nil {
 				return nil
@@ -39,6 +44,25 @@ func parseType(p *parser) ast.Expr {
 		ret.Kw = p.Shift()
 		ret.FuncSig = parseFuncSig(p)
 		return ret
+	} else if p.SeeKeyword("map") {
+		ret := new(ast.MapTypeExpr)
+		ret.Kw = p.Shift()
+		ret.Lbrack = p.ExpectOp("[")
+		ret.Key = p.parseType()
+		ret.Rbrack = p.ExpectOp("]")
+		ret.Val = p.parseType()
+		if p.InError() {
+			return nil
+		}
+		return ret
+	} else if p.SeeKeyword("chan") {
+		ret := new(ast.ChanTypeExpr)
+		ret.Kw = p.Shift()
+		ret.Elem = p.parseType()
+		if ret.Elem == nil {
+			return nil
+		}
+		return ret
 	}
 
 	tok := p.Token()
@@ -0,0 +1,50 @@
+package parse
+
+import (
+	"shanhu.io/smlvm/pl/ast"
+)
+
+// parseDotExpr parses a member expression like "a.b" or a type
+// assertion like "a.(T)".
+func parseDotExpr(p *parser, lead ast.Expr) ast.Expr {
+	if !p.SeeOp(".") {
+		panic("parseDotExpr() must start with '.'")
+	}
+	dot := p.Shift()
+	if !p.SeeOp("(") {
+		return &ast.MemberExpr{
+			Expr: lead,
+			Dot:  dot,
+			Sub:  p.Expect(Ident),
+		}
+	}
+
+	ret := &ast.TypeAssertExpr{Expr: lead, Dot: dot, Lparen: p.Shift()}
+	if p.SeeKeyword("type") || p.SeeLit(Ident, "type") {
+		ret.TypeKw = p.Shift()
+	} else {
+		ret.Type = p.parseType()
+		if ret.Type == nil {
+			return nil
+		}
+	}
+	ret.Rparen = p.ExpectOp(")")
+	return ret
+}
+
+// parseTypeList parses the list of types in a case of a type switch.
+func parseTypeList(p *parser) *ast.ExprList {
+	ret := new(ast.ExprList)
+	for {
+		t := p.parseType()
+		if t == nil {
+			return nil
+		}
+		ret.Exprs = append(ret.Exprs, t)
+		if !p.SeeOp(",") {
+			break
+		}
+		ret.Commas = append(ret.Commas, p.Shift())
+	}
+	return ret
+}
@@ -0,0 +1,49 @@
+package parse
+
+import (
+	"shanhu.io/smlvm/pl/ast"
+)
+
+// parseTypeDecl parses a type declaration. It declares a named type
+// like "type Celsius int" in both syntaxes, and in the go-like syntax,
+// a struct or an interface like "type A struct {}" or
+// "type I interface {}".
+func parseTypeDecl(p *parser) ast.Decl {
+	if !p.SeeKeyword("type") && !p.SeeLit(Ident, "type") {
+		panic("expect type")
+	}
+	kw := p.Shift()
+	name := p.Expect(Ident)
+	if p.golike && p.SeeKeyword("interface") {
+		return parseInterfaceBody(p, &ast.Interface{
+			Kw:      kw,
+			Name:    name,
+			KwAfter: p.Shift(),
+			Lbrace:  p.ExpectOp("{"),
+		})
+	}
+	if p.SeeKeyword("struct") {
+		ret := &ast.Struct{
+			Kw:      kw,
+			Name:    name,
+			KwAfter: p.Shift(),
+			Lbrace:  p.ExpectOp("{"),
+		}
+		if !p.golike {
+			p.CodeErrorf(
+				kw.Pos, "pl.invalidStructDecl",
+				`G langauge uses "struct %s {}" `+
+					`rather than "type %s struct {}"`,
+				name.Lit, name.Lit,
+			)
+		}
+		return parseStructBody(p, ret)
+	}
+
+	return &ast.TypeDecl{
+		Kw:   kw,
+		Name: name,
+		Type: p.parseType(),
+		Semi: p.ExpectSemi(),
+	}
+}
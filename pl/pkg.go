@@ -129,7 +129,7 @@ func fillMethods(b *builder, methods []*tast.Func) {
 	for _, f := range methods {
 		name := f.Sym.Name()
 		t := f.Sym.ObjType.(*types.Func)
-		s := t.Args[0].T.(*types.Pointer).T.(*types.Struct)
+		s := t.Args[0].T.(*types.Pointer).T
 
 		fullName := fmt.Sprintf("%s:%s", s, name)
 		sig := makeFuncSig(t)
@@ -142,6 +142,13 @@ func fillMethods(b *builder, methods []*tast.Func) {
 	}
 }
 
+func fillStructDescs(b *builder, list []*syms.Symbol) {
+	for _, sym := range list {
+		t := sym.ObjType.(*types.Type).T
+		b.structDescs[t] = b.p.NewStructDesc(t.String())
+	}
+}
+
 func buildFuncs(b *builder, funcs []*tast.Func) {
 	for _, f := range funcs {
 		obj := f.Sym.Obj.(*objFunc)
@@ -164,6 +171,7 @@ func buildPkg(
 		Path:    b.path,
 		Files:   files,
 		Imports: imports,
+		Golike:  b.golike,
 	}
 
 	tops = syms.NewTable()
@@ -179,6 +187,8 @@ func buildPkg(
 	fillFuncAlias(res.FuncAliases)
 	fillFuncs(b, res.Funcs)
 	fillMethods(b, res.Methods)
+	fillStructDescs(b, res.Structs)
+	fillStructDescs(b, res.Named)
 	buildFuncs(b, res.Funcs)
 	buildFuncs(b, res.Methods)
 	addInit(b)
@@ -82,3 +82,51 @@ func binaryOpSlice(b *builder, op string, A, B *ref) *ref {
 	}
 	panic("bug")
 }
+
+func testNilInterface(b *builder, r *ref, neg bool) *ref {
+	addr := b.newPtr()
+	isNil := b.newCond()
+	b.b.Arith(addr, nil, "&", r.IR())
+	b.b.Arith(isNil, nil, "?", codegen.NewAddrRef(addr, 4, 4, false, true))
+	if neg {
+		b.b.Arith(isNil, nil, "!", isNil)
+	}
+	return newRef(types.Bool, isNil)
+}
+
+func binaryOpInterface(b *builder, op string, A, B *ref) *ref {
+	atyp := A.Type()
+	btyp := B.Type()
+
+	switch op {
+	case "==", "!=":
+		if types.IsNil(atyp) {
+			return testNilInterface(b, B, op == "==")
+		} else if types.IsNil(btyp) {
+			return testNilInterface(b, A, op == "==")
+		}
+
+		addrA := b.newPtr()
+		addrB := b.newPtr()
+		b.b.Arith(addrA, nil, "&", A.IR())
+		b.b.Arith(addrB, nil, "&", B.IR())
+		recvA := codegen.NewAddrRef(addrA, 4, 0, false, true)
+		tabA := codegen.NewAddrRef(addrA, 4, 4, false, true)
+		recvB := codegen.NewAddrRef(addrB, 4, 0, false, true)
+		tabB := codegen.NewAddrRef(addrB, 4, 4, false, true)
+
+		recvEq := b.newCond()
+		tabEq := b.newCond()
+
+		b.b.Arith(recvEq, recvA, "==", recvB)
+		b.b.Arith(tabEq, tabA, "==", tabB)
+
+		ret := b.newCond()
+		b.b.Arith(ret, recvEq, "&", tabEq)
+		if op == "!=" {
+			b.b.Arith(ret, nil, "!", ret)
+		}
+		return newRef(types.Bool, ret)
+	}
+	panic("bug")
+}
@@ -0,0 +1,85 @@
+package pl
+
+import (
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// buildRangeBody builds the body of a range loop, where continue jumps
+// to next and break jumps to after. The building continues at after.
+func buildRangeBody(
+	b *builder, stmt *tast.RangeStmt, next, after *codegen.Block,
+) {
+	label := b.labels.take()
+	b.breaks.push(after, label)
+	b.continues.push(next, label)
+	buildPreempt(b)
+	b.buildStmt(stmt.Body)
+	b.breaks.pop()
+	b.continues.pop()
+
+	b.b = after
+}
+
+// buildRangeArray builds a range loop over an array, a slice or a
+// string.
+func buildRangeArray(b *builder, stmt *tast.RangeStmt, x *ref) {
+	t := x.Type()
+	if _, ok := t.(*types.Slice); ok || stmt.Value != nil {
+		// only evaluates once; an array is copied only when the values
+		// are used.
+		tmp := b.newTemp(t)
+		b.b.Assign(tmp.IR(), x.IR())
+		x = tmp
+	}
+	addr, n, et := loadArray(b, x)
+	elemSize := arrayElementSize(et)
+
+	i := b.newTempIR(types.Int)
+	b.b.Zero(i)
+
+	condBlock := b.f.NewBlock(b.b)
+	body := b.f.NewBlock(condBlock)
+	next := b.f.NewBlock(body)
+	after := b.f.NewBlock(next)
+	next.Arith(i, i, "+", codegen.Snum(1))
+	next.Jump(condBlock)
+
+	b.b = condBlock
+	inRange := b.newCond()
+	b.b.Arith(inRange, i, "<", n)
+	b.b.JumpIfNot(inRange, after)
+
+	b.b = body
+	if stmt.Key != nil {
+		k := b.buildExpr(stmt.Key)
+		b.b.Assign(k.IR(), i)
+	}
+	if stmt.Value != nil {
+		p := b.newPtr()
+		b.b.Arith(p, i, "*", codegen.Snum(elemSize))
+		b.b.Arith(p, addr, "+", p)
+		v := b.buildExpr(stmt.Value)
+		b.b.Assign(v.IR(), elemAt(p, et, 0))
+	}
+
+	buildRangeBody(b, stmt, next, after)
+}
+
+func buildRangeStmt(b *builder, stmt *tast.RangeStmt) {
+	if stmt.Define != nil {
+		buildDefine(b, stmt.Define)
+	}
+
+	x := b.buildExpr(stmt.X)
+	if _, ok := x.Type().(*types.Map); ok {
+		buildRangeMap(b, stmt, x)
+		return
+	}
+	if _, ok := types.Underlying(x.Type()).(*types.Chan); ok {
+		buildRangeChan(b, stmt, x)
+		return
+	}
+	buildRangeArray(b, stmt, x)
+}
@@ -18,12 +18,25 @@ type ref struct {
 	ir codegen.Ref
 }
 
-func newRef(t types.T, r codegen.Ref) *ref { return &ref{typ: t, ir: r} }
+// normType returns the underlying type of a named type, or of a type
+// that names a named type. Named types only matter in semantic checks.
+func normType(t types.T) types.T {
+	if t, ok := t.(*types.Type); ok && types.IsNamed(t.T) {
+		return &types.Type{T: types.Underlying(t.T)}
+	}
+	return types.Underlying(t)
+}
 
-func newTypeRef(t types.T) *ref { return &ref{typ: &types.Type{T: t}} }
+func newRef(t types.T, r codegen.Ref) *ref {
+	return &ref{typ: normType(t), ir: r}
+}
+
+func newTypeRef(t types.T) *ref {
+	return &ref{typ: &types.Type{T: types.Underlying(t)}}
+}
 
 func newAddressableRef(t types.T, r codegen.Ref) *ref {
-	return &ref{typ: t, ir: r, addressable: true}
+	return &ref{typ: normType(t), ir: r, addressable: true}
 }
 
 func newRecvRef(t *types.Func, recv *ref, r codegen.Ref) *ref {
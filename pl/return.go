@@ -11,6 +11,6 @@ func buildReturnStmt(b *builder, stmt *tast.ReturnStmt) {
 	}
 
 	next := b.f.NewBlock(b.b)
-	b.b.Jump(b.f.End())
+	b.b.Jump(b.exit)
 	b.b = next
 }
@@ -0,0 +1,164 @@
+package pl
+
+import (
+	"shanhu.io/smlvm/builds"
+	"shanhu.io/smlvm/link"
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+	"shanhu.io/smlvm/syms"
+)
+
+// RuntimePkg is the package name of the runtime package. The runtime is
+// written in G, and every G package implicitly imports it.
+const RuntimePkg = "/std/runtime"
+
+// runtimeFiles are the source files of the runtime package.
+var runtimeFiles = map[string]string{
+	"alloc.g":  runtimeAllocSrc,
+	"chan.g":   runtimeChanSrc,
+	"defer.g":  runtimeDeferSrc,
+	"gc.g":     runtimeGCSrc,
+	"heap.g":   runtimeHeapSrc,
+	"map.g":    runtimeMapSrc,
+	"page.g":   runtimePageSrc,
+	"sched.g":  runtimeSchedSrc,
+	"select.g": runtimeSelectSrc,
+	"slice.g":  runtimeSliceSrc,
+	"sweep.g":  runtimeSweepSrc,
+	"thread.g": runtimeThreadSrc,
+}
+
+// RuntimeLang returns the G language for building the runtime package,
+// which does not import the runtime itself.
+func RuntimeLang() *builds.Lang {
+	return &builds.Lang{
+		Ext:      "g",
+		Compiler: &lang{runtime: true},
+	}
+}
+
+// runtimeFuncs are the runtime functions that the compiler calls.
+type runtimeFuncs struct {
+	alloc       codegen.Ref
+	sliceGrow   codegen.Ref
+	sliceAppend codegen.Ref
+	bytesEqual  codegen.Ref
+
+	mapMake   codegen.Ref
+	mapLen    codegen.Ref
+	mapAccess codegen.Ref
+	mapAssign codegen.Ref
+	mapDelete codegen.Ref
+	mapNext   codegen.Ref
+
+	deferPush codegen.Ref
+	deferMark codegen.Ref
+	deferRun  codegen.Ref
+	panic     codegen.Ref
+
+	chanMake  codegen.Ref
+	chanSend  codegen.Ref
+	chanRecv  codegen.Ref
+	chanClose codegen.Ref
+	chanLen   codegen.Ref
+	selectRun codegen.Ref
+
+	goRecord codegen.Ref
+	goStart  codegen.Ref
+	yield    codegen.Ref
+	preempt  codegen.Ref
+}
+
+func declareRuntime(b *builder, rt *link.Pkg) {
+	path := RuntimePkg
+	u := types.Uint
+	fn := func(ret types.T, args ...types.T) *types.Func {
+		return types.NewFuncUnamed(args, []types.T{ret})
+	}
+	f := func(name string, t *types.Func) codegen.Ref {
+		if rt != nil { // nil when only doing static analysis
+			sym := rt.SymbolByName(name)
+			if sym == nil || sym.Type != link.SymFunc {
+				b.Errorf(nil, "runtime function %s missing", name)
+				return nil
+			}
+		}
+		return codegen.NewFuncSym(path, name, makeFuncSig(t))
+	}
+
+	b.rt = &runtimeFuncs{
+		alloc:       f("Alloc", fn(u, u, u)),
+		sliceGrow:   f("SliceGrow", fn(u, u, u, u, u, u)),
+		sliceAppend: f("SliceAppend", fn(u, u, u, u, u, u, u)),
+		bytesEqual:  f("BytesEqual", fn(types.Bool, u, u, u, u)),
+
+		mapMake:   f("MapMake", fn(u, u, u, u, u)),
+		mapLen:    f("MapLen", fn(types.Int, u)),
+		mapAccess: f("MapAccess", fn(u, u, u)),
+		mapAssign: f("MapAssign", fn(u, u, u)),
+		mapDelete: f("MapDelete", types.NewVoidFunc(u, u)),
+		mapNext:   f("MapNext", fn(u, u, u)),
+
+		deferPush: f("DeferPush", fn(u, u, u)),
+		deferMark: f("DeferMark", fn(u)),
+		deferRun:  f("DeferRun", types.NewVoidFunc(u)),
+		panic:     f("Panic", types.VoidFunc),
+
+		chanMake:  f("ChanMake", fn(u, u, u, u)),
+		chanSend:  f("ChanSend", types.NewVoidFunc(u, u)),
+		chanRecv:  f("ChanRecv", fn(types.Bool, u, u)),
+		chanClose: f("ChanClose", types.NewVoidFunc(u)),
+		chanLen:   f("ChanLen", fn(types.Int, u)),
+		selectRun: f("Select", fn(types.Int, u, u, types.Bool)),
+
+		goRecord: f("GoRecord", fn(u, u, u)),
+		goStart:  f("Go", types.NewVoidFunc(u)),
+		yield:    f("Yield", types.VoidFunc),
+		preempt:  codegen.NewHeapSym(path, "Preempt", 4, false, true),
+	}
+
+	// panics through the runtime, so that the deferred calls are run
+	b.panicFunc = b.rt.panic
+	if s := b.scope.Query("panic"); s != nil {
+		s.Obj.(*objFunc).ref = newRef(types.VoidFunc, b.panicFunc)
+	}
+}
+
+// declareRuntimeBuiltin declares the symbols that only the runtime package
+// can use: callerFrame() for walking the stack, the functions for
+// switching threads, and the tables that the linker generates for the
+// garbage collector.
+func declareRuntimeBuiltin(b *builder, builtin *link.Pkg) {
+	b.runtime = true
+	declare := func(name string, kind int, obj interface{}, t types.T) {
+		s := syms.Make(b.path, name, kind, obj, t, nil)
+		if pre := b.scope.Declare(s); pre != nil {
+			b.Errorf(nil, "runtime symbol %s declare failed", name)
+		}
+	}
+
+	fn := func(name, as string, t *types.Func) {
+		sym := builtin.SymbolByName(name)
+		if sym == nil || sym.Type != link.SymFunc {
+			b.Errorf(nil, "builtin function %s missing", name)
+			return
+		}
+		ref := codegen.NewFuncSym(builtin.Path(), name, makeFuncSig(t))
+		obj := &objFunc{name: as, ref: newRef(t, ref)}
+		declare(as, tast.SymFunc, obj, t)
+	}
+	u := types.Uint
+	fn("CallerFrame", "callerFrame", types.NewFuncUnamed(nil, []types.T{u, u}))
+	fn("CoreID", "coreID", types.NewFuncUnamed(nil, []types.T{u}))
+	fn("SwitchThread", "switchThread", types.NewVoidFunc(u, u))
+	fn("TimerHandler", "timerHandler", types.VoidFunc)
+
+	table := func(name, as string) {
+		sym := codegen.NewHeapSym(link.TablePkg, name, 4, false, true)
+		obj := &objVar{as, newAddressableRef(u, sym)}
+		declare(as, tast.SymVar, obj, u)
+	}
+	table(link.TableFuncs, "gcFuncs")
+	table(link.TableRoots, "gcRoots")
+}
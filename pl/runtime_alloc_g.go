@@ -0,0 +1,140 @@
+package pl
+
+// runtimeAllocSrc is the memory allocator of the runtime, presented as
+// alloc.g in the runtime package.
+const runtimeAllocSrc = `
+// gcMin is the least number of bytes to allocate between collections.
+const gcMin = 64 << 10
+
+var gcAlloc uint // bytes allocated since the last collection
+var gcNext uint  // bytes to allocate before the next collection
+
+// findPages looks for n contiguous free pages. It returns the index of
+// the first page and true when found. Otherwise, it returns the index of
+// the first free page at the end of the heap.
+func findPages(n uint) (uint, bool) {
+	run := uint(0)
+	for i := uint(0); i < heapPages; i++ {
+		if !pageFree(i) {
+			run = 0
+			continue
+		}
+		run++
+		if run == n {
+			return i + 1 - n, true
+		}
+	}
+	return heapPages - run, false
+}
+
+// takePages takes n contiguous free pages, and returns the index of the
+// first page. It collects garbage when the heap cannot grow.
+func takePages(n uint) uint {
+	i, found := findPages(n)
+	if found || growHeap(i+n-heapPages) {
+		return i
+	}
+	collect()
+	i, found = findPages(n)
+	if found || growHeap(i+n-heapPages) {
+		return i
+	}
+	panic() // out of memory
+	return 0
+}
+
+func newSpan(npage, size uint) uint {
+	i := takePages(npage)
+	for j := i; j < i+npage; j++ {
+		spanStart[j] = i
+	}
+	spanSize[i] = size
+	return heapBase + i*pageSize
+}
+
+func allocBlock(n uint) (uint, uint) {
+	if n > smallMax {
+		npage := (n + pageSize - 1) / pageSize
+		size := npage * pageSize
+		return newSpan(npage, size), size
+	}
+
+	c := sizeClass(n)
+	size := uint(8) << c
+	ret := freeLists[c]
+	if ret != 0 {
+		freeLists[c] = *(*uint)(ret + 4)
+		*(*uint)(ret + 4) = 0
+		return ret, size
+	}
+
+	if classNext[c] == classEnd[c] {
+		p := newSpan(1, size)
+		classNext[c] = p
+		classEnd[c] = p + pageSize
+	}
+	ret = classNext[c]
+	classNext[c] += size
+	return ret, size
+}
+
+// Alloc allocates n bytes of zeroed memory for an object of type typ,
+// and returns its address.
+func Alloc(n, typ uint) uint {
+	if ncore <= 1 {
+		return alloc(n, typ)
+	}
+	lock()
+	ret := alloc(n, typ)
+	unlock()
+	return ret
+}
+
+// alloc is Alloc with the lock of the runtime held.
+func alloc(n, typ uint) uint {
+	if gcAlloc >= gcNext {
+		collect()
+	}
+	block, size := allocBlock(n + 4)
+	*(*uint)(block) = typ | blockUsed
+	gcAlloc += size
+	return block + 4
+}
+
+// stopWorld waits until the threads running on the other cores are
+// waiting for the lock, or the cores are idle.
+func stopWorld() {
+	me := coreID()
+	for c := uint(0); c < ncore; c++ {
+		if c == me {
+			continue
+		}
+		for {
+			t := (*thread)(cur[c])
+			if t == nil || uint(t) == idle[c] || t.sp != 0 {
+				break
+			}
+			Preempt = 1
+		}
+	}
+}
+
+// collect collects the garbage in the heap.
+func collect() {
+	stopWorld()
+	gcMarkGlobals()
+	gcMarkStack()
+	gcDrain()
+	for gcOverflow {
+		gcOverflow = false
+		gcRescan()
+	}
+
+	live := sweep()
+	gcAlloc = 0
+	gcNext = live
+	if gcNext < gcMin {
+		gcNext = gcMin
+	}
+}
+`
@@ -0,0 +1,254 @@
+package pl
+
+// runtimeChanSrc implements channels, presented as chan.g in the runtime
+// package.
+const runtimeChanSrc = `
+// A channel keeps the values that are sent but not yet received in a
+// ring buffer. A thread that blocks on a channel waits in one of the
+// queues of the channel, and the thread on the other side hands the
+// value over directly, and wakes it up. Values are passed by their
+// addresses, and copied in and out of the channel.
+
+struct waiter {
+	t     *thread
+	elem  uint // address of the value to send or to receive into
+	index int  // the select case
+	next  *waiter
+}
+
+const waiterSize = 16
+
+struct waitQueue {
+	head *waiter
+	tail *waiter
+}
+
+struct hchan {
+	elemSize uint
+	slot     uint // size of a slot in the buffer
+	buf      uint
+	cap      uint
+	n        uint // number of values in the buffer
+	head     uint // index of the first value in the buffer
+	recvq    waitQueue
+	sendq    waitQueue
+	closed   bool
+}
+
+const hchanSize = 44
+
+func waitPush(q *waitQueue, w *waiter) {
+	if q.tail == nil {
+		q.head = w
+	} else {
+		q.tail.next = w
+	}
+	q.tail = w
+}
+
+// waitPop pops the first waiter whose thread is not woken up yet.
+func waitPop(q *waitQueue) *waiter {
+	for q.head != nil {
+		w := q.head
+		q.head = w.next
+		if q.head == nil {
+			q.tail = nil
+		}
+		if !w.t.woken {
+			return w
+		}
+	}
+	return nil
+}
+
+// waitRemove removes the waiters of thread t.
+func waitRemove(q *waitQueue, t *thread) {
+	var prev *waiter
+	for w := q.head; w != nil; w = w.next {
+		if w.t != t {
+			prev = w
+			continue
+		}
+		if prev == nil {
+			q.head = w.next
+		} else {
+			prev.next = w.next
+		}
+		if q.tail == w {
+			q.tail = prev
+		}
+	}
+}
+
+// wait puts the current thread t in queue q, for the select case index.
+func wait(q *waitQueue, t *thread, elem uint, index int) {
+	w := (*waiter)(alloc(waiterSize, typeWords))
+	w.t = t
+	w.elem = elem
+	w.index = index
+	waitPush(q, w)
+}
+
+// wake wakes up the thread of waiter w.
+func wake(w *waiter, ok bool) {
+	t := w.t
+	t.woken = true
+	t.ok = ok
+	t.index = w.index
+	nblocked--
+	pushRun(t)
+}
+
+// chanClear clears the n bytes at p.
+func chanClear(p, n uint) {
+	for i := uint(0); i < n; i++ {
+		*(*byte)(p + i) = 0
+	}
+}
+
+// chanSlot returns the address of the i-th value in the buffer.
+func chanSlot(c *hchan, i uint) uint {
+	return c.buf + (c.head+i)%c.cap*c.slot
+}
+
+// trySend sends the value at elem without blocking. It returns false
+// when it would block.
+func trySend(c *hchan, elem uint) bool {
+	if c.closed {
+		panic() // send on closed channel
+	}
+	w := waitPop(&c.recvq)
+	if w != nil {
+		memCopy(w.elem, elem, c.elemSize)
+		wake(w, true)
+		return true
+	}
+	if c.n < c.cap {
+		memCopy(chanSlot(c, c.n), elem, c.elemSize)
+		c.n++
+		return true
+	}
+	return false
+}
+
+// tryRecv receives a value into elem without blocking. It returns false
+// when it would block, and the second value is false when the channel is
+// closed and empty.
+func tryRecv(c *hchan, elem uint) (bool, bool) {
+	w := waitPop(&c.sendq)
+	if w != nil {
+		if c.n > 0 {
+			// the buffer is full; the sender takes the freed slot
+			p := chanSlot(c, 0)
+			memCopy(elem, p, c.elemSize)
+			memCopy(p, w.elem, c.elemSize)
+			c.head = (c.head + 1) % c.cap
+		} else {
+			memCopy(elem, w.elem, c.elemSize)
+		}
+		wake(w, true)
+		return true, true
+	}
+	if c.n > 0 {
+		p := chanSlot(c, 0)
+		memCopy(elem, p, c.elemSize)
+		chanClear(p, c.elemSize)
+		c.head = (c.head + 1) % c.cap
+		c.n--
+		return true, true
+	}
+	if c.closed {
+		chanClear(elem, c.elemSize)
+		return true, false
+	}
+	return false, false
+}
+
+// ChanMake makes a channel of values of elemSize bytes of type typ, with
+// a buffer of n values.
+func ChanMake(elemSize, typ, n uint) uint {
+	c := (*hchan)(Alloc(hchanSize, typeWords))
+	c.elemSize = elemSize
+	c.slot = (elemSize + 3) / 4 * 4
+	c.cap = n
+	if n > 0 {
+		c.buf = Alloc(n*c.slot, typ)
+	}
+	return uint(c)
+}
+
+// ChanSend sends the value at elem into channel ch.
+func ChanSend(ch, elem uint) {
+	c := (*hchan)(ch)
+	lock()
+	t := curThread()
+	t.woken = false
+	if c == nil {
+		block(t) // blocks forever
+	}
+	if !trySend(c, elem) {
+		wait(&c.sendq, t, elem, 0)
+		block(t)
+		if !t.ok {
+			panic() // the channel is closed
+		}
+	}
+	unlock()
+}
+
+// ChanRecv receives a value from channel ch into elem. It returns false
+// when the channel is closed.
+func ChanRecv(ch, elem uint) bool {
+	c := (*hchan)(ch)
+	lock()
+	t := curThread()
+	t.woken = false
+	if c == nil {
+		block(t) // blocks forever
+	}
+	done, ok := tryRecv(c, elem)
+	if !done {
+		wait(&c.recvq, t, elem, 0)
+		block(t)
+		ok = t.ok
+	}
+	unlock()
+	return ok
+}
+
+// ChanClose closes channel ch, and wakes up all the threads that wait on
+// it.
+func ChanClose(ch uint) {
+	c := (*hchan)(ch)
+	lock()
+	if c == nil || c.closed {
+		panic()
+	}
+	c.closed = true
+	for {
+		w := waitPop(&c.recvq)
+		if w == nil {
+			break
+		}
+		chanClear(w.elem, c.elemSize)
+		wake(w, false)
+	}
+	for {
+		w := waitPop(&c.sendq)
+		if w == nil {
+			break
+		}
+		wake(w, false)
+	}
+	unlock()
+}
+
+// ChanLen returns the number of values in the buffer of channel ch.
+func ChanLen(ch uint) int {
+	c := (*hchan)(ch)
+	if c == nil {
+		return 0
+	}
+	return int(c.n)
+}
+`
@@ -0,0 +1,46 @@
+package pl
+
+// runtimeDeferSrc implements deferred calls, presented as defer.g in the
+// runtime package.
+const runtimeDeferSrc = `
+// A deferred call is saved in a record in the heap, which is also a
+// function value: the first word is the address of the code that makes
+// the call with the saved function and arguments. The records of all
+// the frames of a thread are chained in a stack, and a function with
+// deferred calls remembers the top of the stack when it starts, so that
+// it runs its own deferred calls when it returns.
+
+// DeferPush pushes a record of size bytes for a deferred call, and
+// returns the address of the record. The code of the record is copied
+// from the function value fv.
+func DeferPush(size, fv uint) uint {
+	t := curThread()
+	r := (*deferRec)(Alloc(size, typeWords))
+	r.code = *(*uint)(fv)
+	r.next = t.defers
+	t.defers = r
+	return uint(r)
+}
+
+// DeferMark returns the top of the stack of the deferred calls.
+func DeferMark() uint { return uint(curThread().defers) }
+
+// DeferRun runs the deferred calls in the stack until the top is mark.
+func DeferRun(mark uint) {
+	t := curThread()
+	for uint(t.defers) != mark {
+		r := t.defers
+		t.defers = r.next
+
+		var f func()
+		*(*uint)(uint(&f)) = uint(r)
+		f()
+	}
+}
+
+// Panic runs all the deferred calls, and then panics.
+func Panic() {
+	DeferRun(0)
+	panic()
+}
+`
@@ -0,0 +1,167 @@
+package pl
+
+// runtimeGCSrc is the garbage collector of the runtime, presented as
+// gc.g in the runtime package.
+const runtimeGCSrc = `
+// The garbage collector marks the objects that can be reached from the
+// global variables and the stack, and then frees the rest.
+//
+// The linker provides two tables. gcRoots lists the global variables
+// that have pointers; each entry is the address of the variable, the
+// number of runs, and the runs of pointers. It ends with a zero word.
+// gcFuncs starts with the number of functions, followed by an entry of
+// the address, the size and the offset of the frame layout for each
+// function, sorted by the addresses. A frame layout is the size of the
+// frame, the offset of the saved return address, the number of runs,
+// and the runs of pointers. All offsets in frames are from the stack
+// pointer.
+
+// gcStack is the stack of marked blocks that are waiting for being
+// scanned. When it overflows, the heap is rescanned.
+var gcStack [1024]uint
+var gcDepth uint
+var gcOverflow bool
+
+// gcMark marks the block that p points into.
+func gcMark(p uint) {
+	block, _ := heapBlock(p)
+	if block == 0 {
+		return
+	}
+	h := (*uint)(block)
+	if *h&blockUsed == 0 || *h&blockMarked != 0 {
+		return
+	}
+	*h |= blockMarked
+	if *h < typeWords {
+		return // no pointers
+	}
+	if gcDepth == uint(len(gcStack)) {
+		gcOverflow = true
+		return
+	}
+	gcStack[gcDepth] = block
+	gcDepth++
+}
+
+// gcMarkRuns marks the pointers in n runs of words after base.
+func gcMarkRuns(base, runs, n uint) {
+	for i := uint(0); i < n; i++ {
+		p := base + *(*uint)(runs + i*8)
+		end := p + *(*uint)(runs + i*8 + 4)*4
+		for ; p < end; p += 4 {
+			v := *(*uint)(p)
+			if v != 0 {
+				gcMark(v)
+			}
+		}
+	}
+}
+
+// gcScan marks the pointers in a block.
+func gcScan(block, size uint) {
+	typ := *(*uint)(block) / 4 * 4 // clears the flags
+	p := block + 4
+	end := block + size
+	if typ == typeWords {
+		for ; p < end; p += 4 {
+			gcMark(*(*uint)(p))
+		}
+		return
+	}
+
+	elemSize := *(*uint)(typ)
+	nrun := *(*uint)(typ + 4)
+	for ; p+elemSize <= end; p += elemSize {
+		gcMarkRuns(p, typ+8, nrun)
+	}
+}
+
+func gcDrain() {
+	for gcDepth > 0 {
+		gcDepth--
+		block := gcStack[gcDepth]
+		_, size := heapBlock(block + 4)
+		gcScan(block, size)
+	}
+}
+
+// gcRescan scans all the marked blocks again after gcStack overflows.
+func gcRescan() {
+	for i := uint(0); i < heapPages; i++ {
+		size := spanSize[i]
+		if size == 0 {
+			continue
+		}
+		p := heapBase + i*pageSize
+		end := p + pageSize
+		if size > pageSize {
+			end = p + size
+		}
+		for ; p < end; p += size {
+			if *(*uint)(p)&blockMarked != 0 && *(*uint)(p) >= typeWords {
+				gcScan(p, size)
+				gcDrain()
+			}
+		}
+	}
+}
+
+func gcMarkGlobals() {
+	p := uint(&gcRoots)
+	for *(*uint)(p) != 0 {
+		nrun := *(*uint)(p + 4)
+		gcMarkRuns(*(*uint)(p), p+8, nrun)
+		p += 8 + nrun*8
+	}
+}
+
+// gcFindFunc returns the frame layout of the function that has the code
+// at pc, or 0 if not found.
+func gcFindFunc(pc uint) uint {
+	tab := uint(&gcFuncs)
+	lo := uint(0)
+	hi := *(*uint)(tab)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		e := tab + 4 + mid*12
+		start := *(*uint)(e)
+		if pc < start {
+			hi = mid
+		} else if pc >= start+*(*uint)(e+4) {
+			lo = mid + 1
+		} else {
+			return tab + *(*uint)(e+8)
+		}
+	}
+	return 0
+}
+
+// gcMarkFrames marks the pointers in the frames of a stack, starting
+// from the frame of stack pointer sp that runs the code at pc.
+func gcMarkFrames(sp, pc uint) {
+	for {
+		f := gcFindFunc(pc)
+		if f == 0 {
+			return
+		}
+		frameSize := *(*uint)(f)
+		gcMarkRuns(sp, f+12, *(*uint)(f + 8))
+		pc = *(*uint)(sp + *(*uint)(f + 4))
+		sp += frameSize
+	}
+}
+
+// gcMarkStack marks the pointers in the stacks of all the threads. The
+// threads that are not running are saved with their frames.
+func gcMarkStack() {
+	me := curThread()
+	for t := allThreads; t != nil; t = t.all {
+		if t != me && t.started && t.sp != 0 {
+			gcMarkFrames(t.sp, t.pc)
+		}
+	}
+	sp, pc := callerFrame()
+	gcMarkFrames(sp, pc)
+}
+`
@@ -0,0 +1,106 @@
+package pl
+
+// runtimeHeapSrc is the layout of the heap of the runtime, presented as
+// heap.g in the runtime package.
+const runtimeHeapSrc = `
+// The heap is a contiguous range of pages. Each page in use belongs to a
+// span. A small span is a single page that is divided into blocks of the
+// same size class, and a large span is a block of one or more pages.
+//
+// Each block starts with a header word, which is the address of the type
+// of the object in the block, with the flags in the lowest bits. The
+// object follows the header. A type is an array of words: the size of an
+// element, the number of runs of pointers in an element, and the runs,
+// where each run is an offset and a number of words. The object in a
+// block is an array of elements of its type. A type of 0 means that the
+// object has no pointers.
+
+const heapMaxPages = 4096 // 16MB
+
+// Size classes are the powers of 2 from 8 to smallMax.
+const (
+	nclass   = 9
+	smallMax = 8 << (nclass - 1)
+)
+
+// Flags in block headers.
+const (
+	blockMarked = 1
+	blockUsed   = 2
+	blockFlags  = 3
+)
+
+// typeWords is the type of the objects in which every word might be a
+// pointer. The runtime uses it for the objects it allocates for itself.
+const typeWords = 4
+
+var heapBase uint  // address of the first page
+var heapPages uint // number of pages taken
+
+// For page i, spanStart[i] is the first page of its span. For the first
+// page of a span, spanSize[i] is the size of the blocks in the span, or
+// 0 when the page is free.
+var spanStart [heapMaxPages]uint
+var spanSize [heapMaxPages]uint
+
+// freeLists are the free blocks of each size class, linked by the words
+// after their headers. Free blocks are zero except for the links.
+var freeLists [nclass]uint
+
+// Blocks of each size class that have never been used are allocated from
+// the range between classNext and classEnd, which is the rest of the last
+// small span of the class.
+var classNext [nclass]uint
+var classEnd [nclass]uint
+
+func sizeClass(n uint) uint {
+	c := uint(0)
+	for uint(8)<<c < n {
+		c++
+	}
+	return c
+}
+
+func pageFree(i uint) bool {
+	return spanStart[i] == i && spanSize[i] == 0
+}
+
+// growHeap adds n pages at the end of the heap. It returns false when
+// the pages run out.
+func growHeap(n uint) bool {
+	if n > heapMaxPages-heapPages {
+		return false
+	}
+	p := PageAlloc(n)
+	if p == 0 {
+		return false
+	}
+	if heapPages == 0 {
+		heapBase = p
+	} else if p != heapBase+heapPages*pageSize {
+		panic() // heap not contiguous
+	}
+	for i := heapPages; i < heapPages+n; i++ {
+		spanStart[i] = i
+	}
+	heapPages += n
+	return true
+}
+
+// heapBlock returns the address and the size of the heap block of which
+// the object contains the address p or ends at p. It returns 0, 0 when
+// there is no such block.
+func heapBlock(p uint) (uint, uint) {
+	if p <= heapBase || p > heapBase+heapPages*pageSize {
+		return 0, 0
+	}
+	p--
+	s := spanStart[(p-heapBase)/pageSize]
+	size := spanSize[s]
+	if size == 0 {
+		return 0, 0
+	}
+	start := heapBase + s*pageSize
+	return start + (p-start)/size*size, size
+}
+`
@@ -0,0 +1,230 @@
+package pl
+
+// runtimeMapSrc implements the built-in map type, presented as map.g in
+// the runtime package.
+const runtimeMapSrc = `
+// A map is a pointer to its header. Entries are chained in the buckets
+// by their hashes; each entry is a header followed by the key and the
+// value, both aligned to words.
+
+struct mapHeader {
+	count   int
+	keySize uint
+	valSize uint
+	nbucket uint
+	buckets uint // address of the bucket array
+
+	entryType uint // the type of the entries for the allocator
+}
+
+struct mapEntry {
+	next *mapEntry
+	hash uint
+}
+
+// mapIter saves the state of iterating a map.
+struct mapIter {
+	bucket uint
+	cur    *mapEntry
+}
+
+const mapInitBuckets = 8
+
+func mapAlign(n uint) uint { return (n + 3) / 4 * 4 }
+
+func mapKey(e *mapEntry) uint { return uint(e) + 8 }
+
+func mapVal(h *mapHeader, e *mapEntry) uint {
+	return mapKey(e) + mapAlign(h.keySize)
+}
+
+func mapBucket(h *mapHeader, hash uint) **mapEntry {
+	return (**mapEntry)(h.buckets + (hash&(h.nbucket-1))*4)
+}
+
+// mapBytes returns the bytes of a key. Keys of 8 bytes are strings.
+func mapBytes(h *mapHeader, key uint) (uint, uint) {
+	if h.keySize == 8 {
+		return *(*uint)(key), *(*uint)(key + 4)
+	}
+	return key, h.keySize
+}
+
+func mapHash(h *mapHeader, key uint) uint {
+	p, n := mapBytes(h, key)
+	ret := uint(2166136261)
+	for i := uint(0); i < n; i++ {
+		ret = (ret ^ uint(*(*byte)(p + i))) * 16777619
+	}
+	return ret
+}
+
+func mapKeyEqual(h *mapHeader, k1, k2 uint) bool {
+	p1, n1 := mapBytes(h, k1)
+	p2, n2 := mapBytes(h, k2)
+	return BytesEqual(p1, n1, p2, n2)
+}
+
+func mapFind(h *mapHeader, key, hash uint) *mapEntry {
+	for e := *mapBucket(h, hash); e != nil; e = e.next {
+		if e.hash == hash && mapKeyEqual(h, mapKey(e), key) {
+			return e
+		}
+	}
+	return nil
+}
+
+func mapGrow(h *mapHeader) {
+	old, n := h.buckets, h.nbucket
+	h.nbucket = n * 2
+	h.buckets = Alloc(h.nbucket*4, typeWords)
+	for i := uint(0); i < n; i++ {
+		e := *(**mapEntry)(old + i*4)
+		for e != nil {
+			next := e.next
+			slot := mapBucket(h, e.hash)
+			e.next = *slot
+			*slot = e
+			e = next
+		}
+	}
+}
+
+func typeRuns(t uint) uint {
+	if t == 0 {
+		return 0
+	}
+	return *(*uint)(t + 4)
+}
+
+// copyRuns copies the runs of pointers of type t to p, with the offsets
+// moved by off. It returns the address after the copied runs.
+func copyRuns(p, t, off uint) uint {
+	n := typeRuns(t)
+	for i := uint(0); i < n; i++ {
+		*(*uint)(p) = *(*uint)(t + 8 + i*8) + off
+		*(*uint)(p + 4) = *(*uint)(t + 12 + i*8)
+		p += 8
+	}
+	return p
+}
+
+// mapEntryType makes the type of the entries of a map.
+func mapEntryType(h *mapHeader, keyType, valType uint) uint {
+	nrun := 1 + typeRuns(keyType) + typeRuns(valType)
+	t := Alloc(8+nrun*8, 0)
+	*(*uint)(t) = 8 + mapAlign(h.keySize) + h.valSize
+	*(*uint)(t + 4) = nrun
+	*(*uint)(t + 12) = 1 // the next pointer
+	p := copyRuns(t+16, keyType, 8)
+	copyRuns(p, valType, 8+mapAlign(h.keySize))
+	return t
+}
+
+// MapMake creates a new map, of which the keys and the values are of the
+// types keyType and valType.
+func MapMake(keySize, valSize, keyType, valType uint) uint {
+	h := (*mapHeader)(Alloc(24, typeWords))
+	h.keySize = keySize
+	h.valSize = valSize
+	h.nbucket = mapInitBuckets
+	h.buckets = Alloc(mapInitBuckets*4, typeWords)
+	h.entryType = mapEntryType(h, keyType, valType)
+	return uint(h)
+}
+
+// MapLen returns the number of entries in a map.
+func MapLen(m uint) int {
+	if m == 0 {
+		return 0
+	}
+	return (*mapHeader)(m).count
+}
+
+// MapAccess returns the address of the value of a key, or 0 if the key is
+// not in the map.
+func MapAccess(m, key uint) uint {
+	if m == 0 {
+		return 0
+	}
+	h := (*mapHeader)(m)
+	e := mapFind(h, key, mapHash(h, key))
+	if e == nil {
+		return 0
+	}
+	return mapVal(h, e)
+}
+
+// MapAssign returns the address of the value of a key for assigning. The
+// key is added with a zero value if it is not in the map yet.
+func MapAssign(m, key uint) uint {
+	if m == 0 {
+		panic() // assignment to entry in nil map
+	}
+	h := (*mapHeader)(m)
+	hash := mapHash(h, key)
+	e := mapFind(h, key, hash)
+	if e != nil {
+		return mapVal(h, e)
+	}
+
+	if uint(h.count) >= h.nbucket*2 {
+		mapGrow(h)
+	}
+	size := 8 + mapAlign(h.keySize) + h.valSize
+	e = (*mapEntry)(Alloc(size, h.entryType))
+	e.hash = hash
+	k := mapKey(e)
+	for i := uint(0); i < h.keySize; i++ {
+		*(*byte)(k + i) = *(*byte)(key + i)
+	}
+	slot := mapBucket(h, hash)
+	e.next = *slot
+	*slot = e
+	h.count++
+	return mapVal(h, e)
+}
+
+// MapDelete deletes a key from a map.
+func MapDelete(m, key uint) {
+	if m == 0 {
+		return
+	}
+	h := (*mapHeader)(m)
+	hash := mapHash(h, key)
+	for slot := mapBucket(h, hash); *slot != nil; slot = &(*slot).next {
+		e := *slot
+		if e.hash == hash && mapKeyEqual(h, mapKey(e), key) {
+			*slot = e.next
+			h.count--
+			return
+		}
+	}
+}
+
+// MapNext advances an iterator of a map, and returns the address of the
+// key of the next entry, or 0 when there are no more entries. The
+// address of the value follows the key, aligned to words. When entries
+// are added during the iteration, the map might grow, and the iteration
+// might then return an entry more than once.
+func MapNext(m, it uint) uint {
+	if m == 0 {
+		return 0
+	}
+	h := (*mapHeader)(m)
+	iter := (*mapIter)(it)
+	var e *mapEntry
+	if iter.cur != nil {
+		e = iter.cur.next
+	}
+	for e == nil {
+		if iter.bucket >= h.nbucket {
+			return 0
+		}
+		e = *(**mapEntry)(h.buckets + iter.bucket*4)
+		iter.bucket++
+	}
+	iter.cur = e
+	return mapKey(e)
+}
+`
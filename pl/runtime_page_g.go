@@ -0,0 +1,61 @@
+package pl
+
+// runtimePageSrc gets memory pages for the heap, presented as page.g in
+// the runtime package.
+const runtimePageSrc = `
+// The heap takes its memory from PageAlloc, page by page.
+
+const pageSize = 4096
+
+// sysInfoAddr is the address of the system information page. The first
+// word is the number of physical pages, and the third word is the end of
+// the program image.
+const sysInfoAddr = 0x7000
+
+// stackPages is the number of pages kept for the stack below the stack
+// pointer when the heap starts.
+const stackPages = 16
+
+// PageSource provides the pages for the heap when it is not nil. A kernel
+// sets it to give pages from its own memory management. It is called
+// with the number of pages wanted, and returns the address of the first
+// page, or 0 when the memory runs out.
+var PageSource func(n uint) uint
+
+var pageNext uint  // the next free page on bare metal
+var pageLimit uint // the end of the pages for the heap on bare metal
+
+// PageAlloc asks for n contiguous pages of zeroed memory, and returns the
+// address of the first page, or 0 when the memory runs out.
+func PageAlloc(n uint) uint {
+	if PageSource != nil {
+		return PageSource(n)
+	}
+	return bareMetalPages(n)
+}
+
+// bareMetalPages takes pages from the physical memory between the
+// program image and the stack.
+func bareMetalPages(n uint) uint {
+	if pageNext == 0 {
+		end := *(*uint)(uint(sysInfoAddr + 8))
+		if end == 0 {
+			return 0
+		}
+		pageNext = (end + pageSize - 1) / pageSize
+
+		sp, _ := callerFrame()
+		pageLimit = *(*uint)(uint(sysInfoAddr))
+		if sp/pageSize < pageLimit+stackPages {
+			pageLimit = sp/pageSize - stackPages
+		}
+	}
+
+	if pageNext >= pageLimit || n > pageLimit-pageNext {
+		return 0
+	}
+	ret := pageNext * pageSize
+	pageNext += n
+	return ret
+}
+`
@@ -0,0 +1,226 @@
+package pl
+
+// runtimeSchedSrc is the thread scheduler of the runtime, presented as
+// sched.g in the runtime package.
+const runtimeSchedSrc = `
+// Threads are scheduled on all the cores. The main thread starts on the
+// first core. Every other core waits for the scheduler to start, and
+// then runs its idle thread, which waits for threads in the run queue.
+//
+// A thread gives up its core when it blocks, or when it yields. Once the
+// first thread is started, the timer of each core sets Preempt
+// periodically, and the compiled code checks it in loops, where it
+// calls Yield() when it is set.
+//
+// A thread that switches to another thread holds the lock of the
+// runtime, and the thread that it switches to releases it.
+
+// threadStack is the size of the stack of a thread.
+const threadStack = 4*pageSize - 4
+
+// timeSlice is the number of cycles between timer interrupts.
+const timeSlice = 5000
+
+var runHead, runTail *thread
+var nthread uint  // number of threads that have not exited
+var nblocked uint // number of threads that are blocked
+var schedStarted bool
+
+// handlerStacks are the stacks of the timer interrupt handlers. Each
+// core takes 16 words, and the stack pointer starts at the eighth,
+// which saves the address of Preempt.
+var handlerStacks [maxCores * 16]uint
+
+func init() {
+	ncore = *(*uint)(uint(sysInfoAddr + 4))
+	if coreID() != 0 {
+		idleLoop()
+	}
+	mainThread.started = true
+	cur[0] = uint(&mainThread)
+	allThreads = &mainThread
+	nthread = 1
+}
+
+func pushRun(t *thread) {
+	t.next = nil
+	if runTail == nil {
+		runHead = t
+	} else {
+		runTail.next = t
+	}
+	runTail = t
+}
+
+func popRun() *thread {
+	t := runHead
+	if t != nil {
+		runHead = t.next
+		if runHead == nil {
+			runTail = nil
+		}
+		t.next = nil
+	}
+	return t
+}
+
+// park switches from thread t to the next runnable thread, or to the
+// idle thread of the core when there is none. The lock is held, and is
+// held again when t resumes, maybe on another core.
+func park(t *thread) {
+	me := coreID()
+	next := popRun()
+	if next == nil {
+		if nblocked == nthread {
+			panic() // deadlock
+		}
+		next = (*thread)(idle[me])
+	}
+	cur[me] = uint(next)
+	switchThread(uint(t), uint(next))
+	t.sp = 0
+}
+
+// block blocks thread t until another thread wakes it up.
+func block(t *thread) {
+	nblocked++
+	park(t)
+}
+
+// Yield lets the other runnable threads run.
+func Yield() {
+	Preempt = 0
+	lock()
+	if runHead != nil {
+		t := curThread()
+		pushRun(t)
+		park(t)
+	}
+	unlock()
+}
+
+func idleStart() {
+	unlock()
+	idleLoop()
+}
+
+// idleLoop runs the threads in the run queue on this core.
+func idleLoop() {
+	me := coreID()
+	for idle[me] == 0 {
+	}
+	t := (*thread)(idle[me])
+	cur[me] = uint(t)
+	for {
+		for runHead == nil {
+		}
+		lock()
+		next := popRun()
+		if next != nil {
+			cur[me] = uint(next)
+			switchThread(uint(t), uint(next))
+			t.sp = 0
+		}
+		unlock()
+	}
+}
+
+// threadStart is where a thread starts, with the lock held.
+func threadStart() {
+	t := curThread()
+	t.started = true
+	t.sp = 0
+	unlock()
+
+	var f func()
+	*(*uint)(uint(&f)) = t.fn
+	f()
+
+	lock()
+	if allThreads == t {
+		allThreads = t.all
+	} else {
+		p := allThreads
+		for p.all != t {
+			p = p.all
+		}
+		p.all = t.all
+	}
+	nthread--
+	park(t)
+}
+
+// funcCode returns the address of the code of a function value.
+func funcCode(f func()) uint {
+	return *(*uint)(*(*uint)(uint(&f)))
+}
+
+// Addresses of the interrupt controls and the timer.
+const (
+	intPage   = 0x1000 // 128 bytes for each core
+	timerAddr = 0x2200 // the cycle counter, followed by 16 bytes per core
+)
+
+// schedStart creates the idle threads, and starts the timer interrupts
+// on all the cores. The idle thread of the first core runs on a stack of
+// its own, and the others run on the stacks of their cores.
+func schedStart() {
+	for c := ncore; c > 0; c-- {
+		t := (*thread)(alloc(threadSize, typeWords))
+		t.all = idleThreads
+		idleThreads = t
+		idle[c-1] = uint(t)
+	}
+	t := idleThreads
+	t.stack = (*uint)(alloc(threadStack, 0))
+	t.sp = uint(t.stack) + threadStack
+	t.pc = funcCode(idleStart)
+
+	cycle := *(*uint)(uint(timerAddr))
+	for c := uint(0); c < ncore; c++ {
+		hsp := uint(&handlerStacks[c*16+8])
+		*(*uint)(hsp) = uint(&Preempt)
+
+		in := intPage + c*128
+		*(*uint)(in + 4) = hsp
+		*(*uint)(in + 8) = funcCode(timerHandler)
+		*(*byte)(in + 34) |= 0x8 // IntTimer is 19
+
+		tm := timerAddr + 16 + c*16
+		*(*uint)(tm + 4) = cycle + timeSlice
+		*(*uint)(tm + 8) = timeSlice
+		*(*byte)(tm) = 3 // enabled and periodic
+
+		*(*byte)(in) |= 1
+	}
+}
+
+// GoRecord allocates a record of size bytes for a go call, which is
+// also a function value. The code of the record is copied from the
+// function value fv.
+func GoRecord(size, fv uint) uint {
+	r := Alloc(size, typeWords)
+	*(*uint)(r) = *(*uint)(fv)
+	return r
+}
+
+// Go starts a thread that calls the record of a go call.
+func Go(r uint) {
+	t := (*thread)(Alloc(threadSize, typeWords))
+	t.stack = (*uint)(Alloc(threadStack, 0))
+	t.sp = uint(t.stack) + threadStack
+	t.pc = funcCode(threadStart)
+	t.fn = r
+
+	lock()
+	if !schedStarted {
+		schedStarted = true
+		schedStart()
+	}
+	t.all = allThreads
+	allThreads = t
+	nthread++
+	pushRun(t)
+	unlock()
+}
+`
@@ -0,0 +1,94 @@
+package pl
+
+// runtimeSelectSrc implements select statements, presented as select.g
+// in the runtime package.
+const runtimeSelectSrc = `
+// selectCase is a case of a select. The compiler lays out the cases in
+// an array.
+struct selectCase {
+	c    *hchan
+	elem uint
+	send bool
+	ok   bool // set when a receive case is chosen
+}
+
+const selectCaseSize = 12
+
+var selectRand uint
+
+// nextRand returns the next number of an xorshift generator.
+func nextRand() uint {
+	x := selectRand
+	if x == 0 {
+		x = 2463534242
+	}
+	x ^= x << 13
+	x ^= x >> 17
+	x ^= x << 5
+	selectRand = x
+	return x
+}
+
+// selectTry tries a case of a select without blocking.
+func selectTry(sc *selectCase) bool {
+	if sc.send {
+		return trySend(sc.c, sc.elem)
+	}
+	done, ok := tryRecv(sc.c, sc.elem)
+	sc.ok = ok
+	return done
+}
+
+// Select runs one of the n cases that are ready, starting from a random
+// one, and returns its index. When none is ready, it returns -1 when
+// blocking is false, or waits for one of them otherwise.
+func Select(cases, n uint, blocking bool) int {
+	lock()
+	start := uint(0)
+	if n > 0 {
+		start = nextRand() % n
+	}
+	for j := uint(0); j < n; j++ {
+		i := (start + j) % n
+		sc := (*selectCase)(cases + i*selectCaseSize)
+		if sc.c != nil && selectTry(sc) {
+			unlock()
+			return int(i)
+		}
+	}
+	if !blocking {
+		unlock()
+		return -1
+	}
+
+	t := curThread()
+	t.woken = false
+	for i := uint(0); i < n; i++ {
+		sc := (*selectCase)(cases + i*selectCaseSize)
+		if sc.c == nil {
+			continue
+		}
+		if sc.send {
+			wait(&sc.c.sendq, t, sc.elem, int(i))
+		} else {
+			wait(&sc.c.recvq, t, sc.elem, int(i))
+		}
+	}
+	block(t)
+
+	for i := uint(0); i < n; i++ {
+		sc := (*selectCase)(cases + i*selectCaseSize)
+		if sc.c != nil {
+			waitRemove(&sc.c.sendq, t)
+			waitRemove(&sc.c.recvq, t)
+		}
+	}
+	sc := (*selectCase)(cases + uint(t.index)*selectCaseSize)
+	if sc.send && !t.ok {
+		panic() // the channel is closed
+	}
+	sc.ok = t.ok
+	unlock()
+	return t.index
+}
+`
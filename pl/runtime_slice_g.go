@@ -0,0 +1,73 @@
+package pl
+
+// runtimeSliceSrc implements growing slices, presented as slice.g in the
+// runtime package.
+const runtimeSliceSrc = `
+func memCopy(dest, src, n uint) {
+	if (dest|src|n)&3 == 0 {
+		for i := uint(0); i < n; i += 4 {
+			*(*uint)(dest + i) = *(*uint)(src + i)
+		}
+		return
+	}
+	for i := uint(0); i < n; i++ {
+		*(*byte)(dest + i) = *(*byte)(src + i)
+	}
+}
+
+// memMove is memCopy where the source and the destination can overlap.
+func memMove(dest, src, n uint) {
+	if dest <= src || dest >= src+n {
+		memCopy(dest, src, n)
+		return
+	}
+	for i := n; i > 0; i-- {
+		*(*byte)(dest + i - 1) = *(*byte)(src + i - 1)
+	}
+}
+
+// BytesEqual checks if the n1 bytes at p1 are the same as the n2 bytes at
+// p2. It compares strings.
+func BytesEqual(p1, n1, p2, n2 uint) bool {
+	if n1 != n2 {
+		return false
+	}
+	for i := uint(0); i < n1; i++ {
+		if *(*byte)(p1 + i) != *(*byte)(p2 + i) {
+			return false
+		}
+	}
+	return true
+}
+
+// SliceGrow makes room for appending add elements to the n elements at
+// data, where each element is of type typ and takes size bytes. It
+// returns the address of the elements, which is a new copy when the heap
+// block at data does not have the room. Elements not in the heap are
+// always copied.
+func SliceGrow(data, n, add, size, typ uint) uint {
+	block, blockSize := heapBlock(data)
+	if block != 0 && (n+add)*size <= block+blockSize-data {
+		return data
+	}
+
+	c := n * 2
+	if c < n+add {
+		c = n + add
+	}
+	ret := Alloc(c*size, typ)
+	memCopy(ret, data, n*size)
+	return ret
+}
+
+// SliceAppend appends the add elements at src to the n elements at data,
+// and returns the address of the elements like SliceGrow.
+func SliceAppend(data, n, src, add, size, typ uint) uint {
+	if add == 0 {
+		return data
+	}
+	ret := SliceGrow(data, n, add, size, typ)
+	memMove(ret+n*size, src, add*size)
+	return ret
+}
+`
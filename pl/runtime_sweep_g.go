@@ -0,0 +1,97 @@
+package pl
+
+// runtimeSweepSrc frees the garbage found by the collector, presented as
+// sweep.g in the runtime package.
+const runtimeSweepSrc = `
+// memClear clears n bytes at p, where n is a multiple of 8.
+func memClear(p, n uint) {
+	for end := p + n; p < end; p += 8 {
+		*(*uint)(p) = 0
+		*(*uint)(p + 4) = 0
+	}
+}
+
+// freePages returns n pages of a span to the heap. The pages must have
+// been cleared.
+func freePages(i, n uint) {
+	for j := i; j < i+n; j++ {
+		spanStart[j] = j
+		spanSize[j] = 0
+	}
+}
+
+// sweepBlock frees a block if it is not marked, and returns true if the
+// block is still in use.
+func sweepBlock(p, size uint) bool {
+	h := (*uint)(p)
+	if *h&blockMarked != 0 {
+		*h -= blockMarked
+		return true
+	}
+	if *h != 0 {
+		memClear(p, size)
+	}
+	*(*uint)(p + 4) = 0 // the link of a free block
+	return false
+}
+
+// sweepSpan sweeps a small span. It returns the number of blocks that
+// are still in use.
+func sweepSpan(i, size uint) uint {
+	p := heapBase + i*pageSize
+	end := p + pageSize
+	n := uint(0)
+	for b := p; b < end; b += size {
+		if sweepBlock(b, size) {
+			n++
+		}
+	}
+	if n == 0 {
+		freePages(i, 1)
+		return 0
+	}
+
+	c := sizeClass(size)
+	for b := p; b < end; b += size {
+		if *(*uint)(b) == 0 {
+			*(*uint)(b + 4) = freeLists[c]
+			freeLists[c] = b
+		}
+	}
+	return n
+}
+
+// sweep frees the blocks that are not marked, and returns the number of
+// bytes still in use. The free lists are built again from the free
+// blocks, and the empty spans are returned to the heap.
+func sweep() uint {
+	for c := 0; c < nclass; c++ {
+		freeLists[c] = 0
+		classNext[c] = 0
+		classEnd[c] = 0
+	}
+
+	live := uint(0)
+	for i := uint(0); i < heapPages; {
+		size := spanSize[i]
+		if size == 0 {
+			i++
+		} else if size <= smallMax {
+			live += sweepSpan(i, size) * size
+			i++
+		} else {
+			npage := size / pageSize
+			h := (*uint)(heapBase + i*pageSize)
+			if *h&blockMarked != 0 {
+				*h -= blockMarked
+				live += size
+			} else {
+				memClear(heapBase+i*pageSize, size)
+				freePages(i, npage)
+			}
+			i += npage
+		}
+	}
+	return live
+}
+`
@@ -0,0 +1,107 @@
+package pl
+
+// runtimeThreadSrc is the threads and the lock of the runtime, presented
+// as thread.g in the runtime package.
+const runtimeThreadSrc = `
+// The states of the scheduler, the heap and the channels are protected
+// by one lock, which is a bakery lock, as the cores have no atomic
+// instructions.
+
+const maxCores = 32
+
+struct thread {
+	// The saved context, which is the stack pointer, the program counter
+	// and r3. When the thread waits for the lock, sp and pc is its frame
+	// for the garbage collector.
+	sp uint
+	pc uint
+	r3 uint
+
+	fn     uint // the record of the go call
+	stack  *uint
+	defers *deferRec
+	next   *thread // in the run queue
+	all    *thread // in the list of all threads
+
+	index   int  // the select case that wakes the thread up
+	started bool
+	woken   bool
+	ok      bool // if the channel operation that wakes the thread is ok
+}
+
+// threadSize is the size of a thread.
+const threadSize = 40
+
+// deferRec is the record of a deferred call. See defer.g.
+struct deferRec {
+	code uint
+	next *deferRec
+}
+
+var ncore uint
+var mainThread thread
+var allThreads *thread
+
+// cur and idle are the running thread and the idle thread of each core.
+// They are kept as words, so that the garbage collector does not scan
+// them; the threads are all in allThreads or idleThreads.
+var cur [maxCores]uint
+var idle [maxCores]uint
+var idleThreads *thread
+
+// Preempt is set by the timer interrupts when a thread should yield.
+var Preempt uint
+
+var choosing [maxCores]bool
+var ticket [maxCores]uint
+
+func curThread() *thread { return (*thread)(cur[coreID()]) }
+
+// lock takes the lock of the runtime. The frame of the caller is saved
+// in the current thread while waiting, so that the garbage collector can
+// scan its stack.
+func lock() {
+	if ncore <= 1 {
+		return
+	}
+	me := coreID()
+	t := (*thread)(cur[me])
+	if t != nil {
+		sp, pc := callerFrame()
+		t.pc = pc
+		t.sp = sp
+	}
+
+	choosing[me] = true
+	top := uint(0)
+	for i := uint(0); i < ncore; i++ {
+		if ticket[i] > top {
+			top = ticket[i]
+		}
+	}
+	ticket[me] = top + 1
+	choosing[me] = false
+
+	for i := uint(0); i < ncore; i++ {
+		if i == me {
+			continue
+		}
+		for choosing[i] {
+		}
+		for ticket[i] != 0 && (ticket[i] < ticket[me] ||
+			ticket[i] == ticket[me] && i < me) {
+		}
+	}
+
+	if t != nil {
+		t.sp = 0
+	}
+}
+
+func unlock() {
+	if ncore <= 1 {
+		return
+	}
+	ticket[coreID()] = 0
+}
+`
@@ -0,0 +1,109 @@
+package pl
+
+import (
+	"shanhu.io/smlvm/arch"
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// selectCaseSize is the size of a case of a select for the runtime,
+// which is the channel, the address of the value, if it is a send, and
+// if the receive is ok.
+const selectCaseSize = 3 * arch.RegSize
+
+func buildSelectStmt(b *builder, stmt *tast.SelectStmt) {
+	label := b.labels.take()
+
+	var comms []*tast.SelectCase
+	var def *tast.SelectCase
+	for _, c := range stmt.Cases {
+		if c.Send == nil && c.Recv == nil {
+			def = c
+		} else {
+			comms = append(comms, c)
+		}
+	}
+
+	n := int32(len(comms))
+	base := codegen.Ref(codegen.Num(0))
+	if n > 0 {
+		cases := b.f.NewTemp(n*selectCaseSize, false, true)
+		var chans []int32
+		for i := int32(0); i < n; i++ {
+			chans = append(chans, i*selectCaseSize)
+		}
+		cases.(*codegen.Var).Ptrs = chans
+		base = b.newPtr()
+		b.b.Arith(base, nil, "&", cases)
+	}
+
+	elems := make([]*ref, n)
+	for i, c := range comms {
+		offset := int32(i) * selectCaseSize
+		var ch *ref
+		send := refFalse
+		if c.Send != nil {
+			ch = b.buildExpr(c.Send.Chan)
+			v := b.buildExpr(c.Send.Val)
+			elems[i] = b.newTemp(v.Type())
+			b.b.Assign(elems[i].IR(), v.IR())
+			send = refTrue
+		} else {
+			ch = b.buildExpr(c.Recv.Chan)
+			elems[i] = b.newTemp(chanType(ch).T)
+		}
+		b.b.Assign(elemAt(base, types.Uint, offset), ch.IR())
+		b.b.Assign(
+			elemAt(base, types.Uint, offset+4), refAddr(b, elems[i]),
+		)
+		b.b.Assign(elemAt(base, types.Bool, offset+8), send.IR())
+	}
+
+	index := b.newTempIR(types.Int)
+	blocking := refTrue
+	if def != nil {
+		blocking = refFalse
+	}
+	b.b.Call([]codegen.Ref{index}, b.rt.selectRun,
+		base, codegen.Num(uint32(n)), blocking.IR(),
+	)
+
+	after := b.f.NewBlock(b.b)
+	if pushSwitchBreak(b, after, label) {
+		defer b.breaks.pop()
+	}
+
+	buildCase := func(i int32, c *tast.SelectCase) {
+		isCase := b.newCond()
+		b.b.Arith(isCase, index, "==", codegen.Snum(i))
+		body := b.f.NewBlock(b.b)
+		next := b.f.NewBlock(body)
+		b.b.JumpIfNot(isCase, next)
+
+		b.b = body
+		if c.Recv != nil && c.Comm != nil {
+			v := elems[i]
+			if c.Recv.CommaOk {
+				offset := i*selectCaseSize + 9
+				ok := newRef(types.Bool, elemAt(base, types.Bool, offset))
+				v = appendRef(v, ok)
+			}
+			b.selected[c.Recv] = v
+			b.buildStmt(c.Comm)
+			delete(b.selected, c.Recv)
+		}
+		for _, s := range c.Stmts {
+			b.buildStmt(s)
+		}
+		b.b.Jump(after)
+		b.b = next
+	}
+	for i, c := range comms {
+		buildCase(int32(i), c)
+	}
+	if def != nil {
+		buildCase(-1, def)
+	}
+	b.b = after
+}
@@ -0,0 +1,183 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+func buildCallNew(b *builder, expr *ast.CallExpr, f tast.Expr) tast.Expr {
+	pos := expr.Lparen.Pos
+	if expr.Args.Len() != 1 {
+		b.Errorf(pos, "new() takes one argument")
+		return nil
+	}
+
+	arg := b.buildExpr(expr.Args.Exprs[0])
+	if arg == nil {
+		return nil
+	}
+	t, ok := arg.R().T.(*types.Type)
+	if !ok {
+		b.Errorf(pos, "new() takes a type as the argument")
+		return nil
+	}
+
+	args := tast.NewExprList()
+	args.Append(arg)
+	r := tast.NewRef(types.NewPointer(t.T))
+	return &tast.CallExpr{Func: f, Args: args, Ref: r}
+}
+
+// buildMakeSlice builds make([]T, n), which allocates the slice in the
+// heap, or make([]T, n, p), which makes a slice of the n elements at p.
+func buildMakeSlice(
+	b *builder, expr *ast.CallExpr, t *types.Slice, args *tast.ExprList,
+	f tast.Expr,
+) tast.Expr {
+	n := args.Len()
+	if n != 2 && n != 3 {
+		b.Errorf(expr.Lparen.Pos, "make() slice takes 2 or 3 arguments")
+		return nil
+	}
+
+	size := args.Exprs[1]
+	pos := ast.ExprPos(expr.Args.Exprs[1])
+	size = checkArrayIndex(b, size, pos)
+	if size == nil {
+		return nil
+	}
+
+	callArgs := tast.NewExprList()
+	callArgs.Append(args.Exprs[0])
+	callArgs.Append(size)
+	if n == 3 {
+		start := buildMakeStart(b, args.Exprs[2], expr.Args.Exprs[2], t)
+		if start == nil {
+			return nil
+		}
+		callArgs.Append(start)
+	}
+	return &tast.CallExpr{Func: f, Args: callArgs, Ref: tast.NewRef(t)}
+}
+
+func buildMakeStart(
+	b *builder, start tast.Expr, expr ast.Expr, t *types.Slice,
+) tast.Expr {
+	startType := start.R().T
+	pos := ast.ExprPos(expr)
+	if v, ok := types.NumConst(startType); ok {
+		return numCastUint(b, pos, v, start)
+	}
+	if !types.IsBasic(startType, types.Uint) {
+		pt := types.PointerOf(startType)
+		if pt == nil || !types.SameType(pt, t.T) {
+			b.Errorf(pos,
+				"make() takes an uint or a typed pointer as the 3rd arg",
+			)
+			return nil
+		}
+	}
+	return start
+}
+
+func buildAppendElem(
+	b *builder, expr ast.Expr, t types.T, pos *lexing.Pos,
+) tast.Expr {
+	v := b.buildExpr(expr)
+	if v == nil {
+		return nil
+	}
+
+	ref := v.R()
+	if !ref.IsSingle() {
+		b.CodeErrorf(pos, "pl.append.notSingle",
+			"appended element must be a single value")
+		return nil
+	}
+	if num, ok := types.NumConst(ref.T); ok && types.IsInteger(t) {
+		return numCast(b, pos, num, v, t)
+	}
+
+	ok, needCast := canAssign(b, pos, t, ref.T, "append()")
+	if !ok {
+		return nil
+	}
+	if needCast {
+		return tast.NewCast(v, t)
+	}
+	return v
+}
+
+func buildCallAppend(
+	b *builder, expr *ast.CallExpr, f tast.Expr,
+) tast.Expr {
+	pos := expr.Lparen.Pos
+	if expr.Args.Len() == 0 {
+		b.Errorf(pos, "append() takes at least one argument")
+		return nil
+	}
+
+	s := b.buildExpr(expr.Args.Exprs[0])
+	if s == nil {
+		return nil
+	}
+	t, ok := types.Underlying(s.R().T).(*types.Slice)
+	if !ok || !s.R().IsSingle() {
+		b.CodeErrorf(pos, "pl.append.notSlice",
+			"append() takes a slice as the 1st argument")
+		return nil
+	}
+
+	args := tast.NewExprList()
+	args.Append(s)
+	if expr.Dots != nil {
+		return buildCallAppendSlice(b, expr, f, args, t)
+	}
+	for _, e := range expr.Args.Exprs[1:] {
+		v := buildAppendElem(b, e, t.T, ast.ExprPos(e))
+		if v == nil {
+			return nil
+		}
+		args.Append(v)
+	}
+	return &tast.CallExpr{Func: f, Args: args, Ref: tast.NewRef(s.R().T)}
+}
+
+// buildCallAppendSlice builds an append() that appends the elements of a
+// slice, like "append(s, t...)".
+func buildCallAppendSlice(
+	b *builder, expr *ast.CallExpr, f tast.Expr,
+	args *tast.ExprList, t *types.Slice,
+) tast.Expr {
+	if expr.Args.Len() != 2 {
+		b.CodeErrorf(expr.Dots.Pos, "pl.append.dots",
+			"append() with ... takes exactly two arguments")
+		return nil
+	}
+
+	e := expr.Args.Exprs[1]
+	pos := ast.ExprPos(e)
+	v := b.buildExpr(e)
+	if v == nil {
+		return nil
+	}
+	ref := v.R()
+	if !ref.IsSingle() {
+		b.CodeErrorf(pos, "pl.append.notSingle",
+			"appended slice must be a single value")
+		return nil
+	}
+	ok, needCast := canAssign(b, pos, t, ref.T, "append()")
+	if !ok {
+		return nil
+	}
+	if needCast {
+		v = tast.NewCast(v, t)
+	}
+	args.Append(v)
+	return &tast.CallExpr{
+		Func: f, Args: args, Dots: true, Ref: tast.NewRef(args.Exprs[0].R().T),
+	}
+}
@@ -1,76 +1,233 @@
 package sempass
 
 import (
-	"bytes"
 	"encoding/binary"
+	"math"
 
+	"shanhu.io/smlvm/lexing"
 	"shanhu.io/smlvm/pl/ast"
 	"shanhu.io/smlvm/pl/tast"
 	"shanhu.io/smlvm/pl/types"
 )
 
+func buildArrayLitKey(b *builder, expr ast.Expr, n int32) (int32, bool) {
+	k := b.buildConstExpr(expr)
+	if k == nil {
+		return 0, false
+	}
+
+	pos := ast.ExprPos(expr)
+	var v int64
+	if num, ok := types.NumConst(k.R().T); ok {
+		v = num
+	} else if ct, ok := k.R().T.(*types.Const); ok &&
+		types.IsInteger(ct.Type) {
+		v = ct.Value.(int64)
+	} else {
+		b.CodeErrorf(pos, "pl.arrayLit.illegalIndex",
+			"array literal index is not an integer constant")
+		return 0, false
+	}
+
+	if v < 0 {
+		b.CodeErrorf(pos, "pl.arrayLit.illegalIndex",
+			"array literal index is negative: %d", v)
+		return 0, false
+	} else if !types.InRange(v, types.Int) || (n >= 0 && v >= int64(n)) {
+		b.CodeErrorf(pos, "pl.arrayLit.outOfRange",
+			"array literal index %d out of range", v)
+		return 0, false
+	}
+	return int32(v), true
+}
+
+func buildArrayLitValue(b *builder, expr ast.Expr, t types.T) tast.Expr {
+	v := buildElem(b, expr, t)
+	if v == nil {
+		return nil
+	}
+	return checkArrayLitValue(b, ast.ExprPos(expr), v, t)
+}
+
+// checkArrayLitValue checks if the built value v can be an element of
+// type t in an array literal, and casts it when needed.
+func checkArrayLitValue(
+	b *builder, pos *lexing.Pos, v tast.Expr, t types.T,
+) tast.Expr {
+	ref := v.R()
+	if !ref.IsSingle() {
+		b.CodeErrorf(pos, "pl.arrayLit.notSingle",
+			"array literal element must be a single value")
+		return nil
+	}
+	if num, ok := types.NumConst(ref.T); ok && types.IsInteger(t) {
+		if !types.InRange(num, t) {
+			b.CodeErrorf(pos, "pl.arrayLit.outOfRange",
+				"constant out of range of %s", t)
+			return nil
+		}
+	}
+
+	ok, needCast := canAssign(b, pos, t, ref.T, "array literal")
+	if !ok {
+		return nil
+	}
+	if needCast {
+		return tast.NewCast(v, t)
+	}
+	return v
+}
+
+// constElem encodes a constant element of a literal of basic type t. It
+// returns false when the element cannot be stored in the data section.
+func constElem(t types.T, v tast.Expr) ([]byte, bool) {
+	if c, ok := v.(*tast.Cast); ok {
+		v = c.From
+	}
+	if _, ok := v.(*tast.Const); !ok {
+		return nil, false
+	}
+	ct, ok := v.R().T.(*types.Const)
+	if !ok {
+		return nil, false
+	}
+
+	var f float64
+	var n int64
+	switch x := ct.Value.(type) {
+	case int64:
+		f, n = float64(x), x
+	case float64:
+		f = x
+	default:
+		return nil, false
+	}
+
+	switch t {
+	case types.Int, types.Uint:
+		bs := make([]byte, 4)
+		binary.LittleEndian.PutUint32(bs, uint32(n))
+		return bs, true
+	case types.Int8, types.Uint8:
+		return []byte{byte(n)}, true
+	case types.Float32:
+		bs := make([]byte, 4)
+		binary.LittleEndian.PutUint32(bs, math.Float32bits(float32(f)))
+		return bs, true
+	}
+	return nil, false
+}
+
+func constArrayLit(t types.T, lit *tast.ArrayLit) tast.Expr {
+	var et types.T
+	switch t := types.Underlying(t).(type) {
+	case *types.Array:
+		et = t.T
+	case *types.Slice:
+		et = t.T
+	}
+
+	switch et {
+	case types.Int, types.Uint, types.Int8, types.Uint8, types.Float32:
+	default:
+		return nil
+	}
+
+	size := et.Size()
+	bs := make([]byte, size*lit.Len)
+	for i, e := range lit.Exprs {
+		v, ok := constElem(et, e)
+		if !ok {
+			return nil
+		}
+		copy(bs[lit.Keys[i]*size:], v)
+	}
+	return &tast.Const{Ref: tast.NewConstRef(t, bs)}
+}
+
 func buildArrayLit(b *builder, lit *ast.ArrayLiteral) tast.Expr {
 	hold := b.lhsSwap(false)
 	defer b.lhsRestore(hold)
 
-	if lit.Type.Len != nil {
-		b.CodeErrorf(
-			ast.ExprPos(lit), "pl.notYetSupported",
-			"array literal with length not supported yet",
-		)
-		return nil
+	if lit.Type.Dots != nil {
+		// the length is counted from the elements
+		et := b.buildType(lit.Type.Type)
+		if et == nil {
+			return nil
+		}
+		return buildArrayLitOf(b, &types.Array{T: et, N: -1}, lit.Exprs)
 	}
 
-	buf := new(bytes.Buffer)
-	t := buildType(b, lit.Type.Type)
+	t := b.buildType(lit.Type)
 	if t == nil {
 		return nil
 	}
+	return buildArrayLitOf(b, t, lit.Exprs)
+}
 
-	if !types.IsInteger(t) {
-		pos := ast.ExprPos(lit.Type.Type)
-		b.CodeErrorf(pos, "pl.arrayLit.notInteger",
-			"array literal now only support integer array")
-		return nil
+// buildArrayLitOf builds an array or slice literal of type t, which can
+// be a named type.
+func buildArrayLitOf(b *builder, t types.T, exprs *ast.ExprList) tast.Expr {
+	var et types.T
+	n := int32(-1)
+	switch t := types.Underlying(t).(type) {
+	case *types.Array:
+		et, n = t.T, t.N
+	case *types.Slice:
+		et = t.T
+	default:
+		panic("not an array or slice")
 	}
-	bt := t.(types.Basic)
 
-	if lit.Exprs != nil {
-		for _, expr := range lit.Exprs.Exprs {
-			n := b.buildConstExpr(expr)
-			if n == nil {
+	ret := &tast.ArrayLit{Ref: tast.NewRef(t)}
+	if exprs != nil {
+		keys := make(map[int32]bool)
+		var next int32
+		for _, expr := range exprs.Exprs {
+			pos := ast.ExprPos(expr)
+			key := next
+			if kv, ok := expr.(*ast.KeyValueExpr); ok {
+				k, ok := buildArrayLitKey(b, kv.Key, n)
+				if !ok {
+					return nil
+				}
+				key, expr = k, kv.Value
+			} else if n >= 0 && key >= n {
+				b.CodeErrorf(pos, "pl.arrayLit.outOfRange",
+					"array literal index %d out of range", key)
 				return nil
 			}
-			ntype := n.R().T
-			if _, ok := ntype.(*types.Const); !ok {
-				b.CodeErrorf(ast.ExprPos(expr), "pl.arrayLit.notConstant",
-					"array literal not a constant")
+
+			if keys[key] {
+				b.CodeErrorf(pos, "pl.arrayLit.duplicateIndex",
+					"duplicate index %d in array literal", key)
 				return nil
 			}
+			keys[key] = true
 
-			if v, ok := types.NumConst(ntype); ok {
-				if !types.InRange(v, t) {
-					b.CodeErrorf(
-						ast.ExprPos(expr), "pl.arrayLit.outOfRange",
-						"constant out of range of %s", t,
-					)
-					return nil
-				}
+			v := buildArrayLitValue(b, expr, et)
+			if v == nil {
+				return nil
+			}
+			ret.Keys = append(ret.Keys, key)
+			ret.Exprs = append(ret.Exprs, v)
 
-				switch bt {
-				case types.Int, types.Uint:
-					var bs [4]byte
-					binary.LittleEndian.PutUint32(bs[:], uint32(v))
-					buf.Write(bs[:])
-				case types.Int8, types.Uint8:
-					buf.Write([]byte{byte(v)})
-				default:
-					panic("not integer")
-				}
+			next = key + 1
+			if next > ret.Len {
+				ret.Len = next
 			}
 		}
 	}
+	if n >= 0 {
+		ret.Len = n
+	} else if at, ok := t.(*types.Array); ok {
+		at.N = ret.Len // a [...] array
+	}
 
-	ref := tast.NewConstRef(&types.Slice{T: bt}, buf.Bytes())
-	return &tast.Const{Ref: ref}
+	if n < 0 || len(ret.Exprs) > 0 {
+		if c := constArrayLit(t, ret); c != nil {
+			return c
+		}
+	}
+	return ret
 }
@@ -21,8 +21,7 @@ func assign(b *builder, dest, src tast.Expr, op *lexing.Token) tast.Stmt {
 
 	// check if all addressable
 	for i := 0; i < ndest; i++ {
-		r := destRef.At(i)
-		if !r.Addressable {
+		if !assignable(dest, i) {
 			b.CodeErrorf(
 				op.Pos, "pl.cannotAssign.notAddressable",
 				"assigning to non-addressable",
@@ -40,6 +39,9 @@ func assign(b *builder, dest, src tast.Expr, op *lexing.Token) tast.Stmt {
 	if res.needCast {
 		src = tast.NewMultiCast(src, destRef, res.castMask)
 	}
+	if ident, ok := dest.(*tast.Ident); ok {
+		litBound(b, ident.Sym, src)
+	}
 	return &tast.AssignStmt{Left: dest, Op: op, Right: src}
 }
 
@@ -58,7 +60,7 @@ func opAssign(b *builder, dest, src tast.Expr, op *lexing.Token) tast.Stmt {
 		b.CodeErrorf(op.Pos, "pl.cannotAssign.notSingle",
 			"cannot assign %s %s %s", destRef, op.Lit, srcRef)
 		return nil
-	} else if !destRef.Addressable {
+	} else if !assignable(dest, 0) {
 		b.CodeErrorf(op.Pos, "pl.cannotAssign.notAddressable",
 			"assign to non-addressable")
 		return nil
@@ -91,12 +93,19 @@ func opAssign(b *builder, dest, src tast.Expr, op *lexing.Token) tast.Stmt {
 		}
 		srcRef = src.R()
 		srcType = destType
+	} else if c, ok := srcType.(*types.Const); ok {
+		srcType = c.Type
 	}
 
 	if ok, t := types.SameBasic(destType, srcType); ok {
 		switch t {
 		case types.Int, types.Int8, types.Uint, types.Uint8:
 			return &tast.AssignStmt{Left: dest, Op: op, Right: src}
+		case types.Float32:
+			switch opLit {
+			case "+", "-", "*", "/":
+				return &tast.AssignStmt{Left: dest, Op: op, Right: src}
+			}
 		}
 	}
 
@@ -116,6 +125,7 @@ func buildAssignStmt(b *builder, stmt *ast.AssignStmt) tast.Stmt {
 	if right == nil {
 		return nil
 	}
+	right = commaOk(right, stmt.Left.Len())
 
 	if stmt.Assign.Lit == "=" {
 		return assign(b, left, right, stmt.Assign)
@@ -14,6 +14,7 @@ func BuildBareFunc(scope *syms.Scope, stmts []ast.Stmt) (
 	b := makeBuilder("_", scope)
 	b.scope.Push()
 	defer scopePopAndCheck(b)
+	checkLabels(b, stmts)
 	ret := buildStmts(b, stmts)
 	errs := b.Errs()
 	if errs != nil {
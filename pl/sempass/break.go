@@ -7,10 +7,10 @@ import (
 
 func buildBreakStmt(b *builder, s *ast.BreakStmt) tast.Stmt {
 	if s.Label != nil {
-		b.Errorf(s.Label.Pos, "break with label not implemented")
-		return nil
+		// checked by checkLabels
+		return &tast.BreakStmt{Label: s.Label.Lit}
 	}
-	if b.nloop == 0 {
+	if b.nloop == 0 && b.nswitch == 0 {
 		b.CodeErrorf(s.Kw.Pos, "pl.breakStmt.notInLoop",
 			"break is not in a for block")
 		return nil
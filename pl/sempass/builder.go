@@ -23,13 +23,26 @@ type builder struct {
 	// file level dependency, for checking circular dependencies.
 	deps deps
 
+	// the instances of generics that the package uses, and the nesting
+	// depth of the instance being built.
+	insts     *instances
+	instDepth int
+
 	nloop    int
+	nswitch  int  // switches and selects that an unlabeled break breaks
+	golike   bool // if an unlabeled break breaks switches and selects
 	this     *tast.Ref
 	thisType *types.Pointer
 
 	retType  []types.T
 	retNamed bool
 
+	// the function being built, and the escape analysis of the function
+	// literals in it.
+	fn      *funcScope
+	escapes *escapes
+	callee  bool // if building the function of a call expression
+
 	// if the parsing is in left hand side.
 	// when in left hand side, referencing a variable does not count.
 	lhs bool
@@ -18,19 +18,43 @@ func buildCallLen(b *builder, expr *ast.CallExpr, f tast.Expr) tast.Expr {
 		b.Errorf(expr.Lparen.Pos, "len() takes one argument")
 		return nil
 	}
+	if c := constLen(args); c != nil {
+		return c
+	}
 
 	t := ref.T
-	switch t.(type) {
+	switch types.Underlying(t).(type) {
 	case *types.Slice:
 		return &tast.CallExpr{Func: f, Args: args, Ref: tast.NewRef(types.Int)}
 	case *types.Array:
 		return &tast.CallExpr{Func: f, Args: args, Ref: tast.NewRef(types.Int)}
+	case *types.Map:
+		return &tast.CallExpr{Func: f, Args: args, Ref: tast.NewRef(types.Int)}
+	case *types.Chan:
+		return &tast.CallExpr{Func: f, Args: args, Ref: tast.NewRef(types.Int)}
 	}
 
 	b.Errorf(expr.Lparen.Pos, "len() does not take %s", t)
 	return nil
 }
 
+func buildConstCallLen(b *builder, expr *ast.CallExpr) tast.Expr {
+	args := buildConstExprList(b, expr.Args)
+	if args == nil {
+		return nil
+	}
+	if !args.R().IsSingle() {
+		b.Errorf(expr.Lparen.Pos, "len() takes one argument")
+		return nil
+	}
+	if c := constLen(args); c != nil {
+		return c
+	}
+	b.CodeErrorf(expr.Lparen.Pos, "pl.expectConstExpr",
+		"len() of %s is not a const", args)
+	return nil
+}
+
 func buildCallMake(b *builder, expr *ast.CallExpr, f tast.Expr) tast.Expr {
 	args := buildExprList(b, expr.Args)
 	if args == nil {
@@ -55,59 +79,46 @@ func buildCallMake(b *builder, expr *ast.CallExpr, f tast.Expr) tast.Expr {
 		b.Errorf(expr.Lparen.Pos, "make() takes a type as the 1st argument")
 		return nil
 	}
-	switch st := t.T.(type) {
+	switch st := types.Underlying(t.T).(type) {
 	case *types.Slice:
-		if n != 3 {
-			b.Errorf(expr.Lparen.Pos, "make() slice takes 3 arguments")
-			return nil
-		}
-
-		size := argsList.Exprs[1]
-		pos := ast.ExprPos(expr.Args.Exprs[1])
-		size = checkArrayIndex(b, size, pos)
-		if size == nil {
-			return nil
-		}
-
-		start := argsList.Exprs[2]
-		startType := start.R().T
-		startPos := ast.ExprPos(expr.Args.Exprs[2])
-		if v, ok := types.NumConst(startType); ok {
-			start = numCastUint(b, startPos, v, start)
-			if start == nil {
-				return nil
-			}
-		} else if !types.IsBasic(startType, types.Uint) {
-			pt := types.PointerOf(startType)
-			if pt == nil || !types.SameType(pt, st.T) {
-				b.Errorf(startPos,
-					"make() takes an uint or a typed pointer as the 3rd arg",
-				)
-				return nil
-			}
-		}
-
-		callArgs := tast.NewExprList()
-		callArgs.Append(arg0)
-		callArgs.Append(size)
-		callArgs.Append(start)
-
-		r := tast.NewRef(st)
-		return &tast.CallExpr{Func: f, Args: callArgs, Ref: r}
+		return buildMakeSlice(b, expr, st, argsList, f)
+	case *types.Map:
+		return buildMakeMap(b, expr.Lparen.Pos, st, argsList, f)
+	case *types.Chan:
+		return buildMakeChan(b, expr.Lparen.Pos, st, argsList, f)
 	}
 
 	b.Errorf(expr.Lparen.Pos, "cannot make() type %s", t.T)
 	return nil
 }
 
+// canUseDots checks if the last argument of a call to a function of
+// type t can be a slice followed by "...".
+func canUseDots(t types.T) bool {
+	switch t := types.Underlying(t).(type) {
+	case *types.Func:
+		return t.IsVariadic
+	case *types.BuiltInFunc:
+		return t.Name == "append"
+	}
+	return false
+}
+
 func buildCallExpr(b *builder, expr *ast.CallExpr) tast.Expr {
 	hold := b.lhsSwap(false)
 	defer b.lhsRestore(hold)
 
+	if g := queryGeneric(b, expr.Func); g != nil && g.f != nil {
+		return buildGenericCall(b, expr, g)
+	}
+
+	_, b.callee = expr.Func.(*ast.Operand)
 	f := b.buildExpr(expr.Func)
+	b.callee = false
 	if f == nil {
 		return nil
 	}
+	litCalled(b, f)
 
 	pos := ast.ExprPos(expr.Func)
 	fref := f.R()
@@ -117,6 +128,12 @@ func buildCallExpr(b *builder, expr *ast.CallExpr) tast.Expr {
 		return nil
 	}
 
+	if expr.Dots != nil && !canUseDots(fref.T) {
+		b.CodeErrorf(expr.Dots.Pos, "pl.call.notVariadic",
+			"cannot use ... in call to non-variadic %s", fref)
+		return nil
+	}
+
 	// expr.Func is a Type
 
 	if t, ok := fref.T.(*types.Type); ok {
@@ -130,25 +147,46 @@ func buildCallExpr(b *builder, expr *ast.CallExpr) tast.Expr {
 			return buildCallLen(b, expr, f)
 		case "make":
 			return buildCallMake(b, expr, f)
+		case "delete":
+			return buildCallDelete(b, expr, f)
+		case "new":
+			return buildCallNew(b, expr, f)
+		case "append":
+			return buildCallAppend(b, expr, f)
+		case "close":
+			return buildCallClose(b, expr, f)
 		}
 		b.Errorf(pos, "builtin %s() not implemented", builtin.Name)
 		return nil
 	}
 
-	funcType, ok := fref.T.(*types.Func)
+	funcType, ok := types.Underlying(fref.T).(*types.Func)
 	if !ok {
 		b.Errorf(pos, "function call on non-callable: %s", fref)
 		return nil
 	}
 
-	args := buildExprList(b, expr.Args)
+	var args tast.Expr
+	if funcType.IsVariadic && expr.Dots == nil {
+		args = buildVariadicArgs(b, expr, funcType)
+	} else {
+		args = buildExprList(b, expr.Args)
+	}
 	if args == nil {
 		return nil
 	}
+	return buildFuncCall(b, expr, f, funcType, args)
+}
 
+// buildFuncCall builds a call to function f of type funcType with the
+// built arguments args.
+func buildFuncCall(
+	b *builder, expr *ast.CallExpr, f tast.Expr, funcType *types.Func,
+	args tast.Expr,
+) tast.Expr {
 	argsRef := args.R()
 	nargs := argsRef.Len()
-	pos = ast.ExprPos(expr)
+	pos := ast.ExprPos(expr)
 	if nargs != len(funcType.Args) {
 		b.CodeErrorf(pos, "pl.argsMismatch.count",
 			"argument count mismatch, expects (%s), got (%s)",
@@ -48,7 +48,13 @@ func canAssigns(
 func canAssign(
 	b *builder, p *lexing.Pos, left, right types.T, in string,
 ) (ok bool, needCast bool) {
-	if i, ok := left.(*types.Interface); ok {
+	if i, ok := types.Underlying(left).(*types.Interface); ok {
+		if types.IsNil(right) {
+			return true, true
+		}
+		if types.SameType(left, right) {
+			return true, false
+		}
 		if !assignInterface(b, p, i, right, in) {
 			return false, false
 		}
@@ -68,15 +74,24 @@ func assignInterface(
 	in string,
 ) bool {
 	flag := true
-	var syms *syms.Table
+	var query func(name string) *syms.Symbol
 	if t, ok := types.PointerOf(right).(*types.Struct); ok {
-		syms = t.Syms
-	} else if t, ok := right.(*types.Interface); ok {
-		syms = t.Syms
+		query = func(name string) *syms.Symbol {
+			m, _ := findMember(t, name)
+			if m == nil {
+				return nil
+			}
+			return m.sym
+		}
+	} else if t, ok := types.PointerOf(right).(*types.Named); ok {
+		query = t.Syms.Query
+	} else if t, ok := types.Underlying(right).(*types.Interface); ok {
+		query = t.Syms.Query
 	} else {
 		b.CodeErrorf(p, "pl.cannotAssign.interface",
 			"cannot use %s as interface %s in %s, "+
-				"not a struct pointer or interface",
+				"not a pointer to a struct or named type, "+
+				"or an interface",
 			right, i, in)
 		return false
 	}
@@ -90,7 +105,7 @@ func assignInterface(
 
 	funcs := i.Syms.List()
 	for _, f := range funcs {
-		sym := syms.Query(f.Name())
+		sym := query(f.Name())
 		if sym == nil {
 			errorf("function %s not implemented in %s", f.Name(), right)
 			continue
@@ -39,21 +39,24 @@ func buildCast(b *builder, expr *ast.CallExpr, t types.T) tast.Expr {
 
 	srcType := ref.T
 	if c, ok := srcType.(*types.Const); ok {
-		if v, ok := types.NumConst(srcType); ok && types.IsInteger(t) {
+		if v, ok := types.NumConst(srcType); ok && types.IsNumeric(t) {
 			return numCast(b, pos, v, args, t)
 		}
 		srcType = c.Type // using the underlying type
 	}
 
-	if types.IsInteger(t) && types.IsInteger(srcType) {
+	if types.IsNumeric(t) && types.IsNumeric(srcType) {
 		return tast.NewCast(args, t)
 	}
 	if regSizeCastable(t, srcType) {
 		return tast.NewCast(args, t)
 	}
-	if _, ok := t.(*types.Interface); ok {
+	if _, ok := types.Underlying(t).(*types.Interface); ok {
 		return tast.NewCast(args, t)
 	}
+	if types.SameType(types.Underlying(t), types.Underlying(srcType)) {
+		return tast.NewCast(args, t) // between a named type and its base
+	}
 
 	b.Errorf(pos, "cannot convert from %s to %s", srcType, t)
 	return nil
@@ -0,0 +1,131 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+func buildChanType(b *builder, expr *ast.ChanTypeExpr) types.T {
+	t := buildType(b, expr.Elem)
+	if t == nil {
+		return nil
+	}
+	return &types.Chan{T: t}
+}
+
+// chanOf returns the channel type of e, or reports an error and returns
+// nil when e is not a single channel.
+func chanOf(b *builder, pos *lexing.Pos, e tast.Expr, op string) *types.Chan {
+	ref := e.R()
+	t, ok := types.Underlying(ref.T).(*types.Chan)
+	if !ok || !ref.IsSingle() {
+		b.CodeErrorf(pos, "pl.chan.notChan", "%s on non-channel %s", op, ref)
+		return nil
+	}
+	return t
+}
+
+func buildChanRecv(b *builder, opTok *lexing.Token, ch tast.Expr) tast.Expr {
+	t := chanOf(b, opTok.Pos, ch, "receive")
+	if t == nil {
+		return nil
+	}
+	return &tast.ChanRecv{Chan: ch, Ref: tast.NewRef(t.T)}
+}
+
+// buildChanVal builds a value that is sent into a channel of type t.
+func buildChanVal(b *builder, expr ast.Expr, t types.T) tast.Expr {
+	v := b.buildExpr(expr)
+	if v == nil {
+		return nil
+	}
+
+	pos := ast.ExprPos(expr)
+	ref := v.R()
+	if !ref.IsSingle() {
+		b.CodeErrorf(pos, "pl.chan.notSingle",
+			"sending %s, must be a single value", ref)
+		return nil
+	}
+	if num, ok := types.NumConst(ref.T); ok && types.IsInteger(t) {
+		return numCast(b, pos, num, v, t)
+	}
+
+	ok, needCast := canAssign(b, pos, t, ref.T, "send")
+	if !ok {
+		return nil
+	}
+	if needCast {
+		return tast.NewCast(v, t)
+	}
+	return v
+}
+
+func buildSendStmt(b *builder, stmt *ast.SendStmt) *tast.SendStmt {
+	ch := b.buildExpr(stmt.Chan)
+	if ch == nil {
+		return nil
+	}
+	t := chanOf(b, stmt.Arrow.Pos, ch, "send")
+	if t == nil {
+		return nil
+	}
+	v := buildChanVal(b, stmt.Val, t.T)
+	if v == nil {
+		return nil
+	}
+	return &tast.SendStmt{Chan: ch, Val: v}
+}
+
+func buildGoStmt(b *builder, stmt *ast.GoStmt) tast.Stmt {
+	call := buildKwCall(b, stmt.Kw, stmt.Call)
+	if call == nil {
+		return nil
+	}
+	litGone(b, call.Func)
+	return &tast.GoStmt{Kw: stmt.Kw, Call: call}
+}
+
+func buildCallClose(b *builder, expr *ast.CallExpr, f tast.Expr) tast.Expr {
+	pos := expr.Lparen.Pos
+	if expr.Args.Len() != 1 {
+		b.Errorf(pos, "close() takes one argument")
+		return nil
+	}
+	ch := b.buildExpr(expr.Args.Exprs[0])
+	if ch == nil {
+		return nil
+	}
+	if chanOf(b, pos, ch, "close") == nil {
+		return nil
+	}
+
+	args := tast.NewExprList()
+	args.Append(ch)
+	return &tast.CallExpr{Func: f, Args: args, Ref: tast.Void}
+}
+
+// buildMakeChan builds make(chan T) or make(chan T, n), where n is the
+// size of the buffer.
+func buildMakeChan(
+	b *builder, pos *lexing.Pos, t *types.Chan, args *tast.ExprList,
+	f tast.Expr,
+) tast.Expr {
+	n := args.Len()
+	if n > 2 {
+		b.Errorf(pos, "make() channel takes at most 2 arguments")
+		return nil
+	}
+	callArgs := tast.NewExprList()
+	callArgs.Append(args.Exprs[0])
+	if n == 2 {
+		size := checkArrayIndex(b, args.Exprs[1], pos)
+		if size == nil {
+			return nil
+		}
+		callArgs.Append(size)
+	}
+	return &tast.CallExpr{Func: f, Args: callArgs, Ref: tast.NewRef(t)}
+}
@@ -9,7 +9,7 @@ import (
 func numCast(
 	b *builder, pos *lexing.Pos, v int64, from tast.Expr, to types.T,
 ) tast.Expr {
-	if types.IsInteger(to) && types.InRange(v, to) {
+	if types.IsNumeric(to) && types.InRange(v, to) {
 		return tast.NewCast(from, to)
 	}
 	b.CodeErrorf(
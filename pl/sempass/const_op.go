@@ -36,7 +36,11 @@ func unaryOpConst(b *builder, opTok *lexing.Token, B tast.Expr) tast.Expr {
 		return nil
 	}
 	t := ct.Type
-	v = ct.Value.(int64)
+	if _, ok := types.StringConst(ct); ok {
+		b.CodeErrorf(opTok.Pos, "pl.invalidOp",
+			"invalid operation on string const: %q on %s", op, B)
+		return nil
+	}
 	if types.IsBasic(t, types.Bool) {
 		// TODO(yumuzi234): add const boolean
 		b.CodeErrorf(opTok.Pos, "pl.notYetSupported",
@@ -44,10 +48,18 @@ func unaryOpConst(b *builder, opTok *lexing.Token, B tast.Expr) tast.Expr {
 		return nil
 	}
 	if types.IsBasic(t, types.Float32) {
-		b.CodeErrorf(opTok.Pos, "pl.notYetSupported",
-			"float is not supported yet")
+		switch op {
+		case "+":
+			return B
+		case "-":
+			f := ct.Value.(float64)
+			return tast.NewConst(types.NewConstFloat(-f))
+		}
+		b.CodeErrorf(opTok.Pos, "pl.invalidOp",
+			"invalid operation on float const: %q on %s", op, B)
 		return nil
 	}
+	v = ct.Value.(int64)
 	if types.IsInteger(t) {
 		switch op {
 		case "+":
@@ -90,10 +102,31 @@ func binaryOpConst(b *builder, opTok *lexing.Token, A, B tast.Expr) tast.Expr {
 		return nil
 	}
 
+	sa, oksa := types.StringConst(ca)
+	sb, oksb := types.StringConst(cb)
+	if oksa && oksb {
+		return binaryOpConstString(b, opTok, sa, sb, false)
+	} else if oksa || oksb {
+		b.CodeErrorf(
+			opTok.Pos, "pl.invalidOp.typeMismatch",
+			"cannot %s type %s, and type %s, type mismatch",
+			op, ca.Type, cb.Type)
+		return nil
+	}
+
+	if types.IsBasic(ca.Type, types.Float32) ||
+		types.IsBasic(cb.Type, types.Float32) {
+		return binaryOpConstFloat(b, opTok, A, B, ca, cb)
+	}
+
 	va, oka := types.NumConst(atyp)
 	vb, okb := types.NumConst(btyp)
 	if oka && okb {
-		return constIntOp(b, opTok, A, B, va, vb, ca.Type)
+		t := ca.Type
+		if cb.Type.(types.Number).Char {
+			t = cb.Type // a char stays a char
+		}
+		return constIntOp(b, opTok, A, B, va, vb, t)
 	}
 	var t types.T
 	if oka || okb {
@@ -142,6 +175,28 @@ func binaryOpConst(b *builder, opTok *lexing.Token, A, B tast.Expr) tast.Expr {
 	return constIntOp(b, opTok, A, B, va, vb, t)
 }
 
+func constFloatValue(c *types.Const) (float64, bool) {
+	if v, ok := types.NumConst(c); ok {
+		return float64(v), true
+	}
+	return types.FloatConst(c)
+}
+
+func binaryOpConstFloat(
+	b *builder, opTok *lexing.Token, A, B tast.Expr, ca, cb *types.Const,
+) tast.Expr {
+	va, oka := constFloatValue(ca)
+	vb, okb := constFloatValue(cb)
+	if !(oka && okb) {
+		b.CodeErrorf(
+			opTok.Pos, "pl.invalidOp.typeMismatch",
+			"cannot %s type %s, and type %s, type mismatch",
+			opTok.Lit, ca.Type, cb.Type)
+		return nil
+	}
+	return constFloatOp(b, opTok, A, B, va, vb)
+}
+
 // TODO(yumuzi234): after added const bool, remove inputs of va, ab
 func constIntOp(b *builder, opTok *lexing.Token, A, B tast.Expr,
 	va, vb int64, t types.T) tast.Expr {
@@ -155,6 +210,9 @@ func constIntOp(b *builder, opTok *lexing.Token, A, B tast.Expr,
 			}
 			return tast.NewConst(ref)
 		}
+		if n, ok := t.(types.Number); ok {
+			return tast.NewConst(&types.Const{Value: v, Type: n})
+		}
 		return tast.NewConst(types.NewNumber(v))
 	}
 	op := opTok.Lit
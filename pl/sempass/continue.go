@@ -7,8 +7,8 @@ import (
 
 func buildContinueStmt(b *builder, s *ast.ContinueStmt) tast.Stmt {
 	if s.Label != nil {
-		b.Errorf(s.Label.Pos, "continue with label not implemented")
-		return nil
+		// checked by checkLabels
+		return &tast.ContinueStmt{Label: s.Label.Lit}
 	}
 	if b.nloop == 0 {
 		b.CodeErrorf(s.Kw.Pos, "pl.continueStmt.notInLoop",
@@ -22,6 +22,9 @@ func declareVar(
 		return nil
 	}
 	s.Used = used
+	if b.fn != nil {
+		b.fn.vars[s] = true
+	}
 	return s
 }
 
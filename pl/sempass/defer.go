@@ -0,0 +1,51 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// buildKwCall builds the function call after a defer or a go keyword.
+// The error codes are prefixed with the keyword.
+func buildKwCall(b *builder, kw *lexing.Token, e ast.Expr) *tast.CallExpr {
+	pos := kw.Pos
+	code := "pl." + kw.Lit
+	if b.fn == nil {
+		b.CodeErrorf(pos, code+".notInFunc",
+			"%s must be in a function", kw.Lit)
+		return nil
+	}
+
+	if _, ok := e.(*ast.CallExpr); !ok {
+		b.CodeErrorf(pos, code+".notCall",
+			"expression in %s must be a function call", kw.Lit)
+		return nil
+	}
+	expr := b.buildExpr(e)
+	if expr == nil {
+		return nil
+	}
+	call, ok := expr.(*tast.CallExpr)
+	if !ok {
+		b.CodeErrorf(pos, code+".notCall",
+			"expression in %s must be a function call", kw.Lit)
+		return nil
+	}
+	if _, ok := call.Func.R().T.(*types.BuiltInFunc); ok {
+		b.CodeErrorf(pos, code+".builtin",
+			"cannot %s a call of a builtin function", kw.Lit)
+		return nil
+	}
+	return call
+}
+
+func buildDeferStmt(b *builder, stmt *ast.DeferStmt) tast.Stmt {
+	call := buildKwCall(b, stmt.Kw, stmt.Call)
+	if call == nil {
+		return nil
+	}
+	b.fn.hasDefer = true
+	return &tast.DeferStmt{Kw: stmt.Kw, Call: call}
+}
@@ -25,10 +25,13 @@ func allocPrepare(
 			return nil
 		}
 		if v, ok := types.NumConst(t); ok {
-			e = numCastInt(b, tok.Pos, v, e)
+			n := t.(*types.Const).Type.(types.Number)
+			e = numCast(b, tok.Pos, v, e, types.DefaultType(n))
 			if e == nil {
 				return nil
 			}
+		} else if c, ok := t.(*types.Const); ok {
+			e = tast.NewCast(e, c.Type) // typed const
 		}
 		if !types.IsAllocable(t) {
 			b.CodeErrorf(tok.Pos, "pl.cannotAlloc",
@@ -72,6 +75,9 @@ func define(
 		}
 		ret = append(ret, s)
 	}
+	if len(ret) == 1 {
+		litBound(b, ret[0], expr)
+	}
 
 	return &tast.Define{Left: ret, Right: expr}
 }
@@ -99,6 +105,7 @@ func buildDefineStmt(b *builder, stmt *ast.DefineStmt) tast.Stmt {
 	if right == nil {
 		return nil
 	}
+	right = commaOk(right, stmt.Left.Len())
 
 	idents, err := buildIdentExprList(b, stmt.Left)
 	if err != nil {
@@ -0,0 +1,109 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+	"shanhu.io/smlvm/syms"
+)
+
+// embeddedStruct returns the struct of an embedded field type, which is
+// either a struct or a pointer to a struct. It returns nil when the
+// type cannot be embedded.
+func embeddedStruct(t types.T) *types.Struct {
+	if pt := types.PointerOf(t); pt != nil {
+		t = pt
+	}
+	s, _ := t.(*types.Struct)
+	return s
+}
+
+// embeddedName returns the field name of embedded type t.
+func embeddedName(b *builder, pos *lexing.Pos, t types.T) string {
+	s := embeddedStruct(t)
+	if s == nil {
+		b.CodeErrorf(pos, "pl.embed.notStruct",
+			"cannot embed %s, not a struct or a pointer to a struct", t)
+		return ""
+	}
+	return s.String()
+}
+
+// member is a member of a struct. When the member is promoted, path
+// saves the embedded fields to reach it, the outermost one first.
+type member struct {
+	path []*syms.Symbol
+	sym  *syms.Symbol
+}
+
+type embedLevel struct {
+	t    *types.Struct
+	path []*syms.Symbol
+}
+
+// findMember looks up a member of struct t by name. The members of
+// the embedded fields are searched level by level, and the shallowest
+// level wins. A member found more than once on that level is
+// ambiguous, and findMember returns nil and true.
+func findMember(t *types.Struct, name string) (*member, bool) {
+	visited := make(map[*types.Struct]bool)
+	level := []*embedLevel{{t: t}}
+	for len(level) > 0 {
+		var found *member
+		n := 0
+		var next []*embedLevel
+		for _, l := range level {
+			if sym := l.t.Syms.Query(name); sym != nil {
+				found = &member{path: l.path, sym: sym}
+				n++
+			}
+			next = appendEmbeds(next, l, visited)
+		}
+		if n > 1 {
+			return nil, true
+		}
+		if found != nil {
+			return found, false
+		}
+
+		for _, l := range level {
+			visited[l.t] = true
+		}
+		level = next
+	}
+	return nil, false
+}
+
+func appendEmbeds(
+	list []*embedLevel, l *embedLevel, visited map[*types.Struct]bool,
+) []*embedLevel {
+	for _, f := range l.t.Fields {
+		if !f.Embedded {
+			continue
+		}
+		s := embeddedStruct(f.T)
+		if visited[s] {
+			continue
+		}
+		path := make([]*syms.Symbol, len(l.path), len(l.path)+1)
+		copy(path, l.path)
+		path = append(path, l.t.Syms.Query(f.Name))
+		list = append(list, &embedLevel{t: s, path: path})
+	}
+	return list
+}
+
+// buildPromotion selects the embedded fields on path from obj, which
+// reaches the struct that declares a promoted member.
+func buildPromotion(
+	b *builder, obj tast.Expr, sub *lexing.Token, path []*syms.Symbol,
+) tast.Expr {
+	addressable := !isMapIndex(obj)
+	for _, f := range path {
+		b.refSym(f, sub.Pos)
+		r := tast.NewRef(f.ObjType.(types.T))
+		r.Addressable = addressable
+		obj = &tast.MemberExpr{Expr: obj, Sub: sub, Ref: r, Sym: f}
+	}
+	return obj
+}
@@ -18,12 +18,16 @@ func buildExpr(b *builder, expr ast.Expr) tast.Expr {
 		return buildExpr(b, expr.Expr)
 	case *ast.MemberExpr:
 		return buildMember(b, expr)
+	case *ast.TypeAssertExpr:
+		return buildTypeAssert(b, expr)
 	case *ast.OpExpr:
 		return buildOpExpr(b, expr)
 	case *ast.StarExpr:
 		return buildStarExpr(b, expr)
 	case *ast.IndexExpr:
 		return buildIndexExpr(b, expr)
+	case *ast.InstExpr:
+		return buildInstExpr(b, expr.Generic, expr.Types.Exprs)
 	case *ast.CallExpr:
 		return buildCallExpr(b, expr)
 	case *ast.ArrayTypeExpr:
@@ -32,7 +36,7 @@ func buildExpr(b *builder, expr ast.Expr) tast.Expr {
 			return nil
 		}
 		return tast.NewType(t)
-	case *ast.FuncTypeExpr:
+	case *ast.FuncTypeExpr, *ast.MapTypeExpr, *ast.ChanTypeExpr:
 		t := b.buildType(expr)
 		if t == nil {
 			return nil
@@ -42,6 +46,12 @@ func buildExpr(b *builder, expr ast.Expr) tast.Expr {
 		return buildExprList(b, expr)
 	case *ast.ArrayLiteral:
 		return buildArrayLit(b, expr)
+	case *ast.StructLiteral:
+		return buildStructLit(b, expr)
+	case *ast.FuncLiteral:
+		return buildFuncLit(b, expr)
+	case *ast.MapLiteral:
+		return buildMapLit(b, expr)
 	}
 
 	b.Errorf(ast.ExprPos(expr), "invalid or not implemented: %T", expr)
@@ -72,6 +82,9 @@ func buildConstExpr(b *builder, expr ast.Expr) tast.Expr {
 		if t, ok := fref.T.(*types.Type); ok {
 			return buildConstCast(b, expr, t.T)
 		}
+		if f, ok := fref.T.(*types.BuiltInFunc); ok && f.Name == "len" {
+			return buildConstCallLen(b, expr)
+		}
 	}
 	b.CodeErrorf(
 		ast.ExprPos(expr), "pl.expectConstExpr",
@@ -80,9 +93,15 @@ func buildConstExpr(b *builder, expr ast.Expr) tast.Expr {
 	return nil
 }
 
+// isRecv checks if expr is a receive from a channel.
+func isRecv(expr ast.Expr) bool {
+	op, ok := expr.(*ast.OpExpr)
+	return ok && op.A == nil && op.Op.Lit == "<-"
+}
+
 func buildExprStmt(b *builder, expr ast.Expr) tast.Stmt {
-	if e, ok := expr.(*ast.CallExpr); ok {
-		ret := buildExpr(b, e)
+	if _, ok := expr.(*ast.CallExpr); ok || isRecv(expr) {
+		ret := buildExpr(b, expr)
 		if ret == nil {
 			return nil
 		}
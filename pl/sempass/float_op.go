@@ -0,0 +1,78 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+func unaryOpFloat(b *builder, opTok *lexing.Token, B tast.Expr) tast.Expr {
+	op := opTok.Lit
+	switch op {
+	case "+":
+		return B
+	case "-":
+		t := B.R().T
+		return &tast.OpExpr{Op: opTok, B: B, Ref: tast.NewRef(t)}
+	}
+
+	b.CodeErrorf(opTok.Pos, "pl.invalidOp",
+		"invalid operation: %q on %s", op, B)
+	return nil
+}
+
+func binaryOpFloat(
+	b *builder, opTok *lexing.Token, A, B tast.Expr, t types.T,
+) tast.Expr {
+	op := opTok.Lit
+	switch op {
+	case "+", "-", "*", "/":
+		r := tast.NewRef(t)
+		return &tast.OpExpr{A: A, Op: opTok, B: B, Ref: r}
+	case "==", "!=", ">", "<", ">=", "<=":
+		r := tast.NewRef(types.Bool)
+		return &tast.OpExpr{A: A, Op: opTok, B: B, Ref: r}
+	}
+
+	b.CodeErrorf(opTok.Pos, "pl.invalidOp", "%q on floats", op)
+	return nil
+}
+
+func constFloatOp(
+	b *builder, opTok *lexing.Token, A, B tast.Expr, va, vb float64,
+) tast.Expr {
+	r := func(v float64) tast.Expr {
+		return tast.NewConst(types.NewConstFloat(v))
+	}
+
+	op := opTok.Lit
+	switch op {
+	case "+":
+		return r(va + vb)
+	case "-":
+		return r(va - vb)
+	case "*":
+		return r(va * vb)
+	case "/":
+		if vb == 0 {
+			b.CodeErrorf(opTok.Pos, "pl.divideByZero", "divide by zero")
+			return nil
+		}
+		return r(va / vb)
+	case "==", "!=", ">", "<", ">=", "<=":
+		// compared in runtime, as there is no const bool yet
+		if _, ok := types.NumConst(A.R().T); ok {
+			A = tast.NewCast(A, types.Float32)
+		}
+		if _, ok := types.NumConst(B.R().T); ok {
+			B = tast.NewCast(B, types.Float32)
+		}
+		return &tast.OpExpr{
+			A: A, Op: opTok, B: B,
+			Ref: tast.NewRef(types.Bool),
+		}
+	}
+
+	b.CodeErrorf(opTok.Pos, "pl.invalidOp", "%q on float consts", op)
+	return nil
+}
@@ -6,6 +6,10 @@ import (
 )
 
 func buildForStmt(b *builder, stmt *ast.ForStmt) tast.Stmt {
+	if stmt.Range != nil {
+		return buildRangeStmt(b, stmt)
+	}
+
 	b.scope.Push()
 	defer scopePopAndCheck(b)
 
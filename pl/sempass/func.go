@@ -12,7 +12,8 @@ type pkgFunc struct {
 	sym *syms.Symbol
 	f   *ast.Func
 
-	recv *pkgStruct
+	this    *types.Pointer // the receiver type of a method
+	members *syms.Table    // the members of the receiver
 }
 
 func declareFuncSym(b *builder, f *ast.Func, t types.T) *syms.Symbol {
@@ -103,6 +104,11 @@ func declareParas(
 }
 
 func buildFunc(b *builder, f *pkgFunc) *tast.Func {
+	ret := new(tast.Func)
+	b.fn = newFuncScope(nil, ret, nil)
+	b.escapes = newEscapes()
+	defer func() { b.fn, b.escapes = nil, nil }()
+
 	b.scope.Push()
 	defer scopePopAndCheck(b)
 
@@ -110,7 +116,6 @@ func buildFunc(b *builder, f *pkgFunc) *tast.Func {
 	b.retNamed = f.f.NamedRet()
 	b.retType = t.RetTypes
 
-	ret := new(tast.Func)
 	ret.Sym = f.sym
 
 	if b.this != nil {
@@ -138,13 +143,17 @@ func buildFunc(b *builder, f *pkgFunc) *tast.Func {
 		ret.NamedRets = declareParas(b, f.f.Rets, t.Rets)
 	}
 
+	checkLabels(b, f.f.Body.Stmts)
 	ret.Body = buildStmts(b, f.f.Body.Stmts)
+	ret.HasDefer = b.fn.hasDefer
 
-	if len(b.retType) > 0 && !isBlockTerminal(f.f.Body) {
+	if len(b.retType) > 0 && !isBlockTerminal(b, f.f.Body) {
 		b.CodeErrorf(f.f.Body.Rbrace.Pos, "pl.missingReturn",
 			"missing return at the end of function")
 	}
 
+	b.escapes.check()
+
 	// clear for safety
 	b.retType = nil
 	b.retNamed = false
@@ -153,38 +162,40 @@ func buildFunc(b *builder, f *pkgFunc) *tast.Func {
 }
 
 func buildMethod(b *builder, f *pkgFunc) *tast.Func {
-	this := f.recv.pt
+	this := f.this
 	b.thisType = this
 	if f.f.Recv != nil { // go-like, explicit receiver
 		b.this = tast.NewAddressableRef(this)
 	} else { // inlined
 		b.this = tast.NewRef(this)
-		b.scope.PushTable(f.recv.t.Syms)
+		b.scope.PushTable(f.members)
 		defer b.scope.Pop()
 	}
 
 	return buildFunc(b, f)
 }
 
-func declareMethod(b *builder, ps *pkgStruct, f *ast.Func) *pkgFunc {
+func declareMethod(
+	b *builder, this *types.Pointer, members *syms.Table, f *ast.Func,
+) *pkgFunc {
 	if f.Alias != nil {
 		b.Errorf(f.Alias.Eq.Pos, "cannot alias a function for a method")
 		return nil
 	}
 
-	t := buildFuncType(b, ps.pt, f.FuncSig)
+	t := buildFuncType(b, this, f.FuncSig)
 	if t == nil {
 		return nil
 	}
 
 	name := f.Name.Lit
 	sym := syms.Make(b.path, name, tast.SymFunc, nil, t, f.Name.Pos)
-	conflict := ps.t.Syms.Declare(sym)
+	conflict := members.Declare(sym)
 	if conflict != nil {
 		b.Errorf(f.Name.Pos, "member %s already defined", name)
 		b.Errorf(conflict.Pos, "previously defined here")
 		return nil
 	}
 
-	return &pkgFunc{sym: sym, f: f, recv: ps}
+	return &pkgFunc{sym: sym, f: f, this: this, members: members}
 }
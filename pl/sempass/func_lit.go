@@ -0,0 +1,219 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+	"shanhu.io/smlvm/syms"
+)
+
+// funcScope is a function being built. It tracks the local variables of
+// the function for finding the variables captured by function literals.
+type funcScope struct {
+	parent *funcScope
+	f      *tast.Func
+	lit    *tast.FuncLit // nil for top-level functions
+	vars   map[*syms.Symbol]bool
+
+	captured map[*syms.Symbol]bool
+	hasDefer bool
+}
+
+func newFuncScope(
+	parent *funcScope, f *tast.Func, lit *tast.FuncLit,
+) *funcScope {
+	return &funcScope{
+		parent:   parent,
+		f:        f,
+		lit:      lit,
+		vars:     make(map[*syms.Symbol]bool),
+		captured: make(map[*syms.Symbol]bool),
+	}
+}
+
+// funcLitInfo saves how a function literal is used.
+type funcLitInfo struct {
+	lit    *tast.FuncLit
+	called bool         // called right away
+	bound  *syms.Symbol // saved in a local variable
+	gone   bool         // called by a go statement
+}
+
+// escapes performs the escape analysis of the function literals in a
+// top-level function. A function literal does not escape when it is
+// called right away, or when it is saved in a local variable that is
+// only used for calling, unless it is called by a go statement, which
+// might run it after the function returns.
+type escapes struct {
+	lits      []*funcLitInfo
+	litMap    map[*tast.FuncLit]*funcLitInfo
+	valueUses map[*syms.Symbol]bool
+	owners    map[*syms.Symbol]*tast.Func // of the captured variables
+}
+
+func newEscapes() *escapes {
+	return &escapes{
+		litMap:    make(map[*tast.FuncLit]*funcLitInfo),
+		valueUses: make(map[*syms.Symbol]bool),
+		owners:    make(map[*syms.Symbol]*tast.Func),
+	}
+}
+
+func (e *escapes) add(lit *tast.FuncLit) {
+	info := &funcLitInfo{lit: lit}
+	e.lits = append(e.lits, info)
+	e.litMap[lit] = info
+}
+
+func (e *escapes) escaped(info *funcLitInfo) bool {
+	if info.gone {
+		return true
+	}
+	if info.called {
+		return false
+	}
+	return info.bound == nil || e.valueUses[info.bound]
+}
+
+// check marks the escaping function literals that capture variables,
+// and the captured variables, which are moved to the heap.
+func (e *escapes) check() {
+	for _, info := range e.lits {
+		lit := info.lit
+		if len(lit.Captures) == 0 || !e.escaped(info) {
+			continue
+		}
+		lit.Escapes = true
+		for _, s := range lit.Captures {
+			f := e.owners[s]
+			if f.HeapVars == nil {
+				f.HeapVars = make(map[*syms.Symbol]bool)
+			}
+			f.HeapVars[s] = true
+		}
+	}
+}
+
+// litCalled marks a function literal that is called right away.
+func litCalled(b *builder, f tast.Expr) {
+	if lit, ok := f.(*tast.FuncLit); ok {
+		b.escapes.litMap[lit].called = true
+	}
+}
+
+// litGone marks the function called by a go statement. A function
+// literal escapes, and so does the one saved in a function variable.
+func litGone(b *builder, f tast.Expr) {
+	switch f := f.(type) {
+	case *tast.FuncLit:
+		b.escapes.litMap[f].gone = true
+	case *tast.Ident:
+		b.escapes.valueUses[f.Sym] = true
+	}
+}
+
+// litBound marks a function literal that is saved in a variable.
+func litBound(b *builder, s *syms.Symbol, e tast.Expr) {
+	if lst, ok := e.(*tast.ExprList); ok && lst.Len() == 1 {
+		e = lst.Exprs[0]
+	}
+	lit, ok := e.(*tast.FuncLit)
+	if !ok || s == nil || !b.fn.vars[s] {
+		return
+	}
+	b.escapes.litMap[lit].bound = s
+}
+
+// useVar records the use of a variable in an expression; it finds out
+// the variables that are captured and the function variables that are
+// used as values.
+func useVar(b *builder, s *syms.Symbol, callee bool) {
+	if b.fn == nil {
+		return
+	}
+	if !b.lhs && !callee {
+		if _, ok := s.ObjType.(*types.Func); ok {
+			b.escapes.valueUses[s] = true
+		}
+	}
+
+	var owner *funcScope
+	for fs := b.fn; fs != nil; fs = fs.parent {
+		if fs.vars[s] {
+			owner = fs
+			break
+		}
+	}
+	if owner == nil {
+		return // a global variable
+	}
+	if owner != b.fn {
+		b.escapes.owners[s] = owner.f
+	}
+
+	for fs := b.fn; fs != owner; fs = fs.parent {
+		if fs.captured[s] {
+			break
+		}
+		fs.captured[s] = true
+		fs.lit.Captures = append(fs.lit.Captures, s)
+	}
+}
+
+func buildFuncLit(b *builder, lit *ast.FuncLiteral) tast.Expr {
+	hold := b.lhsSwap(false)
+	defer b.lhsRestore(hold)
+
+	sig := lit.Type.FuncSig
+	t := buildFuncType(b, nil, sig)
+	if t == nil {
+		return nil
+	}
+
+	pos := lit.Type.Kw.Pos
+	f := new(tast.Func)
+	f.Sym = syms.Make(b.path, "func", tast.SymFunc, nil, t, pos)
+	ret := &tast.FuncLit{Func: f, Ref: tast.NewRef(t)}
+
+	nloop, nswitch := b.nloop, b.nswitch
+	retType, retNamed := b.retType, b.retNamed
+	b.nloop, b.nswitch = 0, 0
+	b.retType, b.retNamed = t.RetTypes, sig.NamedRet()
+	b.fn = newFuncScope(b.fn, f, ret)
+	defer func() {
+		b.nloop, b.nswitch = nloop, nswitch
+		b.retType, b.retNamed = retType, retNamed
+		b.fn = b.fn.parent
+	}()
+
+	b.scope.Push()
+	defer scopePopAndCheck(b)
+
+	f.Args = declareParas(b, sig.Args, t.Args)
+	if b.retNamed {
+		f.NamedRets = declareParas(b, sig.Rets, t.Rets)
+	}
+	checkLabels(b, lit.Body.Stmts)
+	f.Body = buildStmts(b, lit.Body.Stmts)
+	f.HasDefer = b.fn.hasDefer
+
+	if len(b.retType) > 0 && !isBlockTerminal(b, lit.Body) {
+		b.CodeErrorf(lit.Body.Rbrace.Pos, "pl.missingReturn",
+			"missing return at the end of function")
+	}
+
+	b.escapes.add(ret)
+	return ret
+}
+
+// checkNotInLit checks that the receiver of a method is not used
+// implicitly in a function literal.
+func checkNotInLit(b *builder, pos *lexing.Pos) bool {
+	if b.fn == nil || b.fn.lit == nil {
+		return true
+	}
+	b.CodeErrorf(pos, "pl.funcLit.this",
+		"cannot use this or implicit fields in a function literal")
+	return false
+}
@@ -45,25 +45,36 @@ func buildFuncs(b *builder, funcs []*pkgFunc) []*tast.Func {
 }
 
 func declareMethods(
-	b *builder, methods []*ast.Func, pkgStructs []*pkgStruct,
+	b *builder, methods []*ast.Func,
+	pkgStructs []*pkgStruct, pkgTypes []*pkgNamed,
 ) []*pkgFunc {
 	m := make(map[string]*pkgStruct)
 	for _, ps := range pkgStructs {
 		m[ps.name.Lit] = ps
 	}
+	mn := make(map[string]*pkgNamed)
+	for _, pn := range pkgTypes {
+		mn[pn.name.Lit] = pn
+	}
 
 	var ret []*pkgFunc
 
 	// go-like ones
 	for _, f := range methods {
 		recv := f.Recv.StructName
-		ps := m[recv.Lit]
-		if ps == nil {
+		var pf *pkgFunc
+		if ps := m[recv.Lit]; ps != nil {
+			pf = declareMethod(b, ps.pt, ps.t.Syms, f)
+		} else if pn := mn[recv.Lit]; pn != nil {
+			if !canHaveMethods(b, recv.Pos, pn.t) {
+				continue
+			}
+			pf = declareMethod(b, pn.pt, pn.t.Syms, f)
+		} else {
 			b.Errorf(recv.Pos, "struct %s not defined", recv.Lit)
 			continue
 		}
 
-		pf := declareMethod(b, ps, f)
 		if pf != nil {
 			ret = append(ret, pf)
 		}
@@ -0,0 +1,262 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+	"shanhu.io/smlvm/syms"
+)
+
+// typeParam is a type parameter of a generic function or struct.
+type typeParam struct {
+	name       *lexing.Token
+	constraint *lexing.Token
+}
+
+// generic is a generic function or struct. The instances are built
+// from the AST in the scope where the generic is declared, which might
+// be in another package.
+type generic struct {
+	name    *lexing.Token
+	params  []*typeParam
+	f       *ast.Func   // the generic function, or
+	s       *ast.Struct // the generic struct
+	methods []*ast.Func // the methods of the generic struct
+
+	path  string      // the package that declares the generic
+	scope *syms.Scope // the package scope
+}
+
+// constraints are the constraints that the type parameters can use.
+var constraints = map[string]func(t types.T) bool{
+	"any":        func(t types.T) bool { return true },
+	"comparable": isComparable,
+	"integer":    types.IsInteger,
+}
+
+// isComparable checks if the values of type t can be compared with
+// "==" and "!=".
+func isComparable(t types.T) bool {
+	switch types.Underlying(t).(type) {
+	case types.Basic, *types.Pointer, *types.Slice, *types.Interface,
+		*types.Chan:
+		return true
+	}
+	return types.IsFuncPointer(t)
+}
+
+func buildTypeParams(b *builder, lst *ast.TypeParamList) []*typeParam {
+	var ret []*typeParam
+	names := make(map[string]bool)
+	for _, p := range lst.Params {
+		name := p.Ident.Lit
+		if names[name] {
+			b.CodeErrorf(p.Ident.Pos, "pl.declConflict.typeParam",
+				"type parameter %s already defined", name)
+			return nil
+		}
+		names[name] = true
+		ret = append(ret, &typeParam{name: p.Ident})
+	}
+
+	// a parameter without a constraint shares the one of the next
+	var c *lexing.Token
+	for i := len(ret) - 1; i >= 0; i-- {
+		if t := lst.Params[i].Constraint; t != nil {
+			c = t
+		}
+		if constraints[c.Lit] == nil {
+			b.CodeErrorf(c.Pos, "pl.generic.badConstraint",
+				"unknown constraint %s, expect any, comparable or integer",
+				c.Lit)
+			return nil
+		}
+		ret[i].constraint = c
+	}
+	return ret
+}
+
+func declareGeneric(b *builder, g *generic) *syms.Symbol {
+	name := g.name.Lit
+	sym := syms.Make(b.path, name, tast.SymGeneric, g, nil, g.name.Pos)
+	conflict := b.scope.Declare(sym)
+	if conflict != nil {
+		b.CodeErrorf(g.name.Pos, "pl.declConflict.generic",
+			"%s already defined", name)
+		b.CodeErrorf(conflict.Pos, "pl.declConflict.previousPos",
+			"previously defined here as a %s", tast.SymStr(conflict.Type))
+		return nil
+	}
+	return sym
+}
+
+func newGeneric(
+	b *builder, name *lexing.Token, lst *ast.TypeParamList,
+) *generic {
+	params := buildTypeParams(b, lst)
+	if params == nil {
+		return nil
+	}
+	return &generic{
+		name:   name,
+		params: params,
+		path:   b.path,
+		scope:  b.scope.Copy(),
+	}
+}
+
+// declareGenerics declares the generic functions and structs of a
+// package, and attaches the methods to the generic structs.
+func declareGenerics(
+	b *builder, funcs []*ast.Func, structs []*ast.Struct,
+	methods []*ast.Func,
+) []*generic {
+	var ret []*generic
+	for _, f := range funcs {
+		if g := newGeneric(b, f.Name, f.TypeParams); g != nil {
+			g.f = f
+			if declareGeneric(b, g) != nil {
+				ret = append(ret, g)
+			}
+		}
+	}
+
+	m := make(map[string]*generic)
+	for _, s := range structs {
+		if g := newGeneric(b, s.Name, s.TypeParams); g != nil {
+			g.s = s
+			if declareGeneric(b, g) != nil {
+				ret = append(ret, g)
+				m[s.Name.Lit] = g
+			}
+		}
+	}
+
+	for _, f := range methods {
+		recv := f.Recv
+		g := m[recv.StructName.Lit]
+		if g == nil {
+			b.CodeErrorf(recv.StructName.Pos, "pl.generic.notGeneric",
+				"%s is not a generic struct", recv.StructName.Lit)
+			continue
+		}
+		if n := len(recv.TypeParams.Params); n != len(g.params) {
+			b.CodeErrorf(recv.TypeParams.Lbrack.Pos,
+				"pl.generic.argsCount",
+				"%s has %d type parameters, got %d",
+				g.name.Lit, len(g.params), n)
+			continue
+		}
+		g.methods = append(g.methods, f)
+	}
+	return ret
+}
+
+// genericStructDeps lists the identifiers that the fields of a generic
+// struct depend on, other than its type parameters.
+func genericStructDeps(g *generic) []string {
+	var ret []string
+	for _, dep := range listStructDeps(g.s) {
+		isParam := false
+		for _, p := range g.params {
+			if p.name.Lit == dep {
+				isParam = true
+			}
+		}
+		if !isParam {
+			ret = append(ret, dep)
+		}
+	}
+	return ret
+}
+
+// queryGeneric returns the generic that expr names, which is an
+// identifier or a member of an imported package. It returns nil when
+// expr is not a generic.
+func queryGeneric(b *builder, expr ast.Expr) *generic {
+	var sym *syms.Symbol
+	var tok *lexing.Token
+	switch expr := expr.(type) {
+	case *ast.Operand:
+		tok = expr.Token
+		sym = b.scope.Query(tok.Lit)
+	case *ast.MemberExpr:
+		op, ok := expr.Expr.(*ast.Operand)
+		if !ok {
+			return nil
+		}
+		s := b.scope.Query(op.Token.Lit)
+		if s == nil || s.Type != tast.SymImport {
+			return nil
+		}
+		tok = expr.Sub
+		if !syms.IsPublic(tok.Lit) {
+			return nil // reported when building the member
+		}
+		sym = s.ObjType.(*types.Pkg).Syms.Query(tok.Lit)
+		if sym != nil && sym.Type == tast.SymGeneric {
+			b.refSym(s, op.Token.Pos)
+		}
+	default:
+		return nil
+	}
+
+	if sym == nil || sym.Type != tast.SymGeneric {
+		return nil
+	}
+	b.refSym(sym, tok.Pos)
+	return sym.Obj.(*generic)
+}
+
+// notInstantiated reports the use of generic sym without type arguments.
+func notInstantiated(b *builder, pos *lexing.Pos, sym *syms.Symbol) {
+	b.CodeErrorf(pos, "pl.generic.notInstantiated",
+		"cannot use generic %s without instantiation", sym.Name())
+}
+
+func buildTypeArgs(b *builder, exprs []ast.Expr) []types.T {
+	var ret []types.T
+	for _, expr := range exprs {
+		t := b.buildType(expr)
+		if t == nil {
+			return nil
+		}
+		ret = append(ret, t)
+	}
+	return ret
+}
+
+// buildInstExpr instantiates the generic that expr names with the type
+// arguments in exprs.
+func buildInstExpr(b *builder, expr ast.Expr, exprs []ast.Expr) tast.Expr {
+	g := queryGeneric(b, expr)
+	if g == nil {
+		b.CodeErrorf(ast.ExprPos(expr), "pl.generic.notGeneric",
+			"expect a generic function or struct")
+		return nil
+	}
+	args := buildTypeArgs(b, exprs)
+	if args == nil {
+		return nil
+	}
+	inst := instantiate(b, ast.ExprPos(expr), g, args)
+	if inst == nil {
+		return nil
+	}
+	return instIdent(g, inst)
+}
+
+func buildInstType(b *builder, expr ast.Expr, exprs []ast.Expr) types.T {
+	ret := buildInstExpr(b, expr, exprs)
+	if ret == nil {
+		return nil
+	}
+	t, ok := ret.R().T.(*types.Type)
+	if !ok {
+		b.CodeErrorf(ast.ExprPos(expr), "pl.expectType",
+			"expect a type, got %s", ret.R().T)
+		return nil
+	}
+	return t.T
+}
@@ -31,7 +31,7 @@ func buildIncStmt(b *builder, stmt *ast.IncStmt) tast.Stmt {
 		return nil
 	}
 
-	if !ref.Addressable {
+	if !assignable(expr, 0) {
 		b.CodeErrorf(
 			stmt.Op.Pos, "pl.incStmt.nonAddressable",
 			"%s on non-addressable", op,
@@ -11,6 +11,10 @@ func buildIndexExpr(b *builder, expr *ast.IndexExpr) tast.Expr {
 	hold := b.lhsSwap(false)
 	defer b.lhsRestore(hold)
 
+	if expr.Colon == nil && queryGeneric(b, expr.Array) != nil {
+		return buildInstExpr(b, expr.Array, []ast.Expr{expr.Index})
+	}
+
 	array := b.buildExpr(expr.Array)
 	if array == nil {
 		return nil
@@ -22,6 +26,9 @@ func buildIndexExpr(b *builder, expr *ast.IndexExpr) tast.Expr {
 		return nil
 	}
 
+	if _, ok := types.Underlying(ref.T).(*types.Map); ok {
+		return buildMapIndex(b, expr, array)
+	}
 	if expr.Colon != nil {
 		return buildSlicing(b, expr, array)
 	}
@@ -30,7 +37,7 @@ func buildIndexExpr(b *builder, expr *ast.IndexExpr) tast.Expr {
 }
 
 func elementType(t types.T) types.T {
-	switch t := t.(type) {
+	switch t := types.Underlying(t).(type) {
 	case *types.Array:
 		return t.T
 	case *types.Slice:
@@ -120,7 +127,8 @@ func buildArrayGet(
 		return nil
 	}
 
-	ref := tast.NewAddressableRef(et)
+	ref := tast.NewRef(et)
+	ref.Addressable = !isMapIndex(array)
 	return &tast.IndexExpr{
 		Array: array,
 		Index: index,
@@ -0,0 +1,238 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/parse"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// paraTypes returns the type expressions of the parameters in lst.
+func paraTypes(lst *ast.ParaList) []ast.Expr {
+	named := false
+	for _, p := range lst.Paras {
+		if p.Ident != nil && p.Type != nil {
+			named = true
+		}
+	}
+
+	ret := make([]ast.Expr, len(lst.Paras))
+	if !named {
+		for i, p := range lst.Paras {
+			if p.Type != nil {
+				ret[i] = p.Type
+			} else {
+				ret[i] = ast.NewOperand(p.Ident)
+			}
+		}
+		return ret
+	}
+
+	// a named parameter without a type shares the type of the next
+	var t ast.Expr
+	for i := len(lst.Paras) - 1; i >= 0; i-- {
+		if p := lst.Paras[i]; p.Type != nil {
+			t = p.Type
+		}
+		ret[i] = t
+	}
+	return ret
+}
+
+// inferer infers the type arguments of a generic function from the
+// types of the arguments of a call.
+type inferer struct {
+	params map[string]bool
+	bound  map[string]types.T
+}
+
+func newInferer(g *generic) *inferer {
+	ret := &inferer{
+		params: make(map[string]bool),
+		bound:  make(map[string]types.T),
+	}
+	for _, p := range g.params {
+		ret.params[p.name.Lit] = true
+	}
+	return ret
+}
+
+// argType returns the type that an argument of type t has when it binds
+// to a type parameter.
+func argType(t types.T) types.T {
+	if _, ok := types.NumConst(t); ok {
+		return types.Int
+	}
+	if c, ok := t.(*types.Const); ok {
+		return c.Type
+	}
+	return t
+}
+
+func (inf *inferer) infer(expr ast.Expr, t types.T) {
+	if t == nil || types.IsNil(t) {
+		return
+	}
+
+	switch expr := expr.(type) {
+	case *ast.Operand:
+		name := expr.Token.Lit
+		if expr.Token.Type != parse.Ident || !inf.params[name] {
+			return
+		}
+		if inf.bound[name] == nil {
+			inf.bound[name] = t
+		}
+	case *ast.ParenExpr:
+		inf.infer(expr.Expr, t)
+	case *ast.StarExpr:
+		if t, ok := t.(*types.Pointer); ok {
+			inf.infer(expr.Expr, t.T)
+		}
+	case *ast.ArrayTypeExpr:
+		switch t := t.(type) {
+		case *types.Slice:
+			if expr.Len == nil {
+				inf.infer(expr.Type, t.T)
+			}
+		case *types.Array:
+			if expr.Len != nil {
+				inf.infer(expr.Type, t.T)
+			}
+		}
+	case *ast.MapTypeExpr:
+		if t, ok := t.(*types.Map); ok {
+			inf.infer(expr.Key, t.Key)
+			inf.infer(expr.Val, t.Val)
+		}
+	case *ast.ChanTypeExpr:
+		if t, ok := t.(*types.Chan); ok {
+			inf.infer(expr.Elem, t.T)
+		}
+	case *ast.FuncTypeExpr:
+		if t, ok := t.(*types.Func); ok {
+			inf.inferFunc(expr.FuncSig, t)
+		}
+	case *ast.InstExpr:
+		inf.inferInst(expr.Types.Exprs, t)
+	case *ast.IndexExpr:
+		inf.inferInst([]ast.Expr{expr.Index}, t)
+	}
+}
+
+func (inf *inferer) inferInst(exprs []ast.Expr, t types.T) {
+	st, ok := t.(*types.Struct)
+	if !ok || len(st.TypeArgs) != len(exprs) {
+		return
+	}
+	for i, expr := range exprs {
+		inf.infer(expr, st.TypeArgs[i])
+	}
+}
+
+func (inf *inferer) inferFunc(sig *ast.FuncSig, t *types.Func) {
+	inf.inferList(paraTypes(sig.Args), t.ArgTypes)
+	if sig.RetType != nil {
+		if len(t.RetTypes) == 1 {
+			inf.infer(sig.RetType, t.RetTypes[0])
+		}
+		return
+	}
+	inf.inferList(paraTypes(sig.Rets), t.RetTypes)
+}
+
+func (inf *inferer) inferList(exprs []ast.Expr, ts []types.T) {
+	if len(exprs) != len(ts) {
+		return
+	}
+	for i, expr := range exprs {
+		inf.infer(expr, ts[i])
+	}
+}
+
+// inferTypeArgs infers the type arguments of a call to generic function
+// g, where the arguments are of types ts.
+func inferTypeArgs(
+	b *builder, expr *ast.CallExpr, g *generic, ts []types.T,
+) []types.T {
+	inf := newInferer(g)
+	paras := g.f.FuncSig.Args.Paras
+	exprs := paraTypes(g.f.FuncSig.Args)
+	for i, t := range ts {
+		t = argType(t)
+		if i < len(paras)-1 || i < len(paras) && paras[i].Dots == nil {
+			inf.infer(exprs[i], t)
+			continue
+		}
+		n := len(paras)
+		if n == 0 || paras[n-1].Dots == nil {
+			break // argument count mismatch, reported later
+		}
+		if expr.Dots != nil {
+			if t, ok := t.(*types.Slice); ok {
+				inf.infer(exprs[n-1], t.T)
+			}
+		} else {
+			inf.infer(exprs[n-1], t)
+		}
+	}
+
+	var ret []types.T
+	for _, p := range g.params {
+		t := inf.bound[p.name.Lit]
+		if t == nil {
+			b.CodeErrorf(ast.ExprPos(expr), "pl.generic.cannotInfer",
+				"cannot infer type parameter %s of %s",
+				p.name.Lit, g.name.Lit)
+			return nil
+		}
+		ret = append(ret, t)
+	}
+	return ret
+}
+
+// instIdent returns the identifier of an instance of generic g.
+func instIdent(g *generic, inst *instance) tast.Expr {
+	t := inst.sym.ObjType.(types.T)
+	return &tast.Ident{Token: g.name, Ref: tast.NewRef(t), Sym: inst.sym}
+}
+
+// buildGenericCall builds a call to generic function g, where the type
+// arguments are inferred from the arguments.
+func buildGenericCall(
+	b *builder, expr *ast.CallExpr, g *generic,
+) tast.Expr {
+	args := buildExprList(b, expr.Args)
+	if args == nil {
+		return nil
+	}
+	targs := inferTypeArgs(b, expr, g, args.R().TypeList())
+	if targs == nil {
+		return nil
+	}
+	inst := instantiate(b, ast.ExprPos(expr.Func), g, targs)
+	if inst == nil {
+		return nil
+	}
+
+	f := instIdent(g, inst)
+	funcType := f.R().T.(*types.Func)
+	if expr.Dots != nil && !funcType.IsVariadic {
+		b.CodeErrorf(expr.Dots.Pos, "pl.call.notVariadic",
+			"cannot use ... in call to non-variadic %s", f.R())
+		return nil
+	}
+	if funcType.IsVariadic && expr.Dots == nil {
+		lst, ok := tast.MakeExprList(args)
+		if !ok {
+			b.CodeErrorf(ast.ExprPos(expr), "pl.multiRefInExprList",
+				"cannot use %s as variadic arguments", args.R())
+			return nil
+		}
+		args = packVariadicArgs(b, expr, lst.Exprs, funcType)
+		if args == nil {
+			return nil
+		}
+	}
+	return buildFuncCall(b, expr, f, funcType, args)
+}
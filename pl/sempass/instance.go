@@ -0,0 +1,258 @@
+package sempass
+
+import (
+	"fmt"
+
+	"shanhu.io/smlvm/fmtutil"
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+	"shanhu.io/smlvm/syms"
+)
+
+// maxInstDepth limits the nesting of instantiations, which never ends
+// when a generic instantiates itself with a growing type argument.
+const maxInstDepth = 16
+
+// instances are the instances of generics that a package uses. The
+// package emits the code of all the instances that it uses, including
+// the ones of the generics declared in other packages.
+type instances struct {
+	path    string
+	m       map[*generic][]*instance
+	pending []*instFunc     // functions with bodies not built yet
+	names   map[string]bool // for keeping the instance names unique
+
+	funcs   []*tast.Func
+	methods []*tast.Func
+	structs []*syms.Symbol
+}
+
+func newInstances(path string) *instances {
+	return &instances{
+		path:  path,
+		m:     make(map[*generic][]*instance),
+		names: make(map[string]bool),
+	}
+}
+
+func (insts *instances) find(g *generic, args []types.T) *instance {
+	for _, inst := range insts.m[g] {
+		if types.SameTypes(inst.args, args) {
+			return inst
+		}
+	}
+	return nil
+}
+
+func (insts *instances) add(g *generic, inst *instance) {
+	insts.m[g] = append(insts.m[g], inst)
+}
+
+// instance is a generic function or struct with its type parameters
+// bound to a list of type arguments.
+type instance struct {
+	args []types.T
+	sym  *syms.Symbol
+}
+
+// instFunc is a function or a method of an instance that is waiting for
+// its body to be built.
+type instFunc struct {
+	g     *generic
+	f     *pkgFunc
+	scope *syms.Scope
+	depth int
+	pos   *lexing.Pos // where it is instantiated
+}
+
+// name returns a name for the instance of generic g with type arguments
+// args. Generics in different packages might share the same name.
+func (insts *instances) name(g *generic, args []types.T) string {
+	base := g.name.Lit + "[" + fmtutil.Join(args, ",") + "]"
+	ret := base
+	for i := 2; insts.names[ret]; i++ {
+		ret = fmt.Sprintf("%s#%d", base, i)
+	}
+	insts.names[ret] = true
+	return ret
+}
+
+// typeArgScope returns the scope of the generic, with the type
+// parameter names bound to the type arguments.
+func typeArgScope(
+	b *builder, g *generic, names []*lexing.Token, args []types.T,
+) *syms.Scope {
+	ret := g.scope.Copy()
+	ret.Push()
+	for i, name := range names {
+		t := &types.Type{T: args[i]}
+		sym := syms.Make(g.path, name.Lit, tast.SymType, nil, t, name.Pos)
+		if conflict := ret.Declare(sym); conflict != nil {
+			b.CodeErrorf(name.Pos, "pl.declConflict.typeParam",
+				"type parameter %s already defined", name.Lit)
+			return nil
+		}
+	}
+	return ret
+}
+
+// instBuilder returns a builder for building an instance of generic g
+// in scope.
+func instBuilder(b *builder, g *generic, scope *syms.Scope) *builder {
+	ret := makeBuilder(g.path, scope)
+	ret.insts = b.insts
+	ret.golike = b.golike
+	ret.instDepth = b.instDepth + 1
+	return ret
+}
+
+func paramNames(g *generic) []*lexing.Token {
+	var ret []*lexing.Token
+	for _, p := range g.params {
+		ret = append(ret, p.name)
+	}
+	return ret
+}
+
+// instantiate returns the instance of generic g with type arguments
+// args, and builds it if the package has not used it yet.
+func instantiate(
+	b *builder, pos *lexing.Pos, g *generic, args []types.T,
+) *instance {
+	if len(args) != len(g.params) {
+		b.CodeErrorf(pos, "pl.generic.argsCount",
+			"%s has %d type parameters, got %d",
+			g.name.Lit, len(g.params), len(args))
+		return nil
+	}
+	for i, p := range g.params {
+		c := p.constraint.Lit
+		if !constraints[c](args[i]) {
+			b.CodeErrorf(pos, "pl.generic.constraint",
+				"%s does not satisfy %s of type parameter %s",
+				args[i], c, p.name.Lit)
+			return nil
+		}
+	}
+
+	if inst := b.insts.find(g, args); inst != nil {
+		return inst
+	}
+	if b.instDepth >= maxInstDepth {
+		b.CodeErrorf(pos, "pl.generic.tooDeep",
+			"instantiation of %s nested too deep", g.name.Lit)
+		return nil
+	}
+
+	inst := &instance{args: args}
+	if g.f != nil {
+		if !instFuncSig(b, pos, g, inst) {
+			return nil
+		}
+	} else {
+		instStruct(b, pos, g, inst)
+	}
+	return inst
+}
+
+func instFuncSig(
+	b *builder, pos *lexing.Pos, g *generic, inst *instance,
+) bool {
+	scope := typeArgScope(b, g, paramNames(g), inst.args)
+	ib := instBuilder(b, g, scope)
+	t := buildFuncType(ib, nil, g.f.FuncSig)
+	if t == nil {
+		b.AddAll(ib.Errs())
+		return false
+	}
+
+	name := b.insts.name(g, inst.args)
+	inst.sym = syms.Make(g.path, name, tast.SymFunc, nil, t, g.name.Pos)
+	b.insts.add(g, inst)
+	b.insts.pending = append(b.insts.pending, &instFunc{
+		g:     g,
+		f:     &pkgFunc{sym: inst.sym, f: g.f},
+		scope: scope,
+		depth: ib.instDepth,
+		pos:   pos,
+	})
+	return true
+}
+
+func instStruct(b *builder, pos *lexing.Pos, g *generic, inst *instance) {
+	name := b.insts.name(g, inst.args)
+	t := types.NewStruct(name)
+	t.Pkg = b.insts.path
+	t.Generic = g
+	t.TypeArgs = inst.args
+	inst.sym = syms.Make(
+		g.path, name, tast.SymStruct, nil, &types.Type{T: t}, g.name.Pos,
+	)
+	b.insts.add(g, inst) // added before the fields for recursive types
+	b.insts.structs = append(b.insts.structs, inst.sym)
+
+	ps := &pkgStruct{
+		name: g.name,
+		ast:  g.s,
+		sym:  inst.sym,
+		t:    t,
+		pt:   types.NewPointer(t),
+	}
+	scope := typeArgScope(b, g, paramNames(g), inst.args)
+	ib := instBuilder(b, g, scope)
+	buildFields(ib, ps)
+	b.AddAll(ib.Errs())
+
+	for _, f := range g.methods {
+		names := make([]*lexing.Token, 0, len(g.params))
+		for _, p := range f.Recv.TypeParams.Params {
+			names = append(names, p.Ident)
+		}
+		scope := typeArgScope(b, g, names, inst.args)
+		if scope == nil {
+			continue
+		}
+		mb := instBuilder(b, g, scope)
+		pf := declareMethod(mb, ps.pt, t.Syms, f)
+		b.AddAll(mb.Errs())
+		if pf == nil {
+			continue
+		}
+		b.insts.pending = append(b.insts.pending, &instFunc{
+			g:     g,
+			f:     pf,
+			scope: scope,
+			depth: mb.instDepth,
+			pos:   pos,
+		})
+	}
+}
+
+// buildInstances builds the bodies of the instance functions and
+// methods, including the ones instantiated while building them.
+func buildInstances(b *builder) {
+	insts := b.insts
+	for len(insts.pending) > 0 {
+		f := insts.pending[0]
+		insts.pending = insts.pending[1:]
+
+		ib := makeBuilder(f.g.path, f.scope)
+		ib.insts = insts
+		ib.golike = b.golike
+		ib.instDepth = f.depth
+		if f.f.this != nil {
+			if ret := buildMethod(ib, f.f); ret != nil {
+				insts.methods = append(insts.methods, ret)
+			}
+		} else if ret := buildFunc(ib, f.f); ret != nil {
+			insts.funcs = append(insts.funcs, ret)
+		}
+
+		if errs := ib.Errs(); errs != nil {
+			b.AddAll(errs)
+			b.CodeErrorf(f.pos, "pl.generic.instPos",
+				"in %s instantiated here", f.f.sym.Name())
+		}
+	}
+}
@@ -58,10 +58,10 @@ func buildInterface(b *builder, pi *pkgInterface) {
 	t := pi.t
 	for _, f := range pi.ast.Funcs {
 		ft := buildFuncType(b, nil, f.FuncSig)
-		ft.IsBond = true
 		if ft == nil {
 			return
 		}
+		ft.IsBond = true
 		name := f.Name.Lit
 		sym := syms.Make(b.path, name, tast.SymFunc, nil, ft, f.Name.Pos)
 		conflict := t.Syms.Declare(sym)
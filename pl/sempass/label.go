@@ -0,0 +1,230 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+)
+
+// labelBlock is a list of statements in a function body. A goto can
+// only jump to a label in its own block or in an enclosing block.
+type labelBlock struct {
+	parent *labelBlock
+	index  int // index of the statement in parent that has the block
+	stmts  []ast.Stmt
+}
+
+type labelInfo struct {
+	tok   *lexing.Token
+	block *labelBlock
+	index int
+	used  bool
+}
+
+type labelGoto struct {
+	stmt  *ast.GotoStmt
+	block *labelBlock
+	index int
+}
+
+type labelBranch struct {
+	label      *lexing.Token
+	isContinue bool
+}
+
+// labelChecker checks the labels of a function body. It does not go
+// into function literals, which have their own labels.
+type labelChecker struct {
+	b      *builder
+	labels map[string]*labelInfo
+	order  []*labelInfo
+
+	targets  []*ast.LabeledStmt // enclosing labeled for and switch
+	gotos    []*labelGoto
+	branches []*labelBranch // break and continue with an invalid label
+}
+
+func subStmts(s ast.Stmt) [][]ast.Stmt {
+	switch s := s.(type) {
+	case *ast.BlockStmt:
+		return [][]ast.Stmt{s.Block.Stmts}
+	case *ast.IfStmt:
+		var ret [][]ast.Stmt
+		if body, ok := s.Body.(*ast.Block); ok {
+			ret = append(ret, body.Stmts)
+		} else if s.Body != nil {
+			ret = append(ret, []ast.Stmt{s.Body})
+		}
+		for e := s.Else; e != nil; e = e.Next {
+			ret = append(ret, e.Body.Stmts)
+		}
+		return ret
+	case *ast.ForStmt:
+		return [][]ast.Stmt{s.Body.Stmts}
+	case *ast.SwitchStmt:
+		var ret [][]ast.Stmt
+		for _, c := range s.Cases {
+			ret = append(ret, c.Stmts)
+		}
+		return ret
+	case *ast.TypeSwitchStmt:
+		var ret [][]ast.Stmt
+		for _, c := range s.Cases {
+			ret = append(ret, c.Stmts)
+		}
+		return ret
+	case *ast.SelectStmt:
+		var ret [][]ast.Stmt
+		for _, c := range s.Cases {
+			ret = append(ret, c.Stmts)
+		}
+		return ret
+	}
+	return nil
+}
+
+func (c *labelChecker) checkBlock(blk *labelBlock) {
+	for i, s := range blk.stmts {
+		c.checkStmt(blk, i, s)
+	}
+}
+
+func (c *labelChecker) checkStmt(blk *labelBlock, i int, s ast.Stmt) {
+	switch s := s.(type) {
+	case *ast.LabeledStmt:
+		name := s.Label.Lit
+		if l := c.labels[name]; l != nil {
+			c.b.CodeErrorf(s.Label.Pos, "pl.label.duplicate",
+				"label %q already defined", name)
+		} else {
+			l := &labelInfo{tok: s.Label, block: blk, index: i}
+			c.labels[name] = l
+			c.order = append(c.order, l)
+		}
+
+		switch s.Stmt.(type) {
+		case *ast.ForStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt,
+			*ast.SelectStmt:
+			c.targets = append(c.targets, s)
+			defer func() { c.targets = c.targets[:len(c.targets)-1] }()
+		}
+		c.checkStmt(blk, i, s.Stmt)
+	case *ast.GotoStmt:
+		c.gotos = append(c.gotos, &labelGoto{s, blk, i})
+	case *ast.BreakStmt:
+		if s.Label != nil {
+			c.checkBranch(s.Label, false)
+		}
+	case *ast.ContinueStmt:
+		if s.Label != nil {
+			c.checkBranch(s.Label, true)
+		}
+	default:
+		for _, stmts := range subStmts(s) {
+			c.checkBlock(&labelBlock{parent: blk, index: i, stmts: stmts})
+		}
+	}
+}
+
+// checkBranch checks that a break or continue uses the label of an
+// enclosing statement.
+func (c *labelChecker) checkBranch(label *lexing.Token, isContinue bool) {
+	for _, t := range c.targets {
+		if t.Label.Lit != label.Lit {
+			continue
+		}
+		if _, ok := t.Stmt.(*ast.ForStmt); ok || !isContinue {
+			c.labels[label.Lit].used = true
+			return
+		}
+	}
+	c.branches = append(c.branches, &labelBranch{label, isContinue})
+}
+
+func isVarDecl(s ast.Stmt) bool {
+	switch s := s.(type) {
+	case *ast.LabeledStmt:
+		return isVarDecl(s.Stmt)
+	case *ast.VarDecls, *ast.DefineStmt:
+		return true
+	}
+	return false
+}
+
+func (c *labelChecker) checkGoto(g *labelGoto) {
+	name := g.stmt.Label.Lit
+	pos := g.stmt.Label.Pos
+	l := c.labels[name]
+	if l == nil {
+		c.b.CodeErrorf(pos, "pl.label.undefined",
+			"label %q not defined", name)
+		return
+	}
+	l.used = true
+
+	index := g.index
+	blk := g.block
+	for blk != nil && blk != l.block {
+		index = blk.index
+		blk = blk.parent
+	}
+	if blk == nil {
+		c.b.CodeErrorf(pos, "pl.goto.intoBlock",
+			"goto %q jumps into block", name)
+		return
+	}
+	for i := index + 1; i < l.index; i++ {
+		if isVarDecl(blk.stmts[i]) {
+			c.b.CodeErrorf(pos, "pl.goto.overDecl",
+				"goto %q jumps over variable declaration", name)
+			return
+		}
+	}
+}
+
+func (c *labelChecker) checkInvalidBranch(br *labelBranch) {
+	name := br.label.Lit
+	pos := br.label.Pos
+	l := c.labels[name]
+	if l == nil {
+		c.b.CodeErrorf(pos, "pl.label.undefined",
+			"label %q not defined", name)
+		return
+	}
+	l.used = true
+	if br.isContinue {
+		c.b.CodeErrorf(pos, "pl.label.invalidContinue",
+			"invalid continue label %q", name)
+	} else {
+		c.b.CodeErrorf(pos, "pl.label.invalidBreak",
+			"invalid break label %q", name)
+	}
+}
+
+// checkLabels checks the labels, the gotos and the labeled breaks and
+// continues in a function body.
+func checkLabels(b *builder, stmts []ast.Stmt) {
+	c := &labelChecker{b: b, labels: make(map[string]*labelInfo)}
+	c.checkBlock(&labelBlock{stmts: stmts})
+
+	for _, g := range c.gotos {
+		c.checkGoto(g)
+	}
+	for _, br := range c.branches {
+		c.checkInvalidBranch(br)
+	}
+	for _, l := range c.order {
+		if !l.used {
+			c.b.CodeErrorf(l.tok.Pos, "pl.label.unused",
+				"label %q defined and not used", l.tok.Lit)
+		}
+	}
+}
+
+func buildLabeledStmt(b *builder, s *ast.LabeledStmt) tast.Stmt {
+	return &tast.LabeledStmt{Label: s.Label.Lit, Stmt: b.buildStmt(s.Stmt)}
+}
+
+func buildGotoStmt(b *builder, s *ast.GotoStmt) tast.Stmt {
+	return &tast.GotoStmt{Label: s.Label.Lit}
+}
@@ -0,0 +1,218 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// buildMapElem builds a key or a value for a map of which the key or the
+// value is of type t.
+func buildMapElem(b *builder, expr ast.Expr, t types.T, in string) tast.Expr {
+	v := buildElem(b, expr, t)
+	if v == nil {
+		return nil
+	}
+
+	pos := ast.ExprPos(expr)
+	ref := v.R()
+	if !ref.IsSingle() {
+		b.CodeErrorf(pos, "pl.map.notSingle",
+			"%s must be a single value", in)
+		return nil
+	}
+	if num, ok := types.NumConst(ref.T); ok && types.IsInteger(t) {
+		return numCast(b, pos, num, v, t)
+	}
+
+	ok, needCast := canAssign(b, pos, t, ref.T, in)
+	if !ok {
+		return nil
+	}
+	if needCast {
+		return tast.NewCast(v, t)
+	}
+	return v
+}
+
+// mapLitKey returns the value of a constant key in a map literal for
+// checking duplications.
+func mapLitKey(k tast.Expr) (interface{}, bool) {
+	if c, ok := k.(*tast.Cast); ok {
+		k = c.From
+	}
+	if c, ok := k.R().T.(*types.Const); ok {
+		return c.Value, true
+	}
+	return nil, false
+}
+
+func buildMapLit(b *builder, lit *ast.MapLiteral) tast.Expr {
+	hold := b.lhsSwap(false)
+	defer b.lhsRestore(hold)
+
+	t := b.buildType(lit.Type)
+	if t == nil {
+		return nil
+	}
+	return buildMapLitOf(b, t, lit.Exprs)
+}
+
+// buildMapLitOf builds a map literal of type t, which can be a named type.
+func buildMapLitOf(b *builder, t types.T, exprs *ast.ExprList) tast.Expr {
+	mt := types.Underlying(t).(*types.Map)
+
+	ret := &tast.MapLit{Ref: tast.NewRef(t)}
+	if exprs == nil {
+		return ret
+	}
+
+	keys := make(map[interface{}]bool)
+	for _, expr := range exprs.Exprs {
+		kv, ok := expr.(*ast.KeyValueExpr)
+		if !ok {
+			b.CodeErrorf(ast.ExprPos(expr), "pl.mapLit.missingKey",
+				"missing key in map literal")
+			return nil
+		}
+
+		k := buildMapElem(b, kv.Key, mt.Key, "map literal key")
+		if k == nil {
+			return nil
+		}
+		if v, ok := mapLitKey(k); ok {
+			if keys[v] {
+				b.CodeErrorf(ast.ExprPos(kv.Key), "pl.mapLit.duplicateKey",
+					"duplicate key in map literal")
+				return nil
+			}
+			keys[v] = true
+		}
+
+		v := buildMapElem(b, kv.Value, mt.Val, "map literal")
+		if v == nil {
+			return nil
+		}
+		ret.Keys = append(ret.Keys, k)
+		ret.Vals = append(ret.Vals, v)
+	}
+	return ret
+}
+
+func buildMapIndex(
+	b *builder, expr *ast.IndexExpr, m tast.Expr,
+) tast.Expr {
+	if expr.Colon != nil {
+		b.CodeErrorf(expr.Lbrack.Pos, "pl.map.slicing",
+			"cannot slice a map")
+		return nil
+	}
+
+	t := types.Underlying(m.R().T).(*types.Map)
+	k := buildMapElem(b, expr.Index, t.Key, "map index")
+	if k == nil {
+		return nil
+	}
+	return &tast.MapIndex{Map: m, Key: k, Ref: tast.NewRef(t.Val)}
+}
+
+// isMapIndex checks if an expression is an element in a map.
+func isMapIndex(e tast.Expr) bool {
+	_, ok := e.(*tast.MapIndex)
+	return ok
+}
+
+// assignable checks if the i-th value of dest can be assigned to. An
+// element in a map is not addressable, but can be assigned to.
+func assignable(dest tast.Expr, i int) bool {
+	if lst, ok := dest.(*tast.ExprList); ok {
+		dest, i = lst.Exprs[i], 0
+	}
+	if m, ok := dest.(*tast.MapIndex); ok {
+		m.Assign = true
+		return true
+	}
+	return dest.R().At(i).Addressable
+}
+
+// commaOk turns a map index, a type assertion or a channel receive into a
+// comma-ok form when it is assigned to n values.
+func commaOk(e tast.Expr, n int) tast.Expr {
+	if n != 2 {
+		return e
+	}
+	switch e := e.(type) {
+	case *tast.MapIndex:
+		if e.CommaOk {
+			return e
+		}
+		r := tast.AppendRef(tast.NewRef(e.Type()), tast.NewRef(types.Bool))
+		return &tast.MapIndex{Map: e.Map, Key: e.Key, CommaOk: true, Ref: r}
+	case *tast.TypeAssert:
+		if e.CommaOk {
+			return e
+		}
+		r := tast.AppendRef(tast.NewRef(e.T), tast.NewRef(types.Bool))
+		return &tast.TypeAssert{Expr: e.Expr, T: e.T, CommaOk: true, Ref: r}
+	case *tast.ChanRecv:
+		if e.CommaOk {
+			return e
+		}
+		r := tast.AppendRef(tast.NewRef(e.Type()), tast.NewRef(types.Bool))
+		return &tast.ChanRecv{Chan: e.Chan, CommaOk: true, Ref: r}
+	}
+	return e
+}
+
+func buildCallDelete(
+	b *builder, expr *ast.CallExpr, f tast.Expr,
+) tast.Expr {
+	pos := expr.Lparen.Pos
+	if expr.Args.Len() != 2 {
+		b.Errorf(pos, "delete() takes two arguments")
+		return nil
+	}
+
+	m := b.buildExpr(expr.Args.Exprs[0])
+	if m == nil {
+		return nil
+	}
+	t, ok := types.Underlying(m.R().T).(*types.Map)
+	if !ok || !m.R().IsSingle() {
+		b.Errorf(pos, "delete() takes a map as the 1st argument")
+		return nil
+	}
+	k := buildMapElem(b, expr.Args.Exprs[1], t.Key, "delete()")
+	if k == nil {
+		return nil
+	}
+
+	args := tast.NewExprList()
+	args.Append(m)
+	args.Append(k)
+	return &tast.CallExpr{Func: f, Args: args, Ref: tast.Void}
+}
+
+// buildMakeMap builds make(map[K]V) or make(map[K]V, n). The size hint
+// is checked but not used.
+func buildMakeMap(
+	b *builder, pos *lexing.Pos, t *types.Map, args *tast.ExprList,
+	f tast.Expr,
+) tast.Expr {
+	n := args.Len()
+	if n > 2 {
+		b.Errorf(pos, "make() map takes at most 2 arguments")
+		return nil
+	}
+	callArgs := tast.NewExprList()
+	callArgs.Append(args.Exprs[0])
+	if n == 2 {
+		size := checkArrayIndex(b, args.Exprs[1], pos)
+		if size == nil {
+			return nil
+		}
+		callArgs.Append(size)
+	}
+	return &tast.CallExpr{Func: f, Args: callArgs, Ref: tast.NewRef(t)}
+}
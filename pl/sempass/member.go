@@ -77,9 +77,13 @@ func buildPkgSym(
 		)
 		return nil, nil
 	}
+	if sym.Type == tast.SymGeneric {
+		notInstantiated(b, m.Sub.Pos, sym)
+		return nil, nil
+	}
 	t := sym.ObjType.(types.T)
 	switch sym.Type {
-	case tast.SymConst, tast.SymStruct, tast.SymFunc:
+	case tast.SymConst, tast.SymStruct, tast.SymNamed, tast.SymFunc:
 		return tast.NewRef(t), sym
 	case tast.SymVar:
 		return tast.NewAddressableRef(t), sym
@@ -91,6 +95,54 @@ func buildPkgSym(
 	return nil, nil
 }
 
+// findMemberOf looks up the member of a value of type t. It returns nil
+// and reports the error when the member is not found.
+func findMemberOf(b *builder, m *ast.MemberExpr, t types.T) *member {
+	name := m.Sub.Lit
+	if n := namedOf(t); n != nil {
+		if sym := n.Syms.Query(name); sym != nil {
+			return &member{sym: sym}
+		}
+	}
+
+	var found *member
+	if i, ok := types.Underlying(t).(*types.Interface); ok {
+		if sym := i.Syms.Query(name); sym != nil {
+			found = &member{sym: sym}
+		}
+	} else {
+		pt := types.PointerOf(t)
+		var tstruct *types.Struct
+		var ok bool
+		if pt != nil {
+			pt = types.Underlying(pt)
+			if tstruct, ok = pt.(*types.Struct); !ok {
+				b.CodeErrorf(m.Dot.Pos, "pl.buildMember.illegal",
+					"*%s is not a pointer of struct or interface", t)
+				return nil
+			}
+		} else {
+			if tstruct, ok = types.Underlying(t).(*types.Struct); !ok {
+				b.CodeErrorf(m.Dot.Pos, "pl.buildMember.illegal",
+					"%s is not a struct or interface", t)
+				return nil
+			}
+		}
+		var ambiguous bool
+		found, ambiguous = findMember(tstruct, name)
+		if ambiguous {
+			b.CodeErrorf(m.Dot.Pos, "pl.buildMember.ambiguous",
+				"ambiguous selector %s on %s", name, t)
+			return nil
+		}
+	}
+	if found == nil {
+		b.CodeErrorf(m.Dot.Pos, "pl.buildMember.notFound",
+			"%s has no member named %s", t, name)
+	}
+	return found
+}
+
 func buildMember(b *builder, m *ast.MemberExpr) tast.Expr {
 	hold := b.lhsSwap(false)
 	defer b.lhsRestore(hold)
@@ -117,50 +169,35 @@ func buildMember(b *builder, m *ast.MemberExpr) tast.Expr {
 		return &tast.MemberExpr{Expr: obj, Sub: m.Sub, Ref: r, Sym: sym}
 	}
 
-	var symTable *syms.Table
-
-	if i, ok := t.(*types.Interface); ok {
-		symTable = i.Syms
-	} else {
-		pt := types.PointerOf(t)
-		var tstruct *types.Struct
-		var ok bool
-		if pt != nil {
-			if tstruct, ok = pt.(*types.Struct); !ok {
-				b.CodeErrorf(m.Dot.Pos, "pl.buildMember.illegal",
-					"*%s is not a pointer of struct or interface", t)
-				return nil
-			}
-		} else {
-			if tstruct, ok = t.(*types.Struct); !ok {
-				b.CodeErrorf(m.Dot.Pos, "pl.buildMember.illegal",
-					"%s is not a struct or interface", t)
-				return nil
-			}
-		}
-		symTable = tstruct.Syms
-	}
 	name := m.Sub.Lit
-	sym := symTable.Query(name)
-	if sym == nil {
-		b.CodeErrorf(m.Dot.Pos, "pl.buildMember.notFound",
-			"%s has no member named %s", t, name)
+	found := findMemberOf(b, m, t)
+	if found == nil {
 		return nil
-	} else if !syms.IsPublic(name) && sym.Pkg() != b.path {
+	}
+	sym := found.sym
+	if !syms.IsPublic(name) && sym.Pkg() != b.path {
 		b.CodeErrorf(m.Dot.Pos, "pl.buildMember.notPublic",
 			"symbol %s is not public", name)
 		return nil
 	}
 
+	if found.path != nil {
+		obj = buildPromotion(b, obj, m.Sub, found.path)
+		ref = obj.R()
+	}
 	b.refSym(sym, m.Sub.Pos)
 
 	if sym.Type == tast.SymField {
 		t := sym.ObjType.(types.T)
-		r := tast.NewAddressableRef(t)
+		r := tast.NewRef(t)
+		r.Addressable = !isMapIndex(obj)
 		return &tast.MemberExpr{Expr: obj, Sub: m.Sub, Ref: r, Sym: sym}
 	} else if sym.Type == tast.SymFunc {
 		ft := sym.ObjType.(*types.Func)
-		r := tast.NewRef(ft.MethodFunc)
+		if ft.MethodFunc != nil {
+			ft = ft.MethodFunc
+		}
+		r := tast.NewRef(ft)
 		r.Recv = ref
 		return &tast.MemberExpr{Expr: obj, Sub: m.Sub, Ref: r, Sym: sym}
 	}
@@ -0,0 +1,106 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+	"shanhu.io/smlvm/syms"
+)
+
+type pkgNamed struct {
+	name *lexing.Token
+	ast  *ast.TypeDecl  // the AST node
+	sym  *syms.Symbol   // the symbol
+	t    *types.Named   // type
+	pt   *types.Pointer // pointer type
+	deps []string       // depending identifiers
+}
+
+func newPkgNamed(b *builder, d *ast.TypeDecl) *pkgNamed {
+	deps := newStructDeps()
+	deps.add(d.Type)
+	t := types.NewNamed(d.Name.Lit)
+	t.Pkg = b.path
+
+	return &pkgNamed{
+		name: d.Name,
+		ast:  d,
+		deps: deps.list(),
+		t:    t,
+		pt:   types.NewPointer(t),
+	}
+}
+
+func declareNamed(b *builder, d *ast.TypeDecl) *pkgNamed {
+	ret := newPkgNamed(b, d)
+	name := ret.name.Lit
+	pos := ret.name.Pos
+	t := &types.Type{T: ret.t}
+	sym := syms.Make(b.path, name, tast.SymNamed, nil, t, pos)
+	conflict := b.scope.Declare(sym)
+	if conflict != nil {
+		b.CodeErrorf(pos, "pl.declConflict.type",
+			"%s already defined", name)
+		b.CodeErrorf(conflict.Pos, "pl.declConflict.previousPos",
+			"previously defined here as a %s", tast.SymStr(conflict.Type))
+		return nil
+	}
+
+	ret.sym = sym
+	return ret
+}
+
+func declareNamedTypes(b *builder, decls []*ast.TypeDecl) []*pkgNamed {
+	var ret []*pkgNamed
+	for _, d := range decls {
+		pn := declareNamed(b, d)
+		if pn != nil {
+			ret = append(ret, pn)
+		}
+	}
+	return ret
+}
+
+// buildNamed sets the underlying type of a named type. The named types
+// that it depends on must be built already.
+func buildNamed(b *builder, pn *pkgNamed) {
+	t := b.buildType(pn.ast.Type)
+	if t == nil {
+		return
+	}
+	if types.IsNamed(t) && types.Underlying(t) == nil {
+		return // the underlying type has errors
+	}
+	pn.t.T = types.Underlying(t)
+}
+
+// canHaveMethods checks if methods can be declared on named type t.
+// Named pointers and interfaces cannot have methods.
+func canHaveMethods(b *builder, pos *lexing.Pos, t *types.Named) bool {
+	switch t.T.(type) {
+	case *types.Pointer, *types.Interface:
+		b.CodeErrorf(pos, "pl.invalidRecv",
+			"invalid receiver %s, a named pointer or interface", t)
+		return false
+	}
+	return true
+}
+
+// namedOf returns the named type of t, or the named type that t points
+// to. It returns nil if there is no such named type.
+func namedOf(t types.T) *types.Named {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.T
+	}
+	n, _ := t.(*types.Named)
+	return n
+}
+
+func namedSyms(named []*pkgNamed) []*syms.Symbol {
+	ret := make([]*syms.Symbol, 0, len(named))
+	for _, pn := range named {
+		ret = append(ret, pn.sym)
+	}
+	return ret
+}
@@ -0,0 +1,39 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// sameNamedOperands checks that when an operand of a binary operation is
+// of a named type, the other operand is of the same named type.
+func sameNamedOperands(b *builder, opTok *lexing.Token, a, t types.T) bool {
+	if !types.IsNamed(a) && !types.IsNamed(t) {
+		return true
+	}
+	if a == t || types.IsNil(a) || types.IsNil(t) {
+		return true
+	}
+	b.CodeErrorf(opTok.Pos, "pl.invalidOp.typeMismatch",
+		"invalid operation of %s %s %s, mismatched types", a, opTok.Lit, t)
+	return false
+}
+
+// namedOpResult retypes the result of an arithmetic operation on named
+// type t to t. Comparisons keep their bool results.
+func namedOpResult(ret tast.Expr, t types.T) tast.Expr {
+	if !types.IsNamed(t) {
+		return ret
+	}
+	op, ok := ret.(*tast.OpExpr)
+	if !ok || !types.SameType(op.R().T, types.Underlying(t)) {
+		return ret
+	}
+	switch op.Op.Lit {
+	case "==", "!=", ">", "<", ">=", "<=":
+		return ret
+	}
+	op.Ref = tast.NewRef(t)
+	return ret
+}
@@ -66,12 +66,16 @@ func buildUnaryOpExpr(b *builder, expr *ast.OpExpr) tast.Expr {
 	btyp := bref.T
 	if op == "&" {
 		return refAddress(b, opTok, B)
+	} else if op == "<-" {
+		return buildChanRecv(b, opTok, B)
 	} else if types.IsConst(btyp) {
 		return unaryOpConst(b, opTok, B)
 	} else if types.IsInteger(btyp) {
 		return unaryOpInt(b, opTok, B)
 	} else if types.IsBasic(btyp, types.Bool) {
 		return unaryOpBool(b, opTok, B)
+	} else if types.IsBasic(btyp, types.Float32) {
+		return unaryOpFloat(b, opTok, B)
 	}
 
 	b.Errorf(opPos, "invalid unary operator %q", op)
@@ -105,6 +109,12 @@ func buildBinaryOpExpr(b *builder, expr *ast.OpExpr) tast.Expr {
 	}
 	btyp := bref.T
 
+	if va, ok := constString(A); ok {
+		if vb, ok := constString(B); ok {
+			return binaryOpConstString(b, opTok, va, vb, true)
+		}
+	}
+
 	if types.IsConst(atyp) && types.IsConst(btyp) {
 		return binaryOpConst(b, opTok, A, B)
 	}
@@ -140,6 +150,10 @@ func buildBinaryOpExpr(b *builder, expr *ast.OpExpr) tast.Expr {
 			return nil
 		}
 		atyp = btyp
+	} else if _, ok := types.FloatConst(atyp); ok &&
+		types.IsBasic(btyp, types.Float32) {
+		A = tast.NewCast(A, btyp)
+		atyp = btyp
 	} else if c, ok := atyp.(*types.Const); ok {
 		atyp = c.Type
 	}
@@ -150,20 +164,32 @@ func buildBinaryOpExpr(b *builder, expr *ast.OpExpr) tast.Expr {
 			return nil
 		}
 		btyp = atyp
+	} else if _, ok := types.FloatConst(btyp); ok &&
+		types.IsBasic(atyp, types.Float32) {
+		B = tast.NewCast(B, atyp)
+		btyp = atyp
 	} else if c, ok := btyp.(*types.Const); ok {
 		btyp = c.Type
 	}
 
+	if !sameNamedOperands(b, opTok, atyp, btyp) {
+		return nil
+	}
+
 	if ok, t := types.SameBasic(atyp, btyp); ok {
+		var ret tast.Expr
 		switch t {
 		case types.Int, types.Int8, types.Uint, types.Uint8:
-			return binaryOpInt(b, opTok, A, B, t)
+			ret = binaryOpInt(b, opTok, A, B, t)
 		case types.Bool:
-			return binaryOpBool(b, opTok, A, B)
+			ret = binaryOpBool(b, opTok, A, B)
 		case types.Float32:
-			b.Errorf(opPos, "floating point operations not implemented")
-			return nil
+			ret = binaryOpFloat(b, opTok, A, B, t)
 		}
+		if ret != nil {
+			return namedOpResult(ret, atyp)
+		}
+		return nil
 	}
 
 	if types.IsNil(atyp) && types.IsNil(btyp) {
@@ -174,6 +200,12 @@ func buildBinaryOpExpr(b *builder, expr *ast.OpExpr) tast.Expr {
 		return binaryOpPtr(b, opTok, A, B)
 	} else if types.BothSlice(atyp, btyp) {
 		return binaryOpSlice(b, opTok, A, B)
+	} else if types.BothMap(atyp, btyp) {
+		return binaryOpMap(b, opTok, A, B)
+	} else if types.BothChan(atyp, btyp) {
+		return binaryOpPtr(b, opTok, A, B)
+	} else if types.BothInterface(atyp, btyp) {
+		return binaryOpInterface(b, opTok, A, B)
 	}
 
 	b.CodeErrorf(opPos, "pl.invalidOp",
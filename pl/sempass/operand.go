@@ -35,19 +35,12 @@ func buildFloat(b *builder, op *lexing.Token) tast.Expr {
 			"invalid float: %s", e)
 		return nil
 	}
-	if ret != math.Floor(ret) {
-		b.CodeErrorf(
-			op.Pos, "pl.notYetSupported",
-			"float is not yet supported")
+	if math.IsInf(float64(float32(ret)), 0) {
+		b.CodeErrorf(op.Pos, "pl.cannotCast.floatOverFlowed",
+			"float too large to fit in float32")
 		return nil
 	}
-	if ret > math.MaxUint32 {
-		b.CodeErrorf(op.Pos, "pl.cannotCast.integerOverFlowed",
-			"integer too large to fit in 32-bit")
-		return nil
-	}
-	retInt := int64(ret)
-	return tast.NewConst(types.NewNumber(retInt))
+	return tast.NewConst(types.NewConstFloat(ret))
 }
 
 func buildChar(b *builder, op *lexing.Token) tast.Expr {
@@ -59,12 +52,7 @@ func buildChar(b *builder, op *lexing.Token) tast.Expr {
 		b.Errorf(op.Pos, "invalid char in quote: %q", v)
 		return nil
 	}
-	ref, e := types.NewConstInt(int64(v[0]), types.Int8)
-	if e != nil {
-		b.Errorf(op.Pos, "cannot covert char: %q in to int8", v)
-		return nil
-	}
-	return tast.NewConst(ref)
+	return tast.NewConst(types.NewChar(int64(v[0])))
 }
 
 func buildString(b *builder, op *lexing.Token) tast.Expr {
@@ -77,6 +65,15 @@ func buildString(b *builder, op *lexing.Token) tast.Expr {
 	return &tast.Const{Ref: ref}
 }
 
+func buildConstString(b *builder, op *lexing.Token) tast.Expr {
+	v, e := strconv.Unquote(op.Lit)
+	if e != nil {
+		b.Errorf(op.Pos, "invalid string: %s", e)
+		return nil
+	}
+	return tast.NewConst(types.NewConstString(v))
+}
+
 func buildIdent(b *builder, ident *lexing.Token) tast.Expr {
 	s := b.scope.Query(ident.Lit)
 	if s == nil {
@@ -86,19 +83,33 @@ func buildIdent(b *builder, ident *lexing.Token) tast.Expr {
 	}
 
 	b.refSym(s, ident.Pos)
+	if s.Type == tast.SymGeneric {
+		notInstantiated(b, ident.Pos, s)
+		return nil
+	}
 
 	t := s.ObjType.(types.T)
 	switch s.Type {
-	case tast.SymVar, tast.SymField:
+	case tast.SymVar:
+		useVar(b, s, b.callee)
+		ref := tast.NewAddressableRef(t)
+		return &tast.Ident{Token: ident, Ref: ref, Sym: s}
+	case tast.SymField:
+		if !checkNotInLit(b, ident.Pos) {
+			return nil
+		}
 		ref := tast.NewAddressableRef(t)
 		return &tast.Ident{Token: ident, Ref: ref, Sym: s}
 	case tast.SymConst:
+		if v, ok := types.StringConst(t); ok {
+			return newStringLit(v)
+		}
 		if types.IsConst(t) {
 			return tast.NewConst(t)
 		}
 		ref := tast.NewRef(t)
 		return &tast.Ident{Token: ident, Ref: ref, Sym: s}
-	case tast.SymStruct, tast.SymType, tast.SymImport:
+	case tast.SymStruct, tast.SymNamed, tast.SymType, tast.SymImport:
 		ref := tast.NewRef(t)
 		return &tast.Ident{Token: ident, Ref: ref, Sym: s}
 	case tast.SymFunc:
@@ -109,6 +120,9 @@ func buildIdent(b *builder, ident *lexing.Token) tast.Expr {
 			if b.this == nil {
 				panic("this missing")
 			}
+			if !checkNotInLit(b, ident.Pos) {
+				return nil
+			}
 			ref := &tast.Ref{T: t.MethodFunc, Recv: b.this}
 			return &tast.Ident{Token: ident, Ref: ref, Sym: s}
 		}
@@ -129,12 +143,16 @@ func buildConstIdent(b *builder, ident *lexing.Token) tast.Expr {
 	}
 
 	b.refSym(s, ident.Pos)
+	if s.Type == tast.SymGeneric {
+		notInstantiated(b, ident.Pos, s)
+		return nil
+	}
 
 	t := s.ObjType.(types.T)
 	switch s.Type {
 	case tast.SymConst:
 		return tast.NewConst(t)
-	case tast.SymStruct, tast.SymType, tast.SymImport:
+	case tast.SymStruct, tast.SymNamed, tast.SymType, tast.SymImport:
 		ref := tast.NewRef(t)
 		return &tast.Ident{Token: ident, Ref: ref, Sym: s}
 	}
@@ -153,10 +171,10 @@ func buildConstOperand(b *builder, op *ast.Operand) tast.Expr {
 		return buildFloat(b, op.Token)
 	case parse.Ident:
 		return buildConstIdent(b, op.Token)
-	case parse.Char, parse.String:
-		b.CodeErrorf(op.Token.Pos, "pl.notYetSupported",
-			"only num and int consts are implemented")
-		return nil
+	case parse.Char:
+		return buildChar(b, op.Token)
+	case parse.String:
+		return buildConstString(b, op.Token)
 	}
 	b.CodeErrorf(op.Token.Pos, "pl.expectConstExpr", "expect a constant")
 	return nil
@@ -168,6 +186,9 @@ func buildOperand(b *builder, op *ast.Operand) tast.Expr {
 			b.Errorf(op.Token.Pos, "using this out of a method function")
 			return nil
 		}
+		if !checkNotInLit(b, op.Token.Pos) {
+			return nil
+		}
 		return &tast.This{Ref: b.this}
 	}
 
@@ -30,6 +30,15 @@ func buildNamedParaList(b *builder, lst *ast.ParaList) []*types.Arg {
 		if t == nil {
 			return nil
 		}
+		if para.Dots != nil {
+			if i > 0 && ret[i-1].T == nil {
+				b.CodeErrorf(para.Dots.Pos, "pl.variadic.notLast",
+					"can only use ... with the last parameter")
+				return nil
+			}
+			ret[i].T = &types.Slice{T: t}
+			continue
+		}
 
 		// go back and assign types
 		for j := i; j >= 0 && ret[j].T == nil; j-- {
@@ -64,6 +73,9 @@ func buildAnonyParaList(b *builder, lst *ast.ParaList) []*types.Arg {
 		if t == nil {
 			return nil
 		}
+		if para.Dots != nil {
+			t = &types.Slice{T: t}
+		}
 
 		ret[i] = &types.Arg{T: t}
 	}
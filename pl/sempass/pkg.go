@@ -26,6 +26,10 @@ type Pkg struct {
 	Path    string
 	Files   map[string]*ast.File
 	Imports map[string]*builds.Package
+
+	// Golike makes an unlabeled break break out of the innermost switch or
+	// select, like in Go.
+	Golike bool
 }
 
 type symbols struct {
@@ -34,7 +38,13 @@ type symbols struct {
 	methods    []*ast.Func
 	structs    []*ast.Struct
 	interfaces []*ast.Interface
+	named      []*ast.TypeDecl
 	vars       []*ast.VarDecls
+
+	// generic functions and structs, and the methods of generic structs
+	genericFuncs   []*ast.Func
+	genericStructs []*ast.Struct
+	genericMethods []*ast.Func
 }
 
 func (p *Pkg) symbols() *symbols {
@@ -44,17 +54,27 @@ func (p *Pkg) symbols() *symbols {
 		for _, d := range decls {
 			switch d := d.(type) {
 			case *ast.Func:
-				if d.Recv == nil {
+				if d.TypeParams != nil {
+					ret.genericFuncs = append(ret.genericFuncs, d)
+				} else if d.Recv == nil {
 					ret.funcs = append(ret.funcs, d)
+				} else if d.Recv.TypeParams != nil {
+					ret.genericMethods = append(ret.genericMethods, d)
 				} else {
 					ret.methods = append(ret.methods, d)
 				}
 			case *ast.VarDecls:
 				ret.vars = append(ret.vars, d)
 			case *ast.Struct:
-				ret.structs = append(ret.structs, d)
+				if d.TypeParams != nil {
+					ret.genericStructs = append(ret.genericStructs, d)
+				} else {
+					ret.structs = append(ret.structs, d)
+				}
 			case *ast.Interface:
 				ret.interfaces = append(ret.interfaces, d)
+			case *ast.TypeDecl:
+				ret.named = append(ret.named, d)
 			case *ast.ConstDecls:
 				ret.consts = append(ret.consts, d)
 			default:
@@ -127,6 +147,8 @@ func (p *Pkg) Build(scope *syms.Scope) (
 	*tast.Pkg, *dagvis.Graph, []*lexing.Error,
 ) {
 	b := makeBuilder(p.Path, scope)
+	b.golike = p.Golike
+	b.insts = newInstances(p.Path)
 	b.initDeps(p.Files)
 
 	imports := p.buildImports(b, p.Imports)
@@ -151,7 +173,19 @@ func (p *Pkg) Build(scope *syms.Scope) (
 		return nil, nil, errs
 	}
 
-	buildStructs(b, pkgStructs)
+	pkgTypes := declareNamedTypes(b, syms.named)
+	if errs := b.Errs(); errs != nil {
+		return nil, nil, errs
+	}
+
+	generics := declareGenerics(
+		b, syms.genericFuncs, syms.genericStructs, syms.genericMethods,
+	)
+	if errs := b.Errs(); errs != nil {
+		return nil, nil, errs
+	}
+
+	buildStructs(b, pkgStructs, pkgTypes, generics)
 	if errs := b.Errs(); errs != nil {
 		return nil, nil, errs
 	}
@@ -166,7 +200,7 @@ func (p *Pkg) Build(scope *syms.Scope) (
 		return nil, nil, errs
 	}
 
-	pkgMethods := declareMethods(b, syms.methods, pkgStructs)
+	pkgMethods := declareMethods(b, syms.methods, pkgStructs, pkgTypes)
 	if errs := b.Errs(); errs != nil {
 		return nil, nil, errs
 	}
@@ -185,6 +219,14 @@ func (p *Pkg) Build(scope *syms.Scope) (
 	if errs := b.Errs(); errs != nil {
 		return nil, nil, errs
 	}
+	methods = append(methods, buildPromotedMethods(b, pkgStructs)...)
+
+	buildInstances(b)
+	if errs := b.Errs(); errs != nil {
+		return nil, nil, errs
+	}
+	funcs = append(funcs, b.insts.funcs...)
+	methods = append(methods, b.insts.methods...)
 
 	checkUnusedImports(b, imports)
 	if errs := b.Errs(); errs != nil {
@@ -192,14 +234,16 @@ func (p *Pkg) Build(scope *syms.Scope) (
 	}
 
 	depGraph := b.depGraph()
-	structs := structSyms(pkgStructs)
+	structs := append(structSyms(pkgStructs), b.insts.structs...)
 	interfaces := interfaceSyms(pkgInterfaces)
+	named := namedSyms(pkgTypes)
 
 	return &tast.Pkg{
 		Imports:     imports,
 		Consts:      consts,
 		Structs:     structs,
 		Interfaces:  interfaces,
+		Named:       named,
 		Vars:        vars,
 		Funcs:       funcs,
 		Methods:     methods,
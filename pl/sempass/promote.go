@@ -0,0 +1,101 @@
+package sempass
+
+import (
+	"sort"
+
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+	"shanhu.io/smlvm/syms"
+)
+
+// promotedNames lists the names of the methods declared by the structs
+// embedded in t, directly or indirectly.
+func promotedNames(t *types.Struct) []string {
+	names := make(map[string]bool)
+	visited := map[*types.Struct]bool{t: true}
+	var walk func(t *types.Struct)
+	walk = func(t *types.Struct) {
+		for _, f := range t.Fields {
+			if !f.Embedded {
+				continue
+			}
+			s := embeddedStruct(f.T)
+			if visited[s] {
+				continue
+			}
+			visited[s] = true
+			for _, sym := range s.Syms.List() {
+				if sym.Type == tast.SymFunc {
+					names[sym.Name()] = true
+				}
+			}
+			walk(s)
+		}
+	}
+	walk(t)
+
+	var ret []string
+	for name := range names {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// buildPromotedMethod generates a method of struct ps that calls the
+// promoted method m on the embedded field.
+func buildPromotedMethod(b *builder, ps *pkgStruct, m *member) *tast.Func {
+	mt := m.sym.ObjType.(*types.Func).MethodFunc
+	t := types.NewFunc(&types.Arg{T: ps.pt}, mt.Args, mt.Rets)
+	if mt.IsVariadic {
+		t.SetVariadic()
+	}
+	name := m.sym.Name()
+	pos := m.sym.Pos
+	sym := syms.Make(b.path, name, tast.SymFunc, nil, t, pos)
+	ps.t.Promoted.Declare(sym)
+
+	var recv tast.Expr = &tast.This{Ref: tast.NewRef(ps.pt)}
+	for _, f := range m.path {
+		r := tast.NewAddressableRef(f.ObjType.(types.T))
+		recv = &tast.MemberExpr{Expr: recv, Ref: r, Sym: f}
+	}
+	fref := tast.NewRef(mt)
+	fref.Recv = recv.R()
+
+	ret := &tast.Func{Sym: sym, This: ps.pt}
+	args := tast.NewExprList()
+	for _, arg := range mt.Args {
+		s := syms.Make(b.path, arg.Name, tast.SymVar, nil, arg.T, pos)
+		ret.Args = append(ret.Args, s)
+		args.Append(&tast.Ident{Ref: tast.NewAddressableRef(arg.T), Sym: s})
+	}
+	call := &tast.CallExpr{
+		Func: &tast.MemberExpr{Expr: recv, Ref: fref, Sym: m.sym},
+		Args: args,
+		Ref:  tast.NewListRef(mt.RetTypes),
+	}
+	if len(mt.Rets) == 0 {
+		ret.Body = []tast.Stmt{&tast.ExprStmt{Expr: call}}
+	} else {
+		ret.Body = []tast.Stmt{&tast.ReturnStmt{Exprs: call}}
+	}
+	return ret
+}
+
+// buildPromotedMethods generates the methods that the structs promote
+// from their embedded fields, so that the structs can implement
+// interfaces with the promoted methods.
+func buildPromotedMethods(b *builder, structs []*pkgStruct) []*tast.Func {
+	var ret []*tast.Func
+	for _, ps := range structs {
+		for _, name := range promotedNames(ps.t) {
+			m, _ := findMember(ps.t, name)
+			if m == nil || m.path == nil || m.sym.Type != tast.SymFunc {
+				continue
+			}
+			ret = append(ret, buildPromotedMethod(b, ps, m))
+		}
+	}
+	return ret
+}
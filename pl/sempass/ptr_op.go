@@ -15,6 +15,8 @@ func refAddress(b *builder, opTok *lexing.Token, B tast.Expr) tast.Expr {
 		b.CodeErrorf(opPos, "pl.refAdrress.notSingle",
 			"%q on %s", op, bref)
 		return nil
+	} else if _, ok := B.(*tast.StructLit); ok {
+		// taking the address of a struct literal allocates it
 	} else if !bref.Addressable {
 		b.CodeErrorf(opPos, "pl.refAddress.notAddressable",
 			"reading address of non-addressable")
@@ -58,6 +60,15 @@ func binaryOpPtr(b *builder, opTok *lexing.Token, A, B tast.Expr) tast.Expr {
 	return nil
 }
 
+func binaryOpMap(b *builder, opTok *lexing.Token, A, B tast.Expr) tast.Expr {
+	if !types.IsNil(A.R().T) && !types.IsNil(B.R().T) {
+		b.CodeErrorf(opTok.Pos, "pl.invalidOp",
+			"map can only be compared to nil")
+		return nil
+	}
+	return binaryOpPtr(b, opTok, A, B)
+}
+
 func binaryOpSlice(b *builder, opTok *lexing.Token, A, B tast.Expr) tast.Expr {
 	op := opTok.Lit
 	switch op {
@@ -68,3 +79,16 @@ func binaryOpSlice(b *builder, opTok *lexing.Token, A, B tast.Expr) tast.Expr {
 		"%q on slices", op)
 	return nil
 }
+
+func binaryOpInterface(
+	b *builder, opTok *lexing.Token, A, B tast.Expr,
+) tast.Expr {
+	op := opTok.Lit
+	switch op {
+	case "==", "!=":
+		return &tast.OpExpr{A: A, Op: opTok, B: B, Ref: tast.NewRef(types.Bool)}
+	}
+	b.CodeErrorf(opTok.Pos, "pl.invalidExprStmt",
+		"%q on interfaces", op)
+	return nil
+}
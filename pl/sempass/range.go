@@ -0,0 +1,146 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// rangeTypes returns the types of the key and the value when ranging
+// over t. Ranging over a string iterates its bytes. Ranging over a
+// channel has no value, and the key is the received element.
+func rangeTypes(t types.T) (key, value types.T, ok bool) {
+	switch t := types.Underlying(t).(type) {
+	case *types.Map:
+		return t.Key, t.Val, true
+	case *types.Array:
+		return types.Int, t.T, true
+	case *types.Slice:
+		return types.Int, t.T, true
+	case *types.Chan:
+		return t.T, nil, true
+	}
+	return nil, nil, false
+}
+
+func isBlank(e ast.Expr) bool {
+	op, ok := e.(*ast.Operand)
+	return ok && op.Token.Lit == "_"
+}
+
+// defineRange declares the variables of a range clause that uses ":=".
+func defineRange(
+	b *builder, r *ast.RangeClause, ts []types.T,
+) (*tast.Define, []tast.Expr) {
+	idents, bad := buildIdentExprList(b, r.Left)
+	if bad != nil {
+		b.Errorf(ast.ExprPos(bad), "left side of := must be identifier")
+		return nil, nil
+	}
+
+	ret := new(tast.Define)
+	exprs := make([]tast.Expr, len(idents))
+	for i, tok := range idents {
+		if tok.Lit == "_" {
+			continue
+		}
+		s := declareVar(b, tok, ts[i], false)
+		if s == nil {
+			return nil, nil
+		}
+		ret.Left = append(ret.Left, s)
+		ref := tast.NewAddressableRef(ts[i])
+		exprs[i] = &tast.Ident{Token: tok, Ref: ref, Sym: s}
+	}
+	if len(ret.Left) == 0 {
+		b.CodeErrorf(r.Assign.Pos, "pl.range.noNewVar",
+			"no new variables on left side of :=")
+		return nil, nil
+	}
+	return ret, exprs
+}
+
+// assignRange builds the destinations of a range clause that uses "=".
+func assignRange(
+	b *builder, r *ast.RangeClause, ts []types.T,
+) []tast.Expr {
+	exprs := make([]tast.Expr, r.Left.Len())
+	for i, e := range r.Left.Exprs {
+		if isBlank(e) {
+			continue
+		}
+
+		hold := b.lhsSwap(true)
+		dest := b.buildExpr(e)
+		b.lhsRestore(hold)
+		if dest == nil {
+			return nil
+		}
+		if !dest.R().IsSingle() || !assignable(dest, 0) {
+			b.CodeErrorf(ast.ExprPos(e), "pl.cannotAssign.notAddressable",
+				"assigning to non-addressable")
+			return nil
+		}
+		if t := dest.R().T; !types.SameType(t, ts[i]) {
+			b.CodeErrorf(ast.ExprPos(e), "pl.cannotAssign.typeMismatch",
+				"cannot use %s as %s in range", ts[i], t)
+			return nil
+		}
+		exprs[i] = dest
+	}
+	return exprs
+}
+
+func buildRangeStmt(b *builder, stmt *ast.ForStmt) tast.Stmt {
+	r := stmt.Range
+	x := b.buildExpr(r.Expr)
+	if x == nil {
+		return nil
+	}
+
+	if s, ok := types.StringConst(x.R().T); ok {
+		x = newStringLit(s)
+	}
+
+	pos := ast.ExprPos(r.Expr)
+	ref := x.R()
+	key, value, ok := rangeTypes(ref.T)
+	if !ok || !ref.IsSingle() {
+		b.CodeErrorf(pos, "pl.range.invalid", "cannot range over %s", ref)
+		return nil
+	}
+
+	b.scope.Push()
+	defer scopePopAndCheck(b)
+
+	ret := &tast.RangeStmt{X: x}
+	var exprs []tast.Expr
+	if n := r.Left.Len(); n > 2 {
+		b.CodeErrorf(r.Assign.Pos, "pl.range.tooManyVars",
+			"range clause permits at most two variables")
+		return nil
+	} else if n == 2 && value == nil {
+		b.CodeErrorf(r.Assign.Pos, "pl.range.tooManyVars",
+			"range over %s permits only one variable", ref)
+		return nil
+	} else if n > 0 {
+		ts := []types.T{key, value}
+		if r.Assign.Lit == ":=" {
+			ret.Define, exprs = defineRange(b, r, ts)
+		} else {
+			exprs = assignRange(b, r, ts)
+		}
+		if exprs == nil {
+			return nil
+		}
+		ret.Key = exprs[0]
+		if n > 1 {
+			ret.Value = exprs[1]
+		}
+	}
+
+	b.nloop++
+	ret.Body = buildBlock(b, stmt.Body)
+	b.nloop--
+	return ret
+}
@@ -0,0 +1,82 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+)
+
+// findRecv finds the receive in the right side of an assignment or a
+// define in a select case.
+func findRecv(e tast.Expr) *tast.ChanRecv {
+	switch e := e.(type) {
+	case *tast.ChanRecv:
+		return e
+	case *tast.Cast:
+		return findRecv(e.From)
+	case *tast.ExprList:
+		if e.Len() == 1 {
+			return findRecv(e.Exprs[0])
+		}
+	}
+	return nil
+}
+
+func buildSelectComm(b *builder, c *tast.SelectCase, s ast.Stmt) bool {
+	switch s := s.(type) {
+	case *ast.SendStmt:
+		c.Send = buildSendStmt(b, s)
+		return c.Send != nil
+	case *ast.ExprStmt:
+		e := b.buildExpr(s.Expr)
+		if e == nil {
+			return false
+		}
+		c.Recv = findRecv(e)
+	case *ast.DefineStmt:
+		c.Comm = buildDefineStmt(b, s)
+		if c.Comm == nil {
+			return false
+		}
+		c.Recv = findRecv(c.Comm.(*tast.Define).Right)
+	case *ast.AssignStmt:
+		c.Comm = buildAssignStmt(b, s)
+		if c.Comm == nil {
+			return false
+		}
+		c.Recv = findRecv(c.Comm.(*tast.AssignStmt).Right)
+	}
+	if c.Recv == nil {
+		b.Errorf(nil, "bug: receive not found in select case")
+		return false
+	}
+	return true
+}
+
+func buildSelectStmt(b *builder, stmt *ast.SelectStmt) tast.Stmt {
+	ret := new(tast.SelectStmt)
+	hasDefault := false
+	defer enterSwitch(b)()
+	for _, c := range stmt.Cases {
+		if c.Comm == nil {
+			if hasDefault {
+				b.CodeErrorf(c.Kw.Pos, "pl.select.multiDefault",
+					"multiple defaults in select")
+				return nil
+			}
+			hasDefault = true
+		}
+
+		b.scope.Push()
+		sc := new(tast.SelectCase)
+		ok := c.Comm == nil || buildSelectComm(b, sc, c.Comm)
+		if ok {
+			sc.Stmts = buildStmts(b, c.Stmts)
+		}
+		scopePopAndCheck(b)
+		if !ok {
+			return nil
+		}
+		ret.Cases = append(ret.Cases, sc)
+	}
+	return ret
+}
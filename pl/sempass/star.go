@@ -26,7 +26,7 @@ func buildStarExpr(b *builder, expr *ast.StarExpr) tast.Expr {
 		return tast.NewType(&types.Pointer{T: t.T})
 	}
 
-	t, ok := addrRef.T.(*types.Pointer)
+	t, ok := types.Underlying(addrRef.T).(*types.Pointer)
 	if !ok {
 		b.CodeErrorf(opPos, "pl.star.onNonPointer", "* on non-pointer")
 		return nil
@@ -18,6 +18,10 @@ func buildStmt(b *builder, stmt ast.Stmt) tast.Stmt {
 		return buildContinueStmt(b, stmt)
 	case *ast.BreakStmt:
 		return buildBreakStmt(b, stmt)
+	case *ast.GotoStmt:
+		return buildGotoStmt(b, stmt)
+	case *ast.LabeledStmt:
+		return buildLabeledStmt(b, stmt)
 	case *ast.DefineStmt:
 		return buildDefineStmt(b, stmt)
 	case *ast.VarDecls:
@@ -28,6 +32,17 @@ func buildStmt(b *builder, stmt ast.Stmt) tast.Stmt {
 		return buildAssignStmt(b, stmt)
 	case *ast.ReturnStmt:
 		return buildReturnStmt(b, stmt)
+	case *ast.DeferStmt:
+		return buildDeferStmt(b, stmt)
+	case *ast.GoStmt:
+		return buildGoStmt(b, stmt)
+	case *ast.SendStmt:
+		if s := buildSendStmt(b, stmt); s != nil {
+			return s
+		}
+		return nil
+	case *ast.SelectStmt:
+		return buildSelectStmt(b, stmt)
 	case *ast.BlockStmt:
 		return buildBlockStmt(b, stmt)
 	case *ast.IfStmt:
@@ -36,6 +51,8 @@ func buildStmt(b *builder, stmt ast.Stmt) tast.Stmt {
 		return buildForStmt(b, stmt)
 	case *ast.SwitchStmt:
 		return buildSwitchStmt(b, stmt)
+	case *ast.TypeSwitchStmt:
+		return buildTypeSwitchStmt(b, stmt)
 	}
 
 	b.Errorf(nil, "invalid or not implemented: %T", stmt)
@@ -0,0 +1,62 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// constString returns the value of a string constant. The expression can
+// either be a string constant in a const expression, or a string literal.
+func constString(e tast.Expr) (string, bool) {
+	r := e.R()
+	if !r.IsSingle() {
+		return "", false
+	}
+	if s, ok := types.StringConst(r.T); ok {
+		return s, true
+	}
+	if r.T == types.String {
+		s, ok := r.ConstValue.(string)
+		return s, ok
+	}
+	return "", false
+}
+
+// newStringLit creates a string literal that can be used as a string.
+func newStringLit(s string) tast.Expr {
+	return &tast.Const{Ref: tast.NewConstRef(types.String, s)}
+}
+
+func binaryOpConstString(
+	b *builder, opTok *lexing.Token, va, vb string, lit bool,
+) tast.Expr {
+	op := opTok.Lit
+	switch op {
+	case "+":
+		if lit {
+			return newStringLit(va + vb)
+		}
+		return tast.NewConst(types.NewConstString(va + vb))
+	case "==", "!=", ">", "<", ">=", "<=":
+		// TODO(yumuzi234): will change into a const bool
+		return &tast.OpExpr{
+			A:   tast.NewConst(types.NewConstString(va)),
+			Op:  opTok,
+			B:   tast.NewConst(types.NewConstString(vb)),
+			Ref: tast.NewRef(types.Bool),
+		}
+	}
+
+	b.CodeErrorf(opTok.Pos, "pl.invalidOp", "%q on string consts", op)
+	return nil
+}
+
+// constLen returns the length of a constant string as a number.
+func constLen(e tast.Expr) tast.Expr {
+	s, ok := constString(e)
+	if !ok {
+		return nil
+	}
+	return tast.NewConst(types.NewNumber(int64(len(s))))
+}
@@ -17,9 +17,10 @@ type pkgStruct struct {
 	deps []string       // depending identifiers
 }
 
-func newPkgStruct(s *ast.Struct) *pkgStruct {
+func newPkgStruct(b *builder, s *ast.Struct) *pkgStruct {
 	deps := listStructDeps(s)
 	t := types.NewStruct(s.Name.Lit)
+	t.Pkg = b.path
 
 	return &pkgStruct{
 		name: s.Name,
@@ -31,7 +32,7 @@ func newPkgStruct(s *ast.Struct) *pkgStruct {
 }
 
 func declareStruct(b *builder, s *ast.Struct) *pkgStruct {
-	ret := newPkgStruct(s)
+	ret := newPkgStruct(b, s)
 	name := ret.name.Lit
 	pos := ret.name.Pos
 	t := &types.Type{T: ret.t}
@@ -49,18 +50,26 @@ func declareStruct(b *builder, s *ast.Struct) *pkgStruct {
 	return ret
 }
 
-func sortStructs(b *builder, m map[string]*pkgStruct) []*pkgStruct {
-	s := newTopoSorter("struct", "pl.circDep.struct")
-	for name, ps := range m {
-		s.addNode(name, ps.name, ps.deps)
+// sortTypes sorts the structs and the named types of a package, so that
+// the size of a type is known when it is used as a field or as an
+// underlying type.
+func sortTypes(
+	b *builder, structs []*pkgStruct, named []*pkgNamed,
+	generics []*generic,
+) []string {
+	s := newTopoSorter("type", "pl.circDep.struct")
+	for _, ps := range structs {
+		s.addNode(ps.name.Lit, ps.name, ps.deps)
 	}
-
-	order := s.sort(b)
-	ret := make([]*pkgStruct, 0, len(order))
-	for _, name := range order {
-		ret = append(ret, m[name])
+	for _, pn := range named {
+		s.addNode(pn.name.Lit, pn.name, pn.deps)
 	}
-	return ret
+	for _, g := range generics {
+		if g.s != nil {
+			s.addNode(g.name.Lit, g.name, genericStructDeps(g))
+		}
+	}
+	return s.sort(b)
 }
 
 func buildFields(b *builder, ps *pkgStruct) {
@@ -73,39 +82,78 @@ func buildFields(b *builder, ps *pkgStruct) {
 			continue
 		}
 
-		for _, id := range f.Idents.Idents {
-			name := id.Lit
-			field := &types.Field{Name: name, T: ft}
-			sym := syms.Make(b.path, name, tast.SymField, field, ft, id.Pos)
-			conflict := t.Syms.Declare(sym)
-			if conflict != nil {
-				b.CodeErrorf(id.Pos, "pl.declConflict.field",
-					"field %s already defined", id.Lit)
-				b.CodeErrorf(conflict.Pos,
-					"pl.declConflict.previousPos",
-					"previously defined here")
+		if f.Idents == nil {
+			pos := ast.ExprPos(f.Type)
+			name := embeddedName(b, pos, ft)
+			if name == "" {
 				continue
 			}
+			field := &types.Field{Name: name, T: ft, Embedded: true}
+			declareField(b, t, field, pos)
+			continue
+		}
 
-			t.AddField(field)
+		for _, id := range f.Idents.Idents {
+			field := &types.Field{Name: id.Lit, T: ft}
+			declareField(b, t, field, id.Pos)
 		}
 	}
 }
 
+func declareField(
+	b *builder, t *types.Struct, field *types.Field, pos *lexing.Pos,
+) {
+	name := field.Name
+	sym := syms.Make(b.path, name, tast.SymField, field, field.T, pos)
+	conflict := t.Syms.Declare(sym)
+	if conflict != nil {
+		b.CodeErrorf(pos, "pl.declConflict.field",
+			"field %s already defined", name)
+		b.CodeErrorf(conflict.Pos,
+			"pl.declConflict.previousPos",
+			"previously defined here")
+		return
+	}
+
+	t.AddField(field)
+}
+
 func declareStructs(b *builder, structs []*ast.Struct) []*pkgStruct {
-	m := make(map[string]*pkgStruct)
+	var ret []*pkgStruct
 	for _, s := range structs {
 		ps := declareStruct(b, s)
 		if ps != nil {
-			m[ps.name.Lit] = ps
+			ret = append(ret, ps)
 		}
 	}
-	ret := sortStructs(b, m)
 	return ret
 }
 
-func buildStructs(b *builder, pkgstructs []*pkgStruct) {
-	for _, ps := range pkgstructs {
-		buildFields(b, ps)
+// buildStructs builds the fields of the structs and the underlying types
+// of the named types. The generic structs are only sorted, as their
+// instances are built when used.
+func buildStructs(
+	b *builder, structs []*pkgStruct, named []*pkgNamed,
+	generics []*generic,
+) {
+	order := sortTypes(b, structs, named, generics)
+	if b.Errs() != nil {
+		return
+	}
+
+	ms := make(map[string]*pkgStruct)
+	for _, ps := range structs {
+		ms[ps.name.Lit] = ps
+	}
+	mn := make(map[string]*pkgNamed)
+	for _, pn := range named {
+		mn[pn.name.Lit] = pn
+	}
+	for _, name := range order {
+		if ps := ms[name]; ps != nil {
+			buildFields(b, ps)
+		} else if pn := mn[name]; pn != nil {
+			buildNamed(b, pn)
+		}
 	}
 }
@@ -29,6 +29,11 @@ func (d *structDeps) add(t ast.Expr) {
 		if t.Len != nil {
 			d.add(t.Type)
 		}
+	case *ast.InstExpr:
+		d.add(t.Generic)
+		for _, arg := range t.Types.Exprs {
+			d.add(arg)
+		}
 	}
 }
 
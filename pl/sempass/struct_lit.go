@@ -0,0 +1,209 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/parse"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+	"shanhu.io/smlvm/syms"
+)
+
+func buildStructLitValue(
+	b *builder, expr ast.Expr, f *types.Field,
+) tast.Expr {
+	v := buildElem(b, expr, f.T)
+	if v == nil {
+		return nil
+	}
+
+	pos := ast.ExprPos(expr)
+	ref := v.R()
+	if !ref.IsSingle() {
+		b.CodeErrorf(pos, "pl.structLit.notSingle",
+			"value of field %s must be a single value", f.Name)
+		return nil
+	}
+	ok, needCast := canAssign(b, pos, f.T, ref.T, "struct literal")
+	if !ok {
+		return nil
+	}
+	if needCast {
+		return tast.NewCast(v, f.T)
+	}
+	return v
+}
+
+func buildStructLitKeyed(
+	b *builder, t *types.Struct, ret *tast.StructLit, exprs []ast.Expr,
+) bool {
+	fields := make(map[*types.Field]bool)
+	for _, expr := range exprs {
+		kv, ok := expr.(*ast.KeyValueExpr)
+		if !ok {
+			b.CodeErrorf(ast.ExprPos(expr), "pl.structLit.mixed",
+				"mixture of field:value and value in struct literal")
+			return false
+		}
+		key, ok := kv.Key.(*ast.Operand)
+		if !ok || key.Token.Type != parse.Ident {
+			b.CodeErrorf(ast.ExprPos(kv.Key), "pl.structLit.badField",
+				"invalid field name in struct literal")
+			return false
+		}
+
+		name := key.Token.Lit
+		pos := key.Token.Pos
+		sym := t.Syms.Query(name)
+		if sym == nil || sym.Type != tast.SymField {
+			b.CodeErrorf(pos, "pl.structLit.unknownField",
+				"%s has no field named %s", t, name)
+			return false
+		} else if !syms.IsPublic(name) && sym.Pkg() != b.path {
+			b.CodeErrorf(pos, "pl.structLit.notPublic",
+				"field %s of %s is not public", name, t)
+			return false
+		}
+		b.refSym(sym, pos)
+
+		f := sym.Obj.(*types.Field)
+		if fields[f] {
+			b.CodeErrorf(pos, "pl.structLit.duplicateField",
+				"duplicate field %s in struct literal", name)
+			return false
+		}
+		fields[f] = true
+
+		v := buildStructLitValue(b, kv.Value, f)
+		if v == nil {
+			return false
+		}
+		ret.Fields = append(ret.Fields, f)
+		ret.Exprs = append(ret.Exprs, v)
+	}
+	return true
+}
+
+func buildStructLitList(
+	b *builder, lit *ast.StructLiteral, t *types.Struct,
+	ret *tast.StructLit, exprs []ast.Expr,
+) bool {
+	for i, expr := range exprs {
+		if _, ok := expr.(*ast.KeyValueExpr); ok {
+			b.CodeErrorf(ast.ExprPos(expr), "pl.structLit.mixed",
+				"mixture of field:value and value in struct literal")
+			return false
+		}
+		if i >= len(t.Fields) {
+			b.CodeErrorf(ast.ExprPos(expr), "pl.structLit.tooManyValues",
+				"too many values in %s literal", t)
+			return false
+		}
+
+		f := t.Fields[i]
+		sym := t.Syms.Query(f.Name)
+		if sym != nil && !syms.IsPublic(f.Name) && sym.Pkg() != b.path {
+			b.CodeErrorf(ast.ExprPos(expr), "pl.structLit.notPublic",
+				"implicit assignment of field %s of %s, "+
+					"which is not public", f.Name, t)
+			return false
+		}
+
+		v := buildStructLitValue(b, expr, f)
+		if v == nil {
+			return false
+		}
+		ret.Fields = append(ret.Fields, f)
+		ret.Exprs = append(ret.Exprs, v)
+	}
+
+	if len(exprs) < len(t.Fields) {
+		b.CodeErrorf(lit.Rbrace.Pos, "pl.structLit.missingField",
+			"too few values in %s literal, missing field %s",
+			t, t.Fields[len(exprs)].Name)
+		return false
+	}
+	return true
+}
+
+func buildStructLit(b *builder, lit *ast.StructLiteral) tast.Expr {
+	hold := b.lhsSwap(false)
+	defer b.lhsRestore(hold)
+
+	st := b.buildType(lit.Type)
+	if st == nil {
+		return nil
+	}
+	return buildStructLitOf(b, st, lit)
+}
+
+// buildStructLitOf builds the literal as a literal of type st. A struct
+// literal syntax with a named type of an array, a slice or a map builds
+// a literal of that type.
+func buildStructLitOf(
+	b *builder, st types.T, lit *ast.StructLiteral,
+) tast.Expr {
+	switch types.Underlying(st).(type) {
+	case *types.Array, *types.Slice:
+		return buildArrayLitOf(b, st, lit.Fields)
+	case *types.Map:
+		return buildMapLitOf(b, st, lit.Fields)
+	}
+	t, ok := types.Underlying(st).(*types.Struct)
+	if !ok {
+		b.CodeErrorf(ast.ExprPos(lit), "pl.structLit.notStruct",
+			"%s is not a struct type", st)
+		return nil
+	}
+
+	ret := &tast.StructLit{Ref: tast.NewRef(st)}
+	if lit.Fields == nil {
+		return ret
+	}
+
+	exprs := lit.Fields.Exprs
+	if _, ok := exprs[0].(*ast.KeyValueExpr); ok {
+		ok = buildStructLitKeyed(b, t, ret, exprs)
+	} else {
+		ok = buildStructLitList(b, lit, t, ret, exprs)
+	}
+	if !ok {
+		return nil
+	}
+	return ret
+}
+
+// buildElem builds a key, a value or a field of a literal, where the
+// element is of type t. The element can be a literal with the type
+// elided, which is the address of a struct literal for a pointer t.
+func buildElem(b *builder, expr ast.Expr, t types.T) tast.Expr {
+	lit, ok := expr.(*ast.StructLiteral)
+	if !ok || lit.Type != nil {
+		return b.buildExpr(expr)
+	}
+
+	p, isPtr := types.Underlying(t).(*types.Pointer)
+	lt := t
+	if isPtr {
+		lt = p.T
+	}
+	ok = false
+	switch types.Underlying(lt).(type) {
+	case *types.Struct:
+		ok = true
+	case *types.Array, *types.Slice, *types.Map:
+		ok = !isPtr
+	}
+	if !ok {
+		b.CodeErrorf(lit.Lbrace.Pos, "pl.compositeLit.missingType",
+			"missing type in the literal of %s", t)
+		return nil
+	}
+
+	v := buildStructLitOf(b, lt, lit)
+	if v == nil || !isPtr {
+		return v
+	}
+	op := &lexing.Token{Type: parse.Operator, Lit: "&", Pos: lit.Lbrace.Pos}
+	return refAddress(b, op, v)
+}
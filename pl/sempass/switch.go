@@ -6,13 +6,25 @@ import (
 	"shanhu.io/smlvm/pl/types"
 )
 
+// enterSwitch enters a switch or a select, and returns the function
+// that leaves it.
+func enterSwitch(b *builder) func() {
+	if !b.golike {
+		return func() {}
+	}
+	b.nswitch++
+	return func() { b.nswitch-- }
+}
+
 func buildSwitchStmt(b *builder, stmt *ast.SwitchStmt) tast.Stmt {
 	e := buildSwitchExpr(b, stmt.Expr)
 	if e == nil {
 		return nil
 	}
+	defer enterSwitch(b)()
+
 	var cases []*tast.Case
-	m := make(map[int64][]ast.Expr)
+	m := make(map[interface{}][]ast.Expr)
 	for _, c := range stmt.Cases {
 		ret := buildCase(b, m, c, e.Type())
 		cases = append(cases, ret)
@@ -22,7 +34,7 @@ func buildSwitchStmt(b *builder, stmt *ast.SwitchStmt) tast.Stmt {
 		if len(exprs) > 1 {
 			for _, e := range exprs {
 				b.CodeErrorf(ast.ExprPos(e), "pl.caseExpr.dulplicated",
-					"dulplicated case const, %d", v)
+					"dulplicated case const, %#v", v)
 			}
 		}
 	}
@@ -48,16 +60,19 @@ func buildSwitchExpr(b *builder, expr ast.Expr) tast.Expr {
 		return nil
 	}
 
-	if !types.IsInteger(exprRef.Type()) {
+	if !types.IsInteger(exprRef.Type()) && !isString(exprRef.Type()) {
 		pos := ast.ExprPos(expr)
 		b.CodeErrorf(pos, "pl.swithExpr.notYetSupported",
-			"only integer is support for case now, got %s", exprRef)
+			"only integer and string are supported for switch, got %s",
+			exprRef)
 		return nil
 	}
 	return e
 }
 
-func buildCase(b *builder, m map[int64][]ast.Expr,
+func isString(t types.T) bool { return types.SameType(t, types.String) }
+
+func buildCase(b *builder, m map[interface{}][]ast.Expr,
 	c *ast.Case, t types.T) *tast.Case {
 	var e tast.Expr
 	if c.Kw.Lit == "case" {
@@ -77,7 +92,7 @@ func buildCase(b *builder, m map[int64][]ast.Expr,
 	return &tast.Case{Expr: e, Stmts: stmts, Fallthrough: c.Fallthrough != nil}
 }
 
-func buildCaseExpr(b *builder, m map[int64][]ast.Expr,
+func buildCaseExpr(b *builder, m map[interface{}][]ast.Expr,
 	c *ast.Case, t types.T) tast.Expr {
 	e := b.buildExpr(c.Expr)
 	pos := ast.ExprPos(c.Expr)
@@ -92,16 +107,31 @@ func buildCaseExpr(b *builder, m map[int64][]ast.Expr,
 			"expect single expression for case, got %s", r)
 		return nil
 	}
+	if isString(t) {
+		s, ok := constString(e)
+		if !ok {
+			b.CodeErrorf(pos, "pl.caseExpr.notConst",
+				"only const string value is allowed for case, got %s", r)
+			return nil
+		}
+		m[s] = append(m[s], c.Expr)
+		return newStringLit(s)
+	}
+
 	v, ok := types.NumConst(r.Type())
-	if !ok {
+	if ok {
+		e = numCast(b, pos, v, e, t)
+		if e == nil {
+			return nil
+		}
+	} else if ct, isConst := r.Type().(*types.Const); isConst &&
+		types.IsInteger(ct.Type) && types.SameType(ct.Type, t) {
+		v = ct.Value.(int64) // typed const
+	} else {
 		b.CodeErrorf(pos, "pl.caseExpr.notConst",
 			"only const integer value is allowed for case, got %s", r)
 		return nil
 	}
-	if !types.IsConst(t) {
-		e = numCast(b, pos, v, e, t)
-		// TODO(yumuzi234): if both switch and case expr is a const type
-	}
 	m[v] = append(m[v], c.Expr)
 
 	return e
@@ -51,9 +51,19 @@ func (u *symUses) symUse(expr ast.Expr) {
 		u.symUse(expr.Array)
 		u.symUse(expr.Index)
 		u.symUse(expr.IndexEnd)
+	case *ast.InstExpr:
+		u.symUse(expr.Generic)
+		for _, t := range expr.Types.Exprs {
+			u.symUse(t)
+		}
 	case *ast.ArrayTypeExpr:
 		u.symUse(expr.Len)
 		u.symUse(expr.Type)
+	case *ast.MapTypeExpr:
+		u.symUse(expr.Key)
+		u.symUse(expr.Val)
+	case *ast.ChanTypeExpr:
+		u.symUse(expr.Elem)
 	case *ast.FuncTypeExpr:
 		sig := expr.FuncSig
 		for _, arg := range sig.Args.Paras {
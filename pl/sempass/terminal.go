@@ -4,65 +4,91 @@ import (
 	"shanhu.io/smlvm/pl/ast"
 )
 
-func isBlockTerminal(block *ast.Block) bool {
-	stmts := block.Stmts
+func isBlockTerminal(b *builder, block *ast.Block) bool {
+	return isStmtsTerminal(b, block.Stmts)
+}
+
+func isStmtsTerminal(b *builder, stmts []ast.Stmt) bool {
 	nstmt := len(stmts)
 	if nstmt == 0 {
 		return false
 	}
-	return isTerminal(stmts[nstmt-1])
+	return isTerminal(b, stmts[nstmt-1])
 }
 
-func blockHasBreak(b *ast.Block) bool {
-	for _, s := range b.Stmts {
-		if hasBreak(s) {
+// stmtsHaveBreak checks if a list of statements breaks out of the
+// enclosing loop or switch. Breaks without a label are only counted when
+// not nested in another loop, or in golike mode, in another switch or
+// select, and breaks to the labels in inner are not counted.
+func stmtsHaveBreak(
+	b *builder, stmts []ast.Stmt, inner map[string]bool, nested bool,
+) bool {
+	for _, s := range stmts {
+		if hasBreak(b, s, inner, nested) {
 			return true
 		}
 	}
 	return false
 }
 
-func hasBreak(stmt ast.Stmt) bool {
+func hasBreak(
+	b *builder, stmt ast.Stmt, inner map[string]bool, nested bool,
+) bool {
 	switch stmt := stmt.(type) {
-	case *ast.BlockStmt:
-		return blockHasBreak(stmt.Block)
-	case *ast.Block:
-		return blockHasBreak(stmt)
 	case *ast.BreakStmt:
-		return true
+		if stmt.Label == nil {
+			return !nested
+		}
+		return !inner[stmt.Label.Lit]
+	case *ast.LabeledStmt:
+		inner[stmt.Label.Lit] = true
+		return hasBreak(b, stmt.Stmt, inner, nested)
 	case *ast.ForStmt:
-		// TODO(h8liu): need to change this if labeled break is added
-		return false
-	case *ast.IfStmt:
-		if hasBreak(stmt.Body) {
+		return stmtsHaveBreak(b, stmt.Body.Stmts, inner, true)
+	case *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+		if b.golike {
+			nested = true
+		}
+	}
+	for _, stmts := range subStmts(stmt) {
+		if stmtsHaveBreak(b, stmts, inner, nested) {
 			return true
 		}
-		selse := stmt.Else
-		for selse != nil {
-			for _, s := range selse.Body.Stmts {
-				if hasBreak(s) {
-					return true
-				}
-			}
-			selse = selse.Next
+	}
+	return false
+}
+
+// isCasesTerminal checks if a switch with the cases is terminal: it has
+// a default case, no break breaks out of it, and every case ends with a
+// terminal statement or a fallthrough.
+func isCasesTerminal(b *builder, cases []*ast.Case) bool {
+	hasDefault := false
+	inner := make(map[string]bool)
+	for _, c := range cases {
+		if c.Kw.Lit == "default" {
+			hasDefault = true
+		}
+		if stmtsHaveBreak(b, c.Stmts, inner, false) {
+			return false
+		}
+		if c.Fallthrough == nil && !isStmtsTerminal(b, c.Stmts) {
+			return false
 		}
-		return false
-	default:
-		return false
 	}
+	return hasDefault
 }
 
-func isTerminal(stmt ast.Stmt) bool {
+func isTerminal(b *builder, stmt ast.Stmt) bool {
 	switch stmt := stmt.(type) {
 	case *ast.BlockStmt:
-		return isBlockTerminal(stmt.Block)
+		return isBlockTerminal(b, stmt.Block)
 	case *ast.Block:
-		return isBlockTerminal(stmt)
+		return isBlockTerminal(b, stmt)
 	case *ast.IfStmt:
 		if stmt.Else == nil {
 			return false
 		}
-		if !isTerminal(stmt.Body) {
+		if !isTerminal(b, stmt.Body) {
 			return false
 		}
 		selse := stmt.Else
@@ -71,23 +97,36 @@ func isTerminal(stmt ast.Stmt) bool {
 				// else if with no further else
 				return false
 			}
-			if !isBlockTerminal(selse.Body) {
+			if !isBlockTerminal(b, selse.Body) {
 				return false
 			}
 			selse = selse.Next
 		}
 		return true
 	case *ast.ForStmt:
-		if stmt.Cond != nil {
+		if stmt.Cond != nil || stmt.Range != nil {
 			return false
 		}
-		for _, s := range stmt.Body.Stmts {
-			if hasBreak(s) {
+		inner := make(map[string]bool)
+		return !stmtsHaveBreak(b, stmt.Body.Stmts, inner, false)
+	case *ast.SwitchStmt:
+		return isCasesTerminal(b, stmt.Cases)
+	case *ast.TypeSwitchStmt:
+		return isCasesTerminal(b, stmt.Cases)
+	case *ast.SelectStmt:
+		inner := make(map[string]bool)
+		for _, c := range stmt.Cases {
+			if stmtsHaveBreak(b, c.Stmts, inner, false) {
+				return false
+			}
+			if !isStmtsTerminal(b, c.Stmts) {
 				return false
 			}
 		}
 		return true
-	case *ast.ReturnStmt:
+	case *ast.LabeledStmt:
+		return isTerminal(b, stmt.Stmt)
+	case *ast.ReturnStmt, *ast.GotoStmt:
 		return true
 	default:
 		return false
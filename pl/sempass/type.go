@@ -31,14 +31,26 @@ func buildFuncType(
 		rets = []*types.Arg{{T: retType}}
 	}
 
+	var ret *types.Func
 	if recv != nil {
 		r := &types.Arg{Name: thisName, T: recv}
-		return types.NewFunc(r, args, rets)
+		ret = types.NewFunc(r, args, rets)
+	} else {
+		ret = types.NewFunc(nil, args, rets)
+	}
+	if f.Args.Variadic() {
+		ret.SetVariadic()
 	}
-	return types.NewFunc(nil, args, rets)
+	return ret
 }
 
 func buildArrayType(b *builder, expr *ast.ArrayTypeExpr) types.T {
+	if expr.Dots != nil {
+		b.CodeErrorf(expr.Dots.Pos, "pl.arrayType.dotsLen",
+			"[...] array outside of a literal")
+		return nil
+	}
+
 	t := buildType(b, expr.Type)
 	if t == nil {
 		return nil
@@ -86,6 +98,24 @@ func buildArrayType(b *builder, expr *ast.ArrayTypeExpr) types.T {
 	return &types.Array{T: t, N: int32(v)}
 }
 
+func buildMapType(b *builder, expr *ast.MapTypeExpr) types.T {
+	key := buildType(b, expr.Key)
+	if key == nil {
+		return nil
+	}
+	val := buildType(b, expr.Val)
+	if val == nil {
+		return nil
+	}
+
+	if !types.IsMapKey(key) {
+		b.CodeErrorf(ast.ExprPos(expr.Key), "pl.invalidMapKey",
+			"invalid map key type %s", key)
+		return nil
+	}
+	return &types.Map{Key: key, Val: val}
+}
+
 func buildPkgRef(b *builder, ident *lexing.Token) *types.Pkg {
 	s := b.scope.Query(ident.Lit)
 	if s == nil {
@@ -136,6 +166,16 @@ func buildType(b *builder, expr ast.Expr) types.T {
 		return buildType(b, expr.Expr)
 	case *ast.FuncTypeExpr:
 		return buildFuncType(b, nil, expr.FuncSig)
+	case *ast.MapTypeExpr:
+		return buildMapType(b, expr)
+	case *ast.ChanTypeExpr:
+		return buildChanType(b, expr)
+	case *ast.InstExpr:
+		return buildInstType(b, expr.Generic, expr.Types.Exprs)
+	case *ast.IndexExpr:
+		if expr.Colon == nil {
+			return buildInstType(b, expr.Array, []ast.Expr{expr.Index})
+		}
 	case *ast.MemberExpr:
 		op, ok := expr.Expr.(*ast.Operand)
 		if !ok {
@@ -157,8 +197,8 @@ func buildType(b *builder, expr ast.Expr) types.T {
 			return nil
 		}
 
-		if s.Type != tast.SymStruct {
-			b.Errorf(expr.Sub.Pos, "symbol %s is a %s, not a struct",
+		if s.Type != tast.SymStruct && s.Type != tast.SymNamed {
+			b.Errorf(expr.Sub.Pos, "symbol %s is a %s, not a type",
 				name, tast.SymStr(s.Type),
 			)
 			return nil
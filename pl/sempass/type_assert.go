@@ -0,0 +1,73 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// buildAssertee builds the interface value of a type assertion or a type
+// switch.
+func buildAssertee(b *builder, expr ast.Expr) (tast.Expr, *types.Interface) {
+	x := b.buildExpr(expr)
+	if x == nil {
+		return nil, nil
+	}
+	pos := ast.ExprPos(expr)
+	r := x.R()
+	if !r.IsSingle() {
+		b.CodeErrorf(pos, "pl.typeAssert.notSingle",
+			"expect a single value for type assertion, got %s", r)
+		return nil, nil
+	}
+	i, ok := types.Underlying(r.Type()).(*types.Interface)
+	if !ok {
+		b.CodeErrorf(pos, "pl.typeAssert.notInterface",
+			"%s is not an interface", r)
+		return nil, nil
+	}
+	return x, i
+}
+
+// checkAssertType checks if an interface value of i might hold a value of
+// type t. Only struct pointers can be asserted. Asserting to an interface
+// would need the method sets of the dynamic types at runtime, which the
+// vtables do not have, so neither x.(J) nor a type switch case of an
+// interface J is supported.
+func checkAssertType(
+	b *builder, pos *lexing.Pos, i *types.Interface, t types.T,
+) bool {
+	if _, ok := types.Underlying(t).(*types.Interface); ok {
+		b.CodeErrorf(pos, "pl.typeAssert.toInterface",
+			"type assertion to interface %s is not supported, "+
+				"only struct pointers can be asserted", t)
+		return false
+	}
+	if _, ok := types.PointerOf(t).(*types.Struct); !ok {
+		b.CodeErrorf(pos, "pl.typeAssert.notStructPointer",
+			"impossible type assertion, %s is not a struct pointer", t)
+		return false
+	}
+	return assignInterface(b, pos, i, t, "type assertion")
+}
+
+func buildTypeAssert(b *builder, expr *ast.TypeAssertExpr) tast.Expr {
+	x, i := buildAssertee(b, expr.Expr)
+	if x == nil {
+		return nil
+	}
+	if expr.Type == nil {
+		b.CodeErrorf(expr.TypeKw.Pos, "pl.typeAssert.outsideSwitch",
+			"use of .(type) outside type switch")
+		return nil
+	}
+	t := b.buildType(expr.Type)
+	if t == nil {
+		return nil
+	}
+	if !checkAssertType(b, ast.ExprPos(expr.Type), i, t) {
+		return nil
+	}
+	return &tast.TypeAssert{Expr: x, T: t, Ref: tast.NewRef(t)}
+}
@@ -0,0 +1,107 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+func buildTypeSwitchStmt(b *builder, stmt *ast.TypeSwitchStmt) tast.Stmt {
+	x, i := buildAssertee(b, stmt.Expr.Expr)
+	if x == nil {
+		return nil
+	}
+	defer enterSwitch(b)()
+
+	used := false
+	var cases []*tast.TypeCase
+	seen := make(map[types.T]bool)
+	for _, c := range stmt.Cases {
+		ret := buildTypeCase(b, stmt.Var, c, i, seen)
+		if ret == nil {
+			continue
+		}
+		if ret.Sym != nil && ret.Sym.Used {
+			used = true
+		}
+		cases = append(cases, ret)
+	}
+	if stmt.Var != nil && !used {
+		b.CodeErrorf(stmt.Var.Pos, "pl.unusedSym",
+			"unused %s %q", tast.SymStr(tast.SymVar), stmt.Var.Lit)
+	}
+	return &tast.TypeSwitchStmt{Expr: x, Cases: cases}
+}
+
+func buildCaseTypes(
+	b *builder, c *ast.Case, i *types.Interface, seen map[types.T]bool,
+) ([]types.T, bool) {
+	var ret []types.T
+	for _, expr := range c.Expr.(*ast.ExprList).Exprs {
+		pos := ast.ExprPos(expr)
+		var t, key types.T
+		if op, ok := expr.(*ast.Operand); !ok || op.Token.Lit != "nil" {
+			t = b.buildType(expr)
+			if t == nil {
+				return nil, false
+			}
+			if !checkAssertType(b, pos, i, t) {
+				return nil, false
+			}
+			key = types.PointerOf(t)
+		}
+		if seen[key] {
+			b.CodeErrorf(pos, "pl.typeSwitch.duplicateCase",
+				"duplicate case in type switch")
+			return nil, false
+		}
+		seen[key] = true
+		ret = append(ret, t)
+	}
+	return ret, true
+}
+
+func buildTypeCase(
+	b *builder, v *lexing.Token, c *ast.Case, i *types.Interface,
+	seen map[types.T]bool,
+) *tast.TypeCase {
+	ret := new(tast.TypeCase)
+	if c.Kw.Lit == "case" {
+		ts, ok := buildCaseTypes(b, c, i, seen)
+		if !ok {
+			return nil
+		}
+		ret.Types = ts
+	} else {
+		ret.Default = true
+	}
+	if c.Fallthrough != nil {
+		b.CodeErrorf(c.Fallthrough.Kw.Pos, "pl.typeSwitch.fallthrough",
+			"cannot fallthrough in type switch")
+		return nil
+	}
+
+	b.scope.Push()
+	defer b.scope.Pop() // the bound variable is checked by the switch
+
+	if v != nil {
+		var t types.T = i
+		if len(ret.Types) == 1 && ret.Types[0] != nil {
+			t = ret.Types[0]
+		}
+		ret.Sym = declareVar(b, v, t, false)
+		if ret.Sym == nil {
+			return nil
+		}
+	}
+
+	b.scope.Push()
+	defer scopePopAndCheck(b)
+	for _, stmt := range c.Stmts {
+		if s := b.buildStmt(stmt); s != nil {
+			ret.Stmts = append(ret.Stmts, s)
+		}
+	}
+	return ret
+}
@@ -14,6 +14,7 @@ func buildVarDecl(b *builder, d *ast.VarDecl) *tast.Define {
 		if right == nil {
 			return nil
 		}
+		right = commaOk(right, len(ids))
 
 		if d.Type == nil {
 			ret := define(b, ids, right, d.Eq)
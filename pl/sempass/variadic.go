@@ -0,0 +1,73 @@
+package sempass
+
+import (
+	"shanhu.io/smlvm/pl/ast"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// callArgs returns the argument expressions of a call.
+func callArgs(expr *ast.CallExpr) []ast.Expr {
+	if expr.Args == nil {
+		return nil
+	}
+	return expr.Args.Exprs
+}
+
+// buildVariadicArgs builds the arguments of a call to a variadic
+// function, where the trailing arguments are packed into a slice.
+func buildVariadicArgs(
+	b *builder, expr *ast.CallExpr, t *types.Func,
+) tast.Expr {
+	var vals []tast.Expr
+	for _, e := range callArgs(expr) {
+		v := b.buildExpr(e)
+		if v == nil {
+			return nil
+		}
+		vals = append(vals, v)
+	}
+	return packVariadicArgs(b, expr, vals, t)
+}
+
+// packVariadicArgs packs the built trailing arguments vals of a call to
+// a variadic function into a slice. The slice is a slice literal, so its
+// elements are allocated in the heap, and the function may keep it.
+func packVariadicArgs(
+	b *builder, expr *ast.CallExpr, vals []tast.Expr, t *types.Func,
+) tast.Expr {
+	exprs := callArgs(expr)
+	nfixed := len(t.Args) - 1
+	if len(vals) < nfixed {
+		b.CodeErrorf(ast.ExprPos(expr), "pl.argsMismatch.count",
+			"argument count mismatch, expects at least %d, got %d",
+			nfixed, len(vals),
+		)
+		return nil
+	}
+
+	ret := tast.NewExprList()
+	for i, v := range vals[:nfixed] {
+		if ref := v.R(); !ref.IsSingle() {
+			b.CodeErrorf(ast.ExprPos(exprs[i]), "pl.multiRefInExprList",
+				"cannot use %s as a single argument", ref)
+			return nil
+		}
+		ret.Append(v)
+	}
+
+	st := t.ArgTypes[nfixed].(*types.Slice)
+	lit := &tast.ArrayLit{Ref: tast.NewRef(st)}
+	for i, v := range vals[nfixed:] {
+		pos := ast.ExprPos(exprs[nfixed+i])
+		v = checkArrayLitValue(b, pos, v, st.T)
+		if v == nil {
+			return nil
+		}
+		lit.Keys = append(lit.Keys, int32(i))
+		lit.Exprs = append(lit.Exprs, v)
+	}
+	lit.Len = int32(len(lit.Exprs))
+	ret.Append(lit)
+	return ret
+}
@@ -25,17 +25,30 @@ func MakeMemFS() *builds.MemFS {
 		panic(err)
 	}
 
+	runtimeDir := strings.TrimPrefix(RuntimePkg, "/")
+	if err := home.MakeDir(runtimeDir); err != nil {
+		panic(err)
+	}
+	for name, src := range runtimeFiles {
+		err := home.AddTextFile(path.Join(runtimeDir, name), src)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	return home
 }
 
 func makeLangSet(lang *builds.Lang) *builds.LangSet {
 	ret := builds.NewLangSet(lang)
 	ret.AddLang("asm", asm.Lang())
+	ret.AddLang("runtime", RuntimeLang())
 	return ret
 }
 
 // MakeLangSet makes the language picker using the given language as the
-// default language and assembly for "asm" keyword.
+// default language, assembly for "asm" keyword and the runtime language
+// for "runtime" keyword.
 func MakeLangSet(golike bool) *builds.LangSet {
 	return makeLangSet(Lang(golike))
 }
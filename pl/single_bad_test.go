@@ -67,9 +67,17 @@ func TestSingleFileBad(t *testing.T) {
 		`func f() int { for true { if true return 0 } }`)
 	o("missingReturn", `func f() int { if true { return 0 } }`)
 	o("missingReturn", `func f() int { if true return 0 }`)
+	o("missingReturn", `func f(x int) int { switch x { case 1: return 1 } }`)
+	o("missingReturn",
+		`func f(x int) int { switch x { case 1: x++; default: return 1 } }`)
+	o("missingReturn", `func f(x int) int {
+		for { switch x { default: if x > 0 break; return 1 } }
+	}`)
+	o("missingReturn",
+		`func f(x int) int { L: switch x { default: break L; return 1 } }`)
 
-	o("cannotAssign.typeMismatch", `func f() int {return 'a'}`)
-	o("cannotAssign.typeMismatch", `func f() (int, int) {return 1, 'a'}`)
+	o("cannotAssign.typeMismatch", `func f() byte {return 'a' + 200}`)
+	o("cannotAssign.typeMismatch", `func f() (int, int8) {return 1, '\xff'}`)
 	o("return.expectNoReturn", `func f() {return 1}`)
 	o("return.noReturnValue", `func f() int {return}`)
 
@@ -149,6 +157,28 @@ func TestSingleFileBad(t *testing.T) {
 		 interface S { t(a,b int ) }
 		 func main() {var i I; var s S; i=s; _:=i}`)
 
+	// struct literals
+	o("structLit.unknownField", `struct A { a int }
+		func main() { _ := A{b: 1} }`)
+	o("structLit.unknownField", `struct A { a int }; func (a *A) f() {}
+		func main() { _ := A{f: 1} }`)
+	o("structLit.duplicateField", `struct A { a int }
+		func main() { _ := A{a: 1, a: 2} }`)
+	o("structLit.missingField", `struct A { a, b int }
+		func main() { _ := A{1} }`)
+	o("structLit.tooManyValues", `struct A { a int }
+		func main() { _ := A{1, 2} }`)
+	o("structLit.mixed", `struct A { a, b int }
+		func main() { _ := A{a: 1, 2} }`)
+	o("structLit.mixed", `struct A { a, b int }
+		func main() { _ := A{1, b: 2} }`)
+	o("structLit.notStruct", `func main() { _ := int{} }`)
+	o("cannotAssign.typeMismatch", `struct A { a int }
+		func main() { _ := A{a: true} }`)
+
+	// function literals
+	o("missingReturn", `func main() { f := func() int { }; f() }`)
+
 	// others
 	o("multiRefInExprList", ` func r() (int, int) { return 3, 4 }
 		func p(a, b, c int) { }
@@ -166,6 +196,137 @@ func TestSingleFileBad(t *testing.T) {
 	o("caseExpr.notSingle", ` func f() (int, int) { return 0, 0 }
 		func main() { a:=4; switch a {case f():} }`)
 
+	o("invalidMapKey", "func main() { var m map[[]int]int; _ := m }")
+	o("refAddress.notAddressable",
+		"func main() { m := map[int]int{}; p := &m[1]; _ := p }")
+	o("cannotAssign.notAddressable", `struct P { x int }
+		func main() { m := map[int]P{}; m[1].x = 3 }`)
+	o("invalidOp", "func main() { m := map[int]int{}; _ := m == m }")
+	o("mapLit.duplicateKey", "func main() { m := map[int]int{1: 2, 1: 3} }")
+	o("mapLit.missingKey", "func main() { m := map[int]int{1}; _ := m }")
+	o("map.slicing", "func main() { m := map[int]int{}; _ := m[1:2] }")
+	o("range.invalid", "func main() { for k := range 3 { _ := k } }")
+	o("range.invalid", "func main() { var p *int; for k := range p {} }")
+	o("cannotAssign.typeMismatch",
+		"func main() { var c byte; for _, c = range \"abc\" {} }")
+	o("range.tooManyVars",
+		"func main() { m := map[int]int{}; for a, b, c := range m {} }")
+
+	o("defer.notCall", "func main() { a := 3; defer a }")
+	o("defer.notCall", "func main() { defer int(3) }")
+	o("defer.builtin", "func main() { var s []int; defer len(s) }")
+
+	o("variadic.notLast", "func f(a, b ...int) {}; func main() {}")
+	o("call.notVariadic", "func f(a []int) {}; func main() { f(nil...) }")
+	o("call.notVariadic", "func main() { var s []int; n := len(s...) }")
+	o("argsMismatch.count", "func f(a int, b ...int) {}; func main() { f() }")
+	o("cannotAssign.typeMismatch",
+		"func f(a ...int) {}; func main() { f(1, true) }")
+	o("cannotAssign.typeMismatch",
+		"func f(a ...int) {}; func main() { var s []uint; f(s...) }")
+	o("append.dots",
+		"func main() { var s []int; s = append(s, 1, s...) }")
+
+	o("label.unused", "func main() { L: for {} }")
+	o("label.undefined", "func main() { goto L }")
+	o("label.undefined", "func main() { for { break L } }")
+	o("label.duplicate", "func main() { L: goto L; L: goto L }")
+	o("label.invalidBreak", "func main() { L: goto L; for { break L } }")
+	o("label.invalidContinue",
+		"func main() { a := 0; L: switch a { case 0: continue L } }")
+	o("label.undefined",
+		"func main() { L: for { f := func() { break L }; f(); break L } }")
+	o("goto.intoBlock", "func main() { goto L; { L: } }")
+	o("goto.overDecl", "func main() { goto L; a := 3; L: printInt(a) }")
+	o("missingReturn", "func f() int { L: for { for { break L } } }")
+
+	o("typeAssert.notInterface",
+		"struct A {}; func main() { a := &A{}; b := a.(*A); _ := b }")
+	o("typeAssert.outsideSwitch",
+		"interface I {}; func main() { var i I; a := i.(type); _ := a }")
+	o("typeAssert.notStructPointer",
+		"interface I {}; func main() { var i I; a := i.(int); _ := a }")
+	o("cannotAssign.interface", `interface I { f() }; struct A {}
+		func main() { var i I; a := i.(*A); _ := a }`)
+	o("typeAssert.toInterface", `interface I {}; interface J {}
+		func main() { var i I; a := i.(J); _ := a }`)
+	o("typeAssert.toInterface", `interface I {}; interface J {}
+		func main() { var i I; _, ok := i.(J); _ := ok }`)
+	o("typeAssert.toInterface", `interface I {}; interface J {}
+		struct A {}
+		func main() { var i I; switch i.(type) { case *A, J: } }`)
+	o("typeSwitch.duplicateCase", `interface I {}; struct A {}
+		func main() { var i I; switch i.(type) { case *A, nil, *A: } }`)
+	o("typeSwitch.fallthrough", `interface I {}; struct A {}
+		func main() { var i I
+			switch i.(type) { case *A: fallthrough; default: }
+		}`)
+	o("unusedSym", `interface I {}; struct A {}
+		func main() { var i I; switch x := i.(type) { case *A: } }`)
+	o("unusedSym", `interface I {}; struct A {}
+		func main() { var i I; switch i.(type) { case *A: a := 0 } }`)
+
+	o("append.notSlice", "func main() { a := 3; _ := append(a, 1) }")
+	o("append.notSingle", `func f() (int, int) { return 0, 0 }
+		func main() { var s []int; s = append(s, f()) }`)
+	o("cannotAssign.typeMismatch",
+		"func main() { var s []int; s = append(s, true) }")
+
+	o("embed.notStruct", "struct A { *int }; func main() {}")
+	o("declConflict.field", "struct A {}; struct B { A; A }; func main() {}")
+	o("buildMember.ambiguous", `struct A { v int }; struct B { v int }
+		struct C { A; B }; func main() { var c C; printInt(c.v) }`)
+	o("cannotAssign.interface", `struct A {}; func (a *A) F() {}
+		struct B {}; func (b *B) F() {}
+		struct C { A; B }; interface I { F() }
+		func main() { var i I = new(C); _ := i }`)
+
+	o("invalidOp.typeMismatch", `type C int
+		func main() { var c C; var i int; printInt(int(c + i)) }`)
+	o("cannotAssign.typeMismatch", `type C int
+		func main() { var i int; var c C = i; _ := c }`)
+	o("cannotAssign.typeMismatch", `type A []int; type B []int
+		func main() { var a A; var b B = a; _ := b }`)
+	o("invalidRecv", "type P *int; func (p *P) F() {}; func main() {}")
+	o("circDep.struct", "type A B; type B A; func main() {}")
+	o("declConflict.type", "type C int; struct C {}; func main() {}")
+	o("cannotAssign.interface", `type C int; interface I { F() }
+		func main() { var c C; var i I = &c; _ := i }`)
+	o("generic.constraint", `func F[T integer](t T) {}
+		func main() { F(true) }`)
+	o("generic.constraint", `struct S[T comparable] { t T }
+		func main() { var s S[map[int]int]; _ := s }`)
+	o("generic.argsCount", `struct S[T any] {}
+		func main() { var s S[int, int]; _ := s }`)
+	o("generic.argsCount", `struct S[K, V any] {}
+		func (s *S[K]) F() {}; func main() {}`)
+	o("generic.cannotInfer", `func F[T, U any](t T) {}
+		func main() { F(3) }`)
+	o("generic.notInstantiated", `func F[T any](t T) {}
+		func main() { f := F; f(3) }`)
+	o("generic.notGeneric", `struct S {}
+		func (s *S[T]) F() {}; func main() {}`)
+	o("generic.badConstraint", "func F[T number]() {}; func main() {}")
+	o("declConflict.typeParam", "func F[T, T any]() {}; func main() {}")
+	o("declConflict.generic", "struct F {}; func F[T any]() {}")
+	o("generic.tooDeep", `func F[T any](t T) { F([]T{t}) }
+		func main() { F(1) }`)
+	o("cannotAssign.typeMismatch", `func F[T any](t T) { var x int = t }
+		func main() { F(true) }`)
+	o("circDep.struct", "struct S[T any] { s S[T] }; func main() {}")
+	o("chan.notChan", "func main() { a := 3; a <- 4 }")
+	o("chan.notChan", "func main() { a := 3; b := <-a; _ := b }")
+	o("chan.notChan", "func main() { a := 3; close(a) }")
+	o("cannotAssign.typeMismatch",
+		`func main() { c := make(chan int, 1); c <- "x" }`)
+	o("go.notCall", "func main() { a := 3; go a }")
+	o("go.builtin", "func main() { c := make(chan int); go close(c) }")
+	o("select.multiDefault", `func main() {
+			select { default: ; default: }
+		}`)
+	o("range.tooManyVars",
+		"func main() { c := make(chan int); for a, b := range c {} }")
+
 	// Bugs found by the fuzzer in the past
 	o("undefinedIdent", "func f() **o.o {}")
 	o("expectConstExpr", "func n()[char[:]]string{}")
@@ -175,14 +336,6 @@ func TestSingleFileBad(t *testing.T) {
 			for i := 0; i < len(s-2); i++ {}
 		}`)
 
-	o("notYetSupported", `interface I { t() int }
-		func main() { var b *B; var i I; i=b; _:=i;}
-		struct B {
-    		v int
-		}
-		func (b *B) t() int {
-			return b.v
-		}`)
 	o("notYetSupported", `interface I { t() int }
 		func main() { var b I2; var i I; i=b; _:=i;}
 		interface I2 {t() int}`)
@@ -194,7 +347,7 @@ func TestSingleFilePanic(t *testing.T) {
 
 	const N = 100000
 	o := func(input string) {
-		_, e := singleTestRun(t, input, N)
+		_, e := singleTestRun(t, input, nil, N)
 		if !arch.IsErr(e, arch.ErrPanic) {
 			t.Log(input)
 			t.Log(e)
@@ -210,4 +363,23 @@ func TestSingleFilePanic(t *testing.T) {
 	o("func f() {}; func main() { var a func()=f; a=nil; a() }")
 	o("func f(p *int) { printInt(*p) }; func main() { f(nil) }")
 	o("struct A { p *int }; func main() { var a A; a.p=nil; *a.p=0 }")
+	o("interface I { f() }; func main() { var i I; i.f() }")
+	o("func main() { var m map[int]int; m[3] = 4 }")
+	o("func main() { n := -1; s := make([]int, n); _ := s }")
+	o("func main() { s := make([]int, 1<<28); _ := s }")
+	o("func main() { var f func(); defer f() }")
+	o(`interface I {}; struct A {}
+		func main() { var i I; p := i.(*A); _ := p }`)
+	o(`interface I {}; struct A {}; struct B {}
+		func main() { var i I = &B{}; p := i.(*A); _ := p }`)
+	o("func f(p *int) { defer printInt(3); *p = 0 }; func main() { f(nil) }")
+	o("func main() { c := make(chan int); <-c }")
+	o("func main() { c := make(chan int, 1); close(c); c <- 1 }")
+	o("func main() { c := make(chan int); close(c); close(c) }")
+	o("func main() { var c chan int; close(c) }")
+	o(`func main() {
+			c := make(chan int)
+			go func() { <-c }()
+			select { case v := <-c: _ := v }
+		}`)
 }
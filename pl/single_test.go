@@ -3,6 +3,7 @@ package pl
 import (
 	"testing"
 
+	"bytes"
 	"errors"
 	"strings"
 
@@ -11,7 +12,11 @@ import (
 
 var errRunFailed = errors.New("test run failed")
 
-func singleTestRun(t *testing.T, input string, N int) (string, error) {
+// singleTestRun compiles and runs a single file program on a machine of
+// config c, or on a default machine if c is nil.
+func singleTestRun(t *testing.T, input string, c *arch.Config, N int) (
+	string, error,
+) {
 	bs, es, _ := CompileSingle("main.g", input, false)
 	if es != nil {
 		t.Log(input)
@@ -22,20 +27,35 @@ func singleTestRun(t *testing.T, input string, N int) (string, error) {
 		return "", errRunFailed
 	}
 
-	ncycle, out, err := arch.RunImageOutput(bs, N)
+	config := new(arch.Config)
+	if c != nil {
+		*config = *c
+	}
+	out := new(bytes.Buffer)
+	config.Output = out
+	m := arch.NewMachine(config)
+	if err := m.LoadImageBytes(bs); err != nil {
+		t.Error(err)
+		return "", errRunFailed
+	}
+
+	ncycle, exp := m.Run(N)
 	if ncycle == N {
 		t.Log(input)
 		t.Error("running out of time")
 		return "", errRunFailed
 	}
-	return out, err
+	if exp == nil {
+		return out.String(), nil
+	}
+	return out.String(), exp
 }
 
 func TestSingleFile(t *testing.T) {
 	const N = 100000
 
 	o := func(input, output string) {
-		out, err := singleTestRun(t, input, N)
+		out, err := singleTestRun(t, input, nil, N)
 		if err == errRunFailed {
 			t.Error(err)
 			return
@@ -229,6 +249,42 @@ func TestSingleFile(t *testing.T) {
 		func main() { printInt(a); printInt(b); printInt(c); printInt(d) }`,
 		"1\n1\n3\n3\n")
 	o(`const a, b = b + 3, 30; func main() { printInt(a) }`, "33")
+	o(`	const s = "hi" + "!"
+		const n, nl = len(s), '\n'
+		var buf [len(s) * 2]int
+		func main() {
+			for i := 0; i < len(s); i++ { printChar(s[i]) }
+			printChar(nl); printInt(n); printInt(len(buf))
+		}`, "hi!\n3\n6")
+	o(`	const a = "abc"
+		func main() {
+			if a == "abc" { printInt(1) }
+			if a != "abc" { printInt(2) }
+			if a < "abd" { printInt(3) }
+			if "b" >= a { printInt(4) }
+			printInt(len(a + "de"))
+		}`, "1\n3\n4\n5")
+	o(`	const x, y = 'x', 'y'
+		func main() {
+			c := 'y'
+			switch c { case x: printInt(1); case y: printInt(2) }
+		}`, "2")
+	o(`	const hi = "hi"
+		func f(s string) int {
+			switch s {
+			case "": return 0
+			case hi: return 1
+			case "hi" + "!": return 2
+			case "ho": return 3
+			}
+			return 4
+		}
+		func main() {
+			b := make([]char, 2)
+			b[0] = 'h'; b[1] = 'o'
+			printInt(f("")); printInt(f(hi)); printInt(f("hi!"))
+			printInt(f(string(b))); printInt(f("h"))
+		}`, "0\n1\n2\n3\n4")
 
 	o(`	var a [4]int
 		func main() {
@@ -242,6 +298,685 @@ func TestSingleFile(t *testing.T) {
 		func main() { }`, "")
 	o(`func f() int { for { if true { return 0 } } }; func main() { }`, "")
 
+	// interfaces
+	o(`	interface I { v() int; add(d int) }
+		struct A { n int }
+		func (a *A) v() int { return a.n }
+		func (a *A) add(d int) { a.n += d }
+		struct B { n int }
+		func (b *B) add(d int) { b.n -= d }
+		func (b *B) v() int { return b.n * 10 }
+		func p(i I) { i.add(2); printInt(i.v()) }
+		func main() {
+			var a A; var b B
+			var i I = &a; p(i); p(&b); p(i)
+			i = &b; p(i)
+		}`, "2\n-20\n4\n-40")
+	o(`	interface I { f() }
+		struct A {}
+		func (a *A) f() {}
+		func main() {
+			var i I
+			if i == nil { printInt(1) }
+			var a A
+			i = &a
+			if i != nil { printInt(2) }
+			j := i
+			if i == j { printInt(3) }
+			var b A
+			j = &b
+			if i != j { printInt(4) }
+			i = nil
+			if i == nil { printInt(5) }
+		}`, "1\n2\n3\n4\n5")
+
+	// type assertions and type switches
+	o(`	interface I { v() int }
+		struct A { n int }
+		func (a *A) v() int { return a.n }
+		struct B { n int }
+		func (b *B) v() int { return -b.n }
+		func main() {
+			a := &A{3}
+			var i I = a
+			printInt(i.(*A).n)
+			b, ok := i.(*B)
+			if !ok && b == nil { printInt(4) }
+			p, found := i.(*A)
+			if found && p == a { printInt(5) }
+			i = nil
+			p, ok = i.(*A)
+			if !ok && p == nil { printInt(6) }
+		}`, "3\n4\n5\n6")
+	o(`	interface I { v() int }
+		struct A { n int }
+		func (a *A) v() int { return a.n }
+		struct B { n int }
+		func (b *B) v() int { return -b.n }
+		func f(i I) {
+			switch x := i.(type) {
+			case *A:
+				printInt(x.n)
+			case *B, nil:
+				if x == nil { printInt(0) } else { printInt(x.v()) }
+			default:
+				printInt(100)
+			}
+		}
+		func main() { f(&A{3}); f(&B{4}); f(nil) }`, "3\n-4\n0")
+	o(`	interface I { }
+		struct A {}
+		struct B {}
+		func main() {
+			var i I = &B{}
+			L: for {
+				switch i.(type) {
+				default:
+					printInt(1)
+				case *A:
+					printInt(2)
+				case *B:
+					printInt(3)
+					break L
+				}
+			}
+		}`, "3")
+
+	// array literals
+	o(`	struct P { x, y int }
+		func main() {
+			var p1, p2 P
+			p2.y = 5
+			ps := []*P{&p1, 1: &p2}
+			ss := [2]P{p2, p1}
+			printInt(ps[1].y + ss[0].y)
+			strs := []string{"a", "bc", 3: "def"}
+			printInt(len(strs[1]) + len(strs[3]) + len(strs))
+			m := [2][3]int{[3]int{}, [3]int{2: 4}}
+			printInt(m[1][2])
+			s := [][]int{[]int{1, 2}, nil, []int{3}}
+			printInt(len(s[0]) + s[2][0])
+		}`, "10\n9\n4\n5")
+	o(`	func f() int { a := [2]int{1, 2}; a[0] += 5; return a[0] }
+		func main() { printInt(f()); printInt(f()) }`, "6\n6")
+	o(`	func f(n int) []int { return []int{n, n + 1, n + 2} }
+		func g(n int) int { var a [20]int; a[3] = n; return a[3] }
+		var strs []string
+		func h() { strs = []string{"ab", "cde"} }
+		func main() {
+			s := f(3)
+			g(77); h(); g(88)
+			printInt(s[0] + s[1] + s[2])
+			printInt(len(strs[0]) + len(strs[1]))
+		}`, "12\n5")
+	o(`	func f() []int { return []int{1, 2, 3} }
+		func main() {
+			s := f()
+			s[0] = 100
+			printInt(f()[0])
+			for i := 0; i < 2; i++ {
+				a := []int{5, 6}
+				printInt(a[0])
+				a[0] = 9
+			}
+		}`, "1\n5\n5")
+	o(`	struct P { x, y int }
+		func main() {
+			g := [2][2]int{{1, 2}, {3, 4}}
+			d := [...]string{"a", 3: "bcd"}
+			ps := []*P{{1, 2}, {x: 3}}
+			m := map[string]P{"a": {5, 6}}
+			printInt(g[1][0] + len(d) + len(d[3]) + ps[1].x + m["a"].y)
+		}`, "19")
+
+	// struct literals
+	o(`	struct P { x, y int; c byte }
+		func (p *P) sum() int { return p.x + p.y + int(p.c) }
+		struct L { a, b P; n *P }
+		func main() {
+			p := P{x: 3, y: 4}
+			printInt(p.sum())
+			q := &P{1, 2, 3}
+			printInt(q.sum())
+			l := L{b: P{y: 7}, n: q}
+			printInt(l.b.y + int(l.n.c) + l.a.x)
+			printInt(P{x: 5}.x + P{}.y)
+			for i := 0; i < 1; i++ { printInt((P{y: 2}).y) }
+		}`, "7\n6\n10\n5\n2")
+	o(`	struct Point { X, Y int; c byte }
+		func mk(x int) *Point { return &Point{X: x, Y: x * 2, c: 7} }
+		func g(n int) int { var a [20]int; a[3] = n; return a[3] }
+		func main() {
+			p := mk(5)
+			g(99)
+			printInt(p.X + p.Y + int(p.c))
+			q := &Point{}
+			printInt(q.Y)
+		}`, "22\n0")
+
+	// function literals and closures
+	o(`	func apply(f func(int) int, x int) int { return f(x) }
+		func mk() func(int) int { return func(x int) int { return x+1 } }
+		func main() {
+			printInt(func(a, b int) int { return a*b }(3, 4))
+			sq := func(x int) int { return x*x }
+			printInt(apply(sq, 5) + mk()(1))
+		}`, "12\n27")
+	o(`	struct P { x, y int }
+		func main() {
+			n := 3
+			var b byte
+			p := P{x: 1}
+			add := func(x int) int { b++; p.y += x; return x + n }
+			printInt(add(4))
+			n = 10
+			printInt(add(4))
+			printInt(int(b) + p.y)
+		}`, "7\n14\n10")
+	o(`	func main() {
+			var fib func(n int) int
+			fib = func(n int) int {
+				if n < 2 { return n }
+				return fib(n-1) + fib(n-2)
+			}
+			printInt(fib(10))
+		}`, "55")
+	o(`	func main() {
+			s := 0
+			for i := 0; i < 4; i++ {
+				add := func() { f := func() { s += i }; f() }
+				add()
+			}
+			func() { func() { s *= 10 }() }()
+			printInt(s)
+		}`, "60")
+
+	// closures that escape
+	o(`	func apply(f func(int) int, x int) int { return f(x) }
+		func counter() func() int {
+			n := 0
+			return func() int { n++; return n }
+		}
+		func adder(k int) func(int) int {
+			return func(x int) int { return x + k }
+		}
+		func main() {
+			k := 10
+			add := func(x int) int { return x + k }
+			printInt(apply(add, 1))
+			c := counter()
+			c(); c()
+			printInt(c())
+			var fs []func(int) int
+			for i := 0; i < 3; i++ { fs = append(fs, adder(i*100)) }
+			printInt(fs[2](5) + fs[1](0))
+		}`, "11\n3\n305")
+	o(`	var saved func()
+		func keep(f func()) { saved = f }
+		func named() (r int) {
+			keep(func() { r = 42 })
+			saved()
+			return
+		}
+		func main() {
+			printInt(named())
+			x := 1
+			keep(func() { x *= 3 })
+			saved(); saved()
+			printInt(x)
+			v := 7
+			outer := func() func() int { return func() int { return v } }
+			v = 8
+			printInt(outer()())
+		}`, "42\n9\n8")
+
+	// maps
+	o(`	func main() {
+			m := make(map[int]int)
+			for i := 0; i < 50; i++ { m[i] = i * 2 }
+			m[3] += 10
+			m[3]++
+			printInt(len(m)); printInt(m[7]); printInt(m[3])
+			v, ok := m[100]
+			if !ok { printInt(v) }
+			delete(m, 3)
+			v, ok = m[3]
+			if !ok { printInt(len(m)) }
+			sum := 0
+			for k, v := range m { sum += k + v }
+			printInt(sum)
+		}`, "50\n14\n17\n0\n49\n3666")
+	o(`	struct P { x, y int }
+		func main() {
+			s := map[string]int{"a": 1, "bb": 2}
+			s["ccc"] = 3
+			n := 0
+			for k := range s { n += len(k) }
+			printInt(n + s["bb"])
+			var nm map[string]int
+			if nm == nil { printInt(len(nm) + nm["x"]) }
+			ps := map[int]P{1: P{1, 2}}
+			printInt(ps[1].y)
+			p := &P{}
+			mp := map[*P]bool{p: true}
+			var k *P
+			for k = range mp { k.x = 5 }
+			printInt(p.x)
+		}`, "8\n0\n2\n5")
+	o(`	func f() []int { printInt(9); return []int{4, 5, 6} }
+		func main() {
+			a := [3]int{1, 2, 3}
+			for i, v := range a { a[2] = 10; printInt(i*10 + v) }
+			s := a[:]
+			for _, v := range s { s = append(s, v) }
+			n := 0
+			for range f() { n++ }
+			printInt(len(s) + n)
+		}`, "1\n12\n23\n9\n9")
+	o(`	func main() {
+			for _, c := range "hi" { printChar(c) }
+			var i int
+			var c char
+			for i, c = range "ab!" { printChar(c) }
+			printInt(i)
+			for _, v := range []int{1, 2, 3, 4} {
+				if v == 2 { continue }
+				if v == 4 { break }
+				printInt(v)
+			}
+			var bs [4]byte
+			for i := range bs { bs[i] = byte(i) + byte(1) }
+			sum := 0
+			for _, b := range bs { sum += int(b) }
+			for i, s := range []string{"x", "yz"} { sum += i * len(s) }
+			printInt(sum)
+		}`, "hiab!2\n1\n3\n12")
+
+	// labels
+	o(`	func main() {
+			n := 0
+		outer:
+			for i := 0; i < 5; i++ {
+				for j := 0; j < 5; j++ {
+					if j == 3 continue outer
+					if i == 3 break outer
+					n += i*10 + j
+				}
+			}
+			printInt(n)
+		R:
+			for _, v := range []int{1, 2} {
+				for k := range "ab" {
+					if k == 1 { continue R }
+					printInt(v*10 + k)
+				}
+			}
+		}`, "99\n10\n20")
+	o(`	func main() {
+			i := 0
+		loop:
+			i++
+			if i < 5 { goto loop }
+			printInt(i)
+		L:
+			switch i {
+			case 5:
+				for { i++; if i == 7 { break L } }
+				printInt(1000)
+			}
+			printInt(i)
+			goto end
+			printInt(77)
+		end:
+		}`, "5\n7")
+	o(`	func f(x int) int {
+			switch x {
+			case 1: return 10
+			case 2: fallthrough
+			default:
+				for { if x > 2 { return 20 } ; x++ }
+			}
+		}
+		func main() { printInt(f(1)); printInt(f(2)); printInt(f(5)) }`,
+		"10\n20\n20")
+	o(`	func f() int {
+			i := 0
+		L:
+			for {
+				for { i++; if i > 3 { break L } }
+			}
+			return i
+		}
+		func main() {
+			g := func() int {
+			L:
+				for { break L }
+				return 4
+			}
+			printInt(f() + g())
+		}`, "8")
+
+	// variadic functions
+	o(`	func sum(xs ...int) int {
+			ret := 0
+			for _, x := range xs { ret += x }
+			return ret
+		}
+		struct T { n int }
+		func (t *T) add(p string, xs ...int) { t.n += len(p) + sum(xs...) }
+		func main() {
+			printInt(sum())
+			printInt(sum(1, 2, 3))
+			s := []int{4, 5}
+			printInt(sum(s...))
+			var t T
+			t.add("ab", 1, 2)
+			t.add("", s...)
+			printInt(t.n)
+			f := sum
+			printInt(f(10, 20))
+			g := func(p string, xs ...string) int { return len(p) + len(xs) }
+			printInt(g("ab", "c", "d"))
+		}`, "0\n6\n9\n14\n30\n4")
+	o(`	func keep(xs ...int) []int { return xs }
+		var saved []int
+		func store(xs ...int) { saved = xs }
+		func g(n int) int { var a [20]int; a[3] = n; return a[3] }
+		func main() {
+			s := keep(5, 6, 7)
+			store(1, 2)
+			g(100)
+			printInt(s[0] + s[1] + s[2])
+			printInt(saved[0] + saved[1])
+		}`, "18\n3")
+	o(`	func main() {
+			s := append([]int{1}, []int{2, 3}...)
+			s = append(s, s...)
+			printInt(len(s))
+			var e []int
+			e = append(e, e...)
+			printInt(len(e))
+			b := make([]int, 8)
+			b[0], b[1], b[2] = 1, 2, 3
+			c := append(b[:1], b[:3]...)
+			printInt(c[0]*1000 + c[1]*100 + c[2]*10 + c[3])
+		}`, "6\n0\n1123")
+
+	// heap allocation
+	o(`	struct P { x, y int }
+		func main() {
+			p := new(P)
+			p.x, p.y = 3, 4
+			printInt(p.x * p.y)
+			printInt(*new(int))
+			s := make([]int, 3)
+			s[2] = 5
+			var t []int
+			for i := 0; i < 100; i++ { t = append(t, i) }
+			t2 := append(t[:1], 7, 8)
+			printInt(len(s) + s[2])
+			printInt(len(t2) + t[1] + t[2] + t[50])
+			var bs []byte
+			bs = append(bs, byte('h'), byte('i'))
+			printChar(char(bs[0])); printChar(char(bs[1]))
+			ps := append([]*P{}, p, nil)
+			if ps[1] == nil { printInt(ps[0].y) }
+		}`, "12\n0\n8\n68\nhi4")
+	o(`	import ("/std/runtime")
+		var pool [8 << 12]byte
+		var used uint
+		func pages(n uint) uint {
+			base := (uint(&pool[0]) + 4095) / 4096 * 4096
+			if used+n > 7 { return 0 }
+			used += n
+			return base + (used-n)*4096
+		}
+		func main() {
+			runtime.PageSource = pages
+			s := make([]int, 2000)
+			s[1999] = 3
+			p := new(int)
+			printUint(used); printInt(s[1999] + *p)
+		}`, "3\n3")
+
+	// defer
+	o(`	func f(n int) (int, int) {
+			defer printInt(n)
+			for i := 0; i < 3; i++ { defer printInt(10 + i) }
+			if n > 5 { return n, 1 }
+			defer func() { printInt(7) }()
+			return n, 2
+		}
+		func main() { a, b := f(3); printInt(a + b); a, b = f(9) }`,
+		"7\n12\n11\n10\n3\n5\n12\n11\n10\n9")
+	o(`	func f() (r int) {
+			defer func() { r *= 2 }()
+			r = 21
+			return
+		}
+		struct T { n int }
+		func (t *T) p(a int) { printInt(t.n + a) }
+		func main() {
+			t := &T{n: 40}
+			defer t.p(2)
+			g := func(x int) { printInt(x) }
+			defer g(f())
+			t.n = 50
+		}`, "42\n52")
+	o(`	struct S { a, b int }
+		interface I { f(x int) }
+		func (s *S) f(x int) { printInt(s.a * x) }
+		func p(s S, c char) { printInt(s.a + s.b); printChar(c) }
+		func main() {
+			s := S{1, 2}
+			var i I = &s
+			defer i.f(5)
+			defer p(s, 'x')
+			s.a = 100
+		}`, "3\nx500")
+
+	// structs and arrays returned by value
+	o(`	struct P { x, y int }
+		struct B { b1, b2, b3 byte }
+		func f(i int) (P, B, int) { return P{i, i+1}, B{1, 2, 3}, i * 10 }
+		func use(p P, b B, n int) { printInt(p.y + int(b.b3) + n) }
+		func g() [3]int { var a [3]int; a[2] = 7; return a }
+		func main() {
+			var arr [2]P
+			m := make(map[int]B)
+			var n int
+			arr[1], m[4], n = f(3)
+			printInt(arr[1].y + int(m[4].b2) + n)
+			use(f(5))
+			p, b, _ := f(7)
+			printInt(p.x + int(b.b1))
+			printInt(g()[2])
+		}`, "36\n59\n8\n7")
+	o(`	struct P { x, y int }
+		func f(a, b, c, d P) (r, s P) {
+			r.x = 100
+			s.y = 200
+			printInt(a.x + b.x + c.x + d.x)
+			return r, s
+		}
+		func g(a, b P) (r P) { r = b; r.x += a.x; return }
+		func h(n1, n2, n3, n4, n5 int) (r1, r2, r3, r4, r5 int) {
+			r4 = 40; r5 = 50
+			printInt(n4 + n5)
+			return
+		}
+		func main() {
+			r, s := f(P{1, 0}, P{2, 0}, P{3, 0}, P{4, 0})
+			printInt(r.x + s.y)
+			printInt(g(P{1, 2}, P{10, 20}).x)
+			_, _, _, a, b := h(1, 2, 3, 4, 5)
+			printInt(a + b)
+		}`, "10\n300\n11\n9\n90")
+
+	// struct embedding
+	o(`	struct Base { x int }
+		func (b *Base) Get() int { return b.x }
+		func (b *Base) Add(d int) { b.x += d }
+		struct Mid { Base; y int }
+		func (m *Mid) Sum() int { return m.x + m.y }
+		struct Top { *Mid }
+		interface Getter { Get() int; Add(d int) }
+		func main() {
+			var m Mid
+			m.x = 3
+			m.y = 4
+			m.Add(2)
+			printInt(m.Base.Get())
+			t := &Top{&m}
+			t.x++
+			printInt(t.Sum())
+			var g Getter = t
+			g.Add(10)
+			printInt(m.Get())
+		}`, "5\n10\n16")
+	o(`	struct A { v int }
+		func (a *A) F() int { return 1 }
+		func (a *A) P(s string, ns ...int) (int, int) {
+			return len(s), len(ns)
+		}
+		struct C { v int }
+		func (c *C) F() int { return 3 }
+		struct B { C }
+		struct D { A; B }
+		interface I { F() int; P(s string, ns ...int) (int, int) }
+		func main() {
+			d := new(D)
+			d.v = 7
+			var i I = d
+			n, m := i.P("ab", 4, 5, 6)
+			printInt(i.F() + d.B.F() + d.A.v + n + m)
+		}`, "16")
+
+	// named types
+	o(`	type Celsius int
+		func (c *Celsius) Double() Celsius { return *c * 2 }
+		type Handler func(int)
+		func (h *Handler) Call(n int) { (*h)(n + 1) }
+		type Ints []int
+		func (s *Ints) Sum() int {
+			ret := 0
+			for _, v := range *s { ret += v }
+			return ret
+		}
+		interface Doubler { Double() Celsius }
+		func main() {
+			c := Celsius(21)
+			printInt(int(c.Double()))
+			var h Handler = printInt
+			h.Call(3)
+			var s Ints
+			s = append(s, 1, 2, 3)
+			printInt(s.Sum() + len(s))
+			var d Doubler = &c
+			c++
+			printInt(int(d.Double()))
+		}`, "42\n4\n9\n44")
+	o(`	struct point { x, y int }
+		type Vec point
+		func (v *Vec) Len() int { return v.x + v.y }
+		type Counts map[string]int
+		type Ptr *int
+		func main() {
+			v := Vec{x: 3, y: 4}
+			p := point(v)
+			m := make(Counts)
+			m["a"] = 5
+			n := 7
+			var q Ptr = &n
+			printInt(v.Len() + p.x + m["a"] + len(m) + *q)
+		}`, "23")
+	o(`	type C int
+		func main() {
+			var c, d C = 3, 4
+			if c < d { c = c * d % 5 }
+			c <<= 2
+			printInt(int(-c))
+		}`, "-8")
+	o(`	type IDs []int
+		func (s *IDs) Sum() int {
+			ret := 0
+			for _, v := range *s { ret += v }
+			return ret
+		}
+		type Grid [3]int
+		type Ages map[string]int
+		type Temp float32
+		func main() {
+			ids := IDs{1, 2, 3}
+			n := 4
+			more := IDs{n, 2: n + 1}
+			g := Grid{1: 5}
+			a := Ages{"x": 7}
+			printInt(ids.Sum() + more.Sum() + g[1] + a["x"])
+			var t Temp = 1.5
+			t = t * 2
+			printFloat(float32(t))
+		}`, "27\n3.000000")
+
+	// generics
+	o(`	func Max[T integer](a, b T) T {
+			if a > b { return a }
+			return b
+		}
+		func Index[T comparable](ts []T, t T) int {
+			for i, v := range ts { if v == t { return i } }
+			return -1
+		}
+		func Map[T, U any](ts []T, f func(t T) U) []U {
+			var ret []U
+			for _, t := range ts { ret = append(ret, f(t)) }
+			return ret
+		}
+		func Sum[T integer](ts ...T) T {
+			var ret T
+			for _, t := range ts { ret += t }
+			return ret
+		}
+		func main() {
+			printInt(Max[int](3, 4))
+			printInt(int(Max(byte(7), byte(5))))
+			printInt(Index([]string{"a", "b"}, "b"))
+			lens := Map([]string{"a", "bcd"}, func(s string) int {
+				return len(s)
+			})
+			printInt(lens[1] + Sum(1, 2, 3) + Sum([]int{4, 5}...))
+			f := Max[int8]
+			printInt(int(f(-3, -2)))
+		}`, "4\n7\n1\n18\n-2")
+	o(`	struct Node[T any] { v T; next *Node[T] }
+		struct Stack[T any] { top *Node[T]; n int }
+		func (s *Stack[T]) Push(v T) {
+			n := new(Node[T])
+			n.v, n.next = v, s.top
+			s.top = n
+			s.n++
+		}
+		func (s *Stack[E]) Pop() E {
+			ret := s.top.v
+			s.top = s.top.next
+			s.n--
+			return ret
+		}
+		struct Pair[K comparable, V any] { k K; v V }
+		struct Holder { s Stack[Pair[string, int]] }
+		func Fact[T integer](n T) T {
+			if n <= 1 { return 1 }
+			return n * Fact(n-1)
+		}
+		func main() {
+			var h Holder
+			h.s.Push(Pair[string, int]{k: "a", v: 3})
+			h.s.Push(Pair[string, int]{k: "bc", v: 4})
+			p := h.s.Pop()
+			printInt(len(p.k) + p.v + h.s.n)
+			printInt(Fact(5))
+		}`, "7\n120")
+
 	// Bugs found by the fuzzer in the past
 	o("func main() { a := 0==0; if a { printInt(33) } }", "33")
 	o(`	func n()[(4-3)*1]string { var a [1]string; return a }
@@ -279,3 +1014,72 @@ func TestSingleFile(t *testing.T) {
 		}
 		func main() { f(0, 0, 0) }`, "0")
 }
+
+func TestSingleFileGolike(t *testing.T) {
+	const N = 100000
+	const input = `
+		package main
+
+		type A struct{ n int }
+
+		func (a *A) v() int { return a.n }
+
+		type I interface {
+			v() int
+		}
+
+		func g(x int) int {
+			for {
+				switch x {
+				case 1:
+					break
+				}
+				return x
+			}
+		}
+
+		func main() {
+			var i I = &A{n: 3}
+			switch i.v() {
+			case 3:
+				printInt(3)
+				fallthrough
+			default:
+				printInt(4)
+			}
+			switch x := i.(type) {
+			case *A:
+				printInt(x.n + 2)
+			}
+			for j := 0; j < 3; j++ {
+				switch j {
+				case 1:
+					break
+				}
+				select {
+				default:
+					break
+				}
+				printInt(j)
+			}
+			printInt(g(1))
+		}`
+
+	bs, es, _ := CompileSingle("main.g", input, true)
+	if es != nil {
+		for _, err := range es {
+			t.Log(err)
+		}
+		t.Fatal("compile failed")
+	}
+	ncycle, out, err := arch.RunImageOutput(bs, N)
+	if ncycle == N {
+		t.Fatal("running out of time")
+	}
+	if !arch.IsHalt(err) {
+		t.Fatal("did not halt gracefully:", err)
+	}
+	if got := strings.TrimSpace(out); got != "3\n4\n5\n0\n1\n2\n1" {
+		t.Errorf("got: %q", got)
+	}
+}
@@ -11,9 +11,13 @@ func buildStmt(b *builder, s tast.Stmt) {
 	case nil:
 		return // empty statement
 	case *tast.ContinueStmt:
-		buildContinueStmt(b)
+		buildContinueStmt(b, stmt)
 	case *tast.BreakStmt:
-		buildBreakStmt(b)
+		buildBreakStmt(b, stmt)
+	case *tast.GotoStmt:
+		buildGotoStmt(b, stmt)
+	case *tast.LabeledStmt:
+		buildLabeledStmt(b, stmt)
 	case *tast.IncStmt:
 		buildIncStmt(b, stmt)
 	case *tast.ExprStmt:
@@ -32,14 +36,26 @@ func buildStmt(b *builder, s tast.Stmt) {
 		buildAssignStmt(b, stmt)
 	case *tast.ReturnStmt:
 		buildReturnStmt(b, stmt)
+	case *tast.DeferStmt:
+		buildDeferStmt(b, stmt)
+	case *tast.GoStmt:
+		buildGoStmt(b, stmt)
+	case *tast.SendStmt:
+		buildSendStmt(b, stmt)
 	case *tast.Block:
 		buildBlock(b, stmt)
 	case *tast.ForStmt:
 		buildForStmt(b, stmt)
+	case *tast.RangeStmt:
+		buildRangeStmt(b, stmt)
 	case *tast.IfStmt:
 		buildIfStmt(b, stmt)
 	case *tast.SwitchStmt:
 		buildSwitchStmt(b, stmt)
+	case *tast.TypeSwitchStmt:
+		buildTypeSwitchStmt(b, stmt)
+	case *tast.SelectStmt:
+		buildSelectStmt(b, stmt)
 	default:
 		panic(fmt.Errorf("unimplemented: %T", stmt))
 	}
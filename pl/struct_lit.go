@@ -0,0 +1,47 @@
+package pl
+
+import (
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// fillStructLit assigns the fields of a struct literal into the zeroed
+// struct at address base.
+func fillStructLit(b *builder, lit *tast.StructLit, base codegen.Ref) {
+	for i, expr := range lit.Exprs {
+		v := b.buildExpr(expr)
+		f := lit.Fields[i]
+		dest := codegen.NewAddrRef(
+			base,
+			f.T.Size(),
+			f.Offset(),
+			types.IsByte(f.T),
+			true,
+		)
+		b.b.Assign(dest, v.IR())
+	}
+}
+
+func buildStructLit(b *builder, lit *tast.StructLit) *ref {
+	ret := b.newTemp(lit.T)
+	b.b.Zero(ret.IR())
+	if len(lit.Exprs) == 0 {
+		return ret
+	}
+
+	base := b.newPtr()
+	b.b.Arith(base, nil, "&", ret.IR())
+	fillStructLit(b, lit, base)
+	return ret
+}
+
+// buildStructLitPtr builds &T{...}, where the struct is allocated in the
+// heap.
+func buildStructLitPtr(b *builder, lit *tast.StructLit, t types.T) *ref {
+	ret := b.newTemp(t)
+	size := codegen.Num(uint32(lit.T.Size()))
+	b.b.Call([]codegen.Ref{ret.IR()}, b.rt.alloc, size, b.typeDesc(lit.T))
+	fillStructLit(b, lit, ret.IR())
+	return ret
+}
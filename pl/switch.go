@@ -6,6 +6,44 @@ import (
 	"shanhu.io/smlvm/pl/types"
 )
 
+// pushSwitchBreak pushes the break target of a switch, a type switch or a
+// select. Only a labeled break can break out of them, unless in golike
+// mode, where an unlabeled break breaks the innermost one like in Go. It
+// returns false when nothing is pushed.
+func pushSwitchBreak(b *builder, after *codegen.Block, label string) bool {
+	if b.golike {
+		b.breaks.push(after, label)
+		return true
+	}
+	if label == "" {
+		return false
+	}
+	b.breaks.pushLabelOnly(after, label)
+	return true
+}
+
+// buildCaseMatch checks if the switch value s equals to the case value
+// v. Strings are compared by their bytes.
+func buildCaseMatch(b *builder, s, v *ref) codegen.Ref {
+	ret := b.newCond()
+	if !types.SameType(s.Type(), types.String) {
+		b.b.Arith(ret, s.IR(), "==", v.IR())
+		return ret
+	}
+
+	addrS := b.newPtr()
+	addrV := b.newPtr()
+	b.b.Arith(addrS, nil, "&", s.IR())
+	b.b.Arith(addrV, nil, "&", v.IR())
+	b.b.Call([]codegen.Ref{ret}, b.rt.bytesEqual,
+		codegen.NewAddrRef(addrS, 4, 0, false, true),
+		codegen.NewAddrRef(addrS, 4, 4, false, true),
+		codegen.NewAddrRef(addrV, 4, 0, false, true),
+		codegen.NewAddrRef(addrV, 4, 4, false, true),
+	)
+	return ret
+}
+
 func buildSwitchStmt(b *builder, stmt *tast.SwitchStmt) {
 	type caseInfo struct {
 		expr *codegen.Block
@@ -13,6 +51,7 @@ func buildSwitchStmt(b *builder, stmt *tast.SwitchStmt) {
 		c    *tast.Case
 	}
 
+	label := b.labels.take()
 	s := buildExpr(b, stmt.Expr)
 	cases := make([]*caseInfo, len(stmt.Cases))
 
@@ -31,12 +70,15 @@ func buildSwitchStmt(b *builder, stmt *tast.SwitchStmt) {
 	}
 	def.Jump(after)
 
+	if pushSwitchBreak(b, after, label) {
+		defer b.breaks.pop()
+	}
+
 	for _, c := range cases {
 		b.b = c.expr
 		if c.c.Expr != nil {
-			ret := b.newTemp(types.Bool)
-			b.b.Arith(ret.IR(), s.IR(), "==", b.buildExpr(c.c.Expr).IR())
-			b.b.JumpIf(ret.IR(), c.body)
+			match := buildCaseMatch(b, s, b.buildExpr(c.c.Expr))
+			b.b.JumpIf(match, c.body)
 		} else {
 			b.b.Jump(c.body)
 		}
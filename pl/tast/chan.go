@@ -0,0 +1,41 @@
+package tast
+
+import (
+	"shanhu.io/smlvm/lexing"
+)
+
+// ChanRecv receives a value from a channel, like "<-ch". CommaOk is true
+// for a receive like "v, ok := <-ch", which has two values.
+type ChanRecv struct {
+	Chan    Expr
+	CommaOk bool
+	*Ref
+}
+
+// GoStmt is a statement like "go f(a, b)".
+type GoStmt struct {
+	Kw   *lexing.Token
+	Call *CallExpr
+}
+
+// SendStmt is a statement like "ch <- v".
+type SendStmt struct {
+	Chan Expr
+	Val  Expr
+}
+
+// SelectStmt is a select statement.
+type SelectStmt struct {
+	Cases []*SelectCase
+}
+
+// SelectCase is a case in a select statement. A case has either Send or
+// Recv, or neither for the default case. Comm is the statement that
+// assigns or defines with the received value, and is nil when the value
+// is dropped.
+type SelectCase struct {
+	Send  *SendStmt
+	Recv  *ChanRecv
+	Comm  Stmt
+	Stmts []Stmt
+}
@@ -98,10 +98,12 @@ type StarExpr struct {
 	*Ref
 }
 
-// CallExpr is an expression like "f(x)"
+// CallExpr is an expression like "f(x)". Dots is true when the last
+// argument is a slice followed by "...", like "append(s, t...)".
 type CallExpr struct {
 	Func Expr
 	Args Expr
+	Dots bool
 	*Ref
 }
 
@@ -113,6 +115,50 @@ type IndexExpr struct {
 	*Ref
 }
 
+// ArrayLit is an array or slice literal that is built at runtime.
+// Exprs[i] is the value of the element at index Keys[i]; Len is the
+// number of elements in the array.
+type ArrayLit struct {
+	Len   int32
+	Keys  []int32
+	Exprs []Expr
+	*Ref
+}
+
+// MapLit is a map literal. Vals[i] is the value of Keys[i].
+type MapLit struct {
+	Keys []Expr
+	Vals []Expr
+	*Ref
+}
+
+// MapIndex is an element in a map, like "m[k]". Assign is true when the
+// element is assigned to, where a missing key is added into the map.
+// CommaOk is true for a lookup like "v, ok := m[k]", which has two values.
+type MapIndex struct {
+	Map, Key Expr
+	Assign   bool
+	CommaOk  bool
+	*Ref
+}
+
+// TypeAssert is a type assertion like "v.(T)". CommaOk is true for an
+// assertion like "t, ok := v.(T)", which has two values.
+type TypeAssert struct {
+	Expr    Expr
+	T       types.T
+	CommaOk bool
+	*Ref
+}
+
+// StructLit is a struct literal. Exprs[i] is the value of Fields[i];
+// fields that are not listed are zero.
+type StructLit struct {
+	Fields []*types.Field
+	Exprs  []Expr
+	*Ref
+}
+
 // ExprList is a list of expressions.
 type ExprList struct {
 	Exprs []Expr
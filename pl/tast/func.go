@@ -16,6 +16,12 @@ type Func struct {
 	NamedRets []*syms.Symbol
 
 	Body []Stmt
+
+	HasDefer bool // has defer statements
+
+	// HeapVars are the variables of the function that are captured by
+	// escaping function literals, which are allocated in the heap.
+	HeapVars map[*syms.Symbol]bool
 }
 
 // IsMethod returns true when the function is a method.
@@ -23,6 +29,16 @@ func (f *Func) IsMethod() bool {
 	return !(f.This == nil && f.Receiver == nil)
 }
 
+// FuncLit is a function literal. Captures are the variables of the
+// enclosing functions that are used in the function body. The closure of
+// an escaping function literal is allocated in the heap.
+type FuncLit struct {
+	Func     *Func
+	Captures []*syms.Symbol
+	Escapes  bool
+	*Ref
+}
+
 // FuncAlias is a function alias.
 type FuncAlias struct {
 	Sym *syms.Symbol
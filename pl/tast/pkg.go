@@ -9,12 +9,13 @@ type Import struct {
 	Sym *syms.Symbol
 }
 
-// Pkg is a package of imports, consts, structs, vars and funcs.
+// Pkg is a package of imports, consts, types, vars and funcs.
 type Pkg struct {
 	Imports    []*syms.Symbol
 	Consts     []*syms.Symbol
 	Structs    []*syms.Symbol
 	Interfaces []*syms.Symbol
+	Named      []*syms.Symbol
 
 	Vars        []*Define
 	FuncAliases []*FuncAlias
@@ -2,6 +2,7 @@ package tast
 
 import (
 	"shanhu.io/smlvm/lexing"
+	"shanhu.io/smlvm/pl/types"
 	"shanhu.io/smlvm/syms"
 )
 
@@ -34,17 +35,38 @@ type IncStmt struct {
 	Op   *lexing.Token
 }
 
-// ContinueStmt is a "continue"
-type ContinueStmt struct{}
+// ContinueStmt is a "continue", with an optional label.
+type ContinueStmt struct {
+	Label string
+}
+
+// BreakStmt is a "break", with an optional label.
+type BreakStmt struct {
+	Label string
+}
+
+// GotoStmt is a "goto" that jumps to a label.
+type GotoStmt struct {
+	Label string
+}
 
-// BreakStmt is a "break"
-type BreakStmt struct{}
+// LabeledStmt is a statement with a label, like "L: for {}".
+type LabeledStmt struct {
+	Label string
+	Stmt  Stmt
+}
 
 // ReturnStmt is a statement like "return a,b"
 type ReturnStmt struct {
 	Exprs Expr
 }
 
+// DeferStmt is a statement like "defer f(a, b)".
+type DeferStmt struct {
+	Kw   *lexing.Token
+	Call *CallExpr
+}
+
 // IfStmt is an if statement.
 type IfStmt struct {
 	Expr Expr
@@ -65,6 +87,22 @@ type Case struct {
 	Fallthrough bool
 }
 
+// TypeSwitchStmt is a type switch statement on an interface value.
+type TypeSwitchStmt struct {
+	Expr  Expr
+	Cases []*TypeCase
+}
+
+// TypeCase is a case in a type switch. A nil in Types matches a nil
+// interface. Sym is the variable bound in the case, and is nil when the
+// switch does not bind one.
+type TypeCase struct {
+	Default bool
+	Types   []types.T
+	Sym     *syms.Symbol
+	Stmts   []Stmt
+}
+
 // ForStmt is a for loop statement.
 type ForStmt struct {
 	ThreeFold bool
@@ -73,3 +111,13 @@ type ForStmt struct {
 	Iter      Stmt
 	Body      Stmt
 }
+
+// RangeStmt is a for loop over the entries of X. Key and Value receive
+// the key and the value of each entry, and are nil when omitted. Define
+// declares the variables when the range clause uses ":=".
+type RangeStmt struct {
+	Define     *Define
+	Key, Value Expr
+	X          Expr
+	Body       Stmt
+}
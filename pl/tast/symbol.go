@@ -15,6 +15,8 @@ const (
 	SymImport
 	SymField
 	SymInterface
+	SymNamed
+	SymGeneric
 )
 
 // SymStr returns the string representation of a symbol
@@ -36,6 +38,10 @@ func SymStr(s int) string {
 		return "builtin type"
 	case SymInterface:
 		return "interface"
+	case SymNamed:
+		return "named type"
+	case SymGeneric:
+		return "generic"
 	default:
 		panic(fmt.Errorf("unknown symbol: %d", s))
 	}
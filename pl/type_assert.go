@@ -0,0 +1,131 @@
+package pl
+
+import (
+	"shanhu.io/smlvm/arch"
+	"shanhu.io/smlvm/pl/codegen"
+	"shanhu.io/smlvm/pl/tast"
+	"shanhu.io/smlvm/pl/types"
+)
+
+// loadIface loads the receiver and the struct descriptor of an interface
+// value. The descriptor is 0 when the interface is nil.
+func loadIface(b *builder, x *ref) (recv, desc codegen.Ref) {
+	addr := b.newPtr()
+	b.b.Arith(addr, nil, "&", x.IR())
+	recv = b.newPtr()
+	b.b.Assign(recv, codegen.NewAddrRef(addr, arch.RegSize, 0, false, true))
+	tab := b.newPtr()
+	b.b.Assign(tab, codegen.NewAddrRef(
+		addr, arch.RegSize, arch.RegSize, false, true,
+	))
+
+	desc = b.newPtr()
+	b.b.Zero(desc)
+	isNil := b.newCond()
+	b.b.Arith(isNil, nil, "?", tab)
+	b.b.Arith(isNil, nil, "!", isNil)
+	body := b.f.NewBlock(b.b)
+	after := b.f.NewBlock(body)
+	b.b.JumpIf(isNil, after)
+	b.b = body
+	b.b.Assign(desc, codegen.NewAddrRef(tab, arch.RegSize, 0, false, true))
+	b.b = after
+	return recv, desc
+}
+
+// isType checks if the struct descriptor desc is the one of t. A nil t
+// checks if the interface is nil.
+func isType(b *builder, desc codegen.Ref, t types.T) codegen.Ref {
+	ret := b.newCond()
+	if t == nil {
+		b.b.Arith(ret, nil, "?", desc)
+		b.b.Arith(ret, nil, "!", ret)
+		return ret
+	}
+	want := b.newPtr()
+	s := types.PointerOf(t).(*types.Struct)
+	b.b.Arith(want, nil, "&", b.structDesc(s))
+	b.b.Arith(ret, desc, "==", want)
+	return ret
+}
+
+func buildTypeAssert(b *builder, expr *tast.TypeAssert) *ref {
+	x := b.buildExpr(expr.Expr)
+	recv, desc := loadIface(b, x)
+	ok := isType(b, desc, expr.T)
+
+	ret := b.newTemp(expr.T)
+	if !expr.CommaOk {
+		fail := b.f.NewBlock(b.b)
+		after := b.f.NewBlock(fail)
+		b.b.JumpIf(ok, after)
+		b.b = fail
+		callPanic(b, "type assertion failed")
+		b.b = after
+		b.b.Assign(ret.IR(), recv)
+		return ret
+	}
+
+	b.b.Zero(ret.IR())
+	body := b.f.NewBlock(b.b)
+	after := b.f.NewBlock(body)
+	b.b.JumpIfNot(ok, after)
+	b.b = body
+	b.b.Assign(ret.IR(), recv)
+	b.b = after
+	return appendRef(ret, newRef(types.Bool, ok))
+}
+
+func buildTypeSwitchStmt(b *builder, stmt *tast.TypeSwitchStmt) {
+	label := b.labels.take()
+	x := b.buildExpr(stmt.Expr)
+	v := b.newTemp(x.Type())
+	b.b.Assign(v.IR(), x.IR())
+	recv, desc := loadIface(b, v)
+
+	after := b.f.NewBlock(b.b)
+	if pushSwitchBreak(b, after, label) {
+		defer b.breaks.pop()
+	}
+
+	var def *tast.TypeCase
+	for _, c := range stmt.Cases {
+		if c.Default {
+			def = c
+			continue
+		}
+		match := isType(b, desc, c.Types[0])
+		for _, t := range c.Types[1:] {
+			b.b.Arith(match, match, "|", isType(b, desc, t))
+		}
+		body := b.f.NewBlock(b.b)
+		next := b.f.NewBlock(body)
+		b.b.JumpIfNot(match, next)
+		b.b = body
+		buildTypeCase(b, c, v, recv)
+		b.b.Jump(after)
+		b.b = next
+	}
+	if def != nil {
+		buildTypeCase(b, def, v, recv)
+	}
+	b.b.Jump(after)
+	b.b = after
+}
+
+func buildTypeCase(b *builder, c *tast.TypeCase, v *ref, recv codegen.Ref) {
+	if c.Sym != nil {
+		name := c.Sym.Name()
+		t := c.Sym.ObjType.(types.T)
+		r := b.newVar(c.Sym, t)
+		c.Sym.Obj = &objVar{name: name, ref: r}
+		if _, ok := types.Underlying(t).(*types.Interface); ok {
+			b.b.Assign(r.IR(), v.IR())
+		} else {
+			b.b.Assign(r.IR(), recv)
+		}
+	}
+	for _, s := range c.Stmts {
+		b.buildStmt(s)
+	}
+}
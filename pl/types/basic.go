@@ -19,7 +19,7 @@ const (
 
 // IsBasic checks if a type is a particular basic type
 func IsBasic(t T, b Basic) bool {
-	code, ok := t.(Basic)
+	code, ok := Underlying(t).(Basic)
 	if !ok {
 		return false
 	}
@@ -28,7 +28,7 @@ func IsBasic(t T, b Basic) bool {
 
 // IsRegSizeBasic checks if a type is a Int, Uint or Float32
 func IsRegSizeBasic(t T) bool {
-	code, ok := t.(Basic)
+	code, ok := Underlying(t).(Basic)
 	if !ok {
 		return false
 	}
@@ -41,7 +41,7 @@ func IsRegSizeBasic(t T) bool {
 
 // IsInteger checks if a type is an integer type
 func IsInteger(t T) bool {
-	code, ok := t.(Basic)
+	code, ok := Underlying(t).(Basic)
 	if !ok {
 		return false
 	}
@@ -52,9 +52,14 @@ func IsInteger(t T) bool {
 	return false
 }
 
+// IsNumeric checks if a type is an integer or a float type
+func IsNumeric(t T) bool {
+	return IsInteger(t) || IsBasic(t, Float32)
+}
+
 // IsSigned checks if a type is a signed integer type
 func IsSigned(t T) bool {
-	code, ok := t.(Basic)
+	code, ok := Underlying(t).(Basic)
 	if !ok {
 		return false
 	}
@@ -67,7 +72,7 @@ func IsSigned(t T) bool {
 
 // IsUnsigned checks if a type is an unsigned integer type
 func IsUnsigned(t T) bool {
-	code, ok := t.(Basic)
+	code, ok := Underlying(t).(Basic)
 	if !ok {
 		return false
 	}
@@ -80,7 +85,7 @@ func IsUnsigned(t T) bool {
 
 // IsByte checks if a type is Uint8
 func IsByte(t T) bool {
-	code, ok := t.(Basic)
+	code, ok := Underlying(t).(Basic)
 	if !ok {
 		return false
 	}
@@ -95,11 +100,11 @@ func BothBasic(a, b T, t Basic) bool {
 // SameBasic check if two types are both basic types, and also returns
 // the type if it is.
 func SameBasic(a, b T) (bool, Basic) {
-	code1, ok := a.(Basic)
+	code1, ok := Underlying(a).(Basic)
 	if !ok {
 		return false, Int
 	}
-	code2, ok := b.(Basic)
+	code2, ok := Underlying(b).(Basic)
 	if !ok {
 		return false, Int
 	}
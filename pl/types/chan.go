@@ -0,0 +1,36 @@
+package types
+
+import (
+	"shanhu.io/smlvm/arch"
+)
+
+// Chan is a channel type. A channel value is a pointer to the channel's
+// data, which is maintained by the runtime.
+type Chan struct {
+	T T
+}
+
+// String returns "chan T"
+func (t *Chan) String() string { return "chan " + t.T.String() }
+
+// Size returns the size of a pointer.
+func (t *Chan) Size() int32 { return arch.RegSize }
+
+// RegSizeAlign returns true. A channel is always word aligned.
+func (t *Chan) RegSizeAlign() bool { return true }
+
+// BothChan checks if the two types are the same channel types.
+// If one is nil, but the other one is a channel, it returns true.
+// Otherwise it returns false.
+func BothChan(t1, t2 T) bool {
+	_, ok1 := Underlying(t1).(*Chan)
+	_, ok2 := Underlying(t2).(*Chan)
+	if IsNil(t1) && ok2 {
+		return true
+	} else if IsNil(t2) && ok1 {
+		return true
+	} else if !ok1 || !ok2 {
+		return false
+	}
+	return SameType(t1, t2)
+}
@@ -19,6 +19,17 @@ func NewConstInt(v int64, t T) (*Const, error) {
 	return &Const{Value: v, Type: t}, nil
 }
 
+// NewConstFloat creates a new float32 constant. The value is rounded to
+// float32 precision.
+func NewConstFloat(v float64) *Const {
+	return &Const{Value: float64(float32(v)), Type: Float32}
+}
+
+// NewConstString creates a new string constant.
+func NewConstString(v string) *Const {
+	return &Const{Value: v, Type: String}
+}
+
 // NewConstBool creates a new bool constant.
 func NewConstBool(v bool) *Const {
 	return &Const{Value: v, Type: Bool}
@@ -29,6 +40,11 @@ func NewNumber(v int64) *Const {
 	return &Const{Value: v, Type: Number{}}
 }
 
+// NewChar creates a new constant number from a char literal.
+func NewChar(v int64) *Const {
+	return &Const{Value: v, Type: Number{Char: true}}
+}
+
 // Size returns the type of the size.
 func (c *Const) Size() int32 { return c.Type.Size() }
 
@@ -45,6 +61,8 @@ func (c *Const) String() string {
 		return fmt.Sprintf("const %s %d", c.Type.(Basic), v)
 	case bool:
 		return fmt.Sprintf("const %s %t", c.Type.(Basic), v)
+	case float64:
+		return fmt.Sprintf("const %s %g", c.Type.(Basic), v)
 	case string:
 		return fmt.Sprintf("%q", v)
 	default:
@@ -71,9 +89,30 @@ func NumConst(t T) (int64, bool) {
 	return c.Value.(int64), true
 }
 
-// InRange checks if a const is in range of an integer type.
+// FloatConst checks and transforms a type to a float constant value.
+func FloatConst(t T) (float64, bool) {
+	c, ok := t.(*Const)
+	if !ok {
+		return 0, false
+	}
+	v, ok := c.Value.(float64)
+	return v, ok
+}
+
+// StringConst checks and transforms a type to a string constant value.
+func StringConst(t T) (string, bool) {
+	c, ok := t.(*Const)
+	if !ok {
+		return "", false
+	}
+	v, ok := c.Value.(string)
+	return v, ok
+}
+
+// InRange checks if a const is in range of an integer type, or if it
+// can be converted to a float.
 func InRange(v int64, t T) bool {
-	t, ok := t.(Basic)
+	t, ok := Underlying(t).(Basic)
 	if !ok {
 		return false
 	}
@@ -86,6 +125,21 @@ func InRange(v int64, t T) bool {
 		return v >= math.MinInt8 && v <= math.MaxInt8
 	case Uint8:
 		return v >= 0 && v <= math.MaxUint8
+	case Float32:
+		return v >= math.MinInt32 && v <= math.MaxUint32
 	}
 	return false
 }
+
+// FloatInRange checks if a float const can be converted to a numeric type.
+// It converts to an integer type only when the value is integral and in
+// range.
+func FloatInRange(v float64, t T) bool {
+	if IsBasic(t, Float32) {
+		return true
+	}
+	if v != math.Floor(v) || v < math.MinInt32 || v > math.MaxUint32 {
+		return false
+	}
+	return InRange(int64(v), t)
+}
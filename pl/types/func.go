@@ -3,9 +3,9 @@ package types
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"shanhu.io/smlvm/arch"
-	"shanhu.io/smlvm/fmtutil"
 )
 
 // Arg is a function argument or return value
@@ -36,6 +36,10 @@ type Func struct {
 
 	// If the function pointer has a this pointer bond to it.
 	IsBond bool
+
+	// If the last argument is variadic. The type of the last argument
+	// is a slice of the element type.
+	IsVariadic bool
 }
 
 // NewFunc creates a new function type
@@ -57,6 +61,14 @@ func NewFunc(this *Arg, args []*Arg, rets []*Arg) *Func {
 	return ret
 }
 
+// SetVariadic marks the last argument of the function as variadic.
+func (t *Func) SetVariadic() {
+	t.IsVariadic = true
+	if t.MethodFunc != nil {
+		t.MethodFunc.IsVariadic = true
+	}
+}
+
 // ArgTypes returns the types of the args.
 func ArgTypes(args []*Arg) []T {
 	var ret []T
@@ -92,7 +104,19 @@ var VoidFunc = NewVoidFunc()
 func (t *Func) String() string {
 	// TODO: this is kind of ugly, need some refactoring
 	buf := new(bytes.Buffer)
-	fmt.Fprintf(buf, "func (%s)", fmtutil.Join(t.Args, ","))
+	args := make([]string, len(t.Args))
+	for i, arg := range t.Args {
+		args[i] = arg.String()
+	}
+	if t.IsVariadic {
+		last := t.Args[len(t.Args)-1]
+		v := "..." + last.T.(*Slice).T.String()
+		if last.Name != "" {
+			v = last.Name + " " + v
+		}
+		args[len(args)-1] = v
+	}
+	fmt.Fprintf(buf, "func (%s)", strings.Join(args, ","))
 	if len(t.Rets) > 1 {
 		fmt.Fprintf(buf, " (")
 		for i, ret := range t.Rets {
@@ -124,7 +148,7 @@ func (t *Func) RegSizeAlign() bool { return true }
 // IsFuncPointer checks if the function is a simple function pointer
 // that does not have a bond this pointer.
 func IsFuncPointer(t T) bool {
-	ft, ok := t.(*Func)
+	ft, ok := Underlying(t).(*Func)
 	if !ok {
 		return false
 	}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"fmt"
+
+	"shanhu.io/smlvm/arch"
+)
+
+// Map is a map type. A map value is a pointer to the map's data, which is
+// maintained by the runtime.
+type Map struct {
+	Key T
+	Val T
+}
+
+// String returns "map[K]V"
+func (t *Map) String() string {
+	return fmt.Sprintf("map[%s]%s", t.Key, t.Val)
+}
+
+// Size returns the size of a pointer.
+func (t *Map) Size() int32 { return arch.RegSize }
+
+// RegSizeAlign returns true. A map is always word aligned.
+func (t *Map) RegSizeAlign() bool { return true }
+
+// IsMapKey checks if a type can be used as the key type of a map. Map keys
+// can be integers, pointers or strings.
+func IsMapKey(t T) bool {
+	return IsInteger(t) || IsPointer(t) || IsBasic(t, Bool) ||
+		SameType(t, String)
+}
+
+// BothMap checks if the two types are the same map types.
+// If one is nil, but the other one is a map, it returns true.
+// Otherwise it returns false.
+func BothMap(t1, t2 T) bool {
+	_, ok1 := Underlying(t1).(*Map)
+	_, ok2 := Underlying(t2).(*Map)
+	if IsNil(t1) && ok2 {
+		return true
+	} else if IsNil(t2) && ok1 {
+		return true
+	} else if !ok1 || !ok2 {
+		return false
+	}
+	return SameType(t1, t2)
+}
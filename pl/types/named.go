@@ -0,0 +1,51 @@
+package types
+
+import (
+	"shanhu.io/smlvm/syms"
+)
+
+// Named is a type declared with a name over an underlying type, like
+// "type Celsius int". A named type is different from any other type,
+// and can have methods.
+type Named struct {
+	Syms *syms.Table // methods
+	Pkg  string      // path of the package that declares the type
+
+	// T is the underlying type, which is never a named type.
+	T T
+
+	name string
+}
+
+// NewNamed constructs a new named type. The underlying type is set
+// after all the types of the package are declared.
+func NewNamed(name string) *Named {
+	return &Named{
+		Syms: syms.NewTable(),
+		name: name,
+	}
+}
+
+// Size returns the size of the underlying type.
+func (t *Named) Size() int32 { return t.T.Size() }
+
+// String returns the name of the type.
+func (t *Named) String() string { return t.name }
+
+// RegSizeAlign inherits from the underlying type.
+func (t *Named) RegSizeAlign() bool { return t.T.RegSizeAlign() }
+
+// Underlying returns the underlying type of a named type. For other
+// types, it returns the type itself.
+func Underlying(t T) T {
+	if n, ok := t.(*Named); ok {
+		return n.T
+	}
+	return t
+}
+
+// IsNamed checks if a type is a named type.
+func IsNamed(t T) bool {
+	_, ok := t.(*Named)
+	return ok
+}
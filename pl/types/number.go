@@ -1,7 +1,9 @@
 package types
 
 // Number is a typeless constant number.
-type Number struct{}
+type Number struct {
+	Char bool // a char literal, which defines int8 variables
+}
 
 // Size on Number will panic.
 func (n Number) Size() int32 { panic("size of typeless number") }
@@ -9,5 +11,19 @@ func (n Number) Size() int32 { panic("size of typeless number") }
 // RegSizeAlign on Number will panic.
 func (n Number) RegSizeAlign() bool { panic("alignment on constant") }
 
-// String returns "<number>"
-func (n Number) String() string { return "<number>" }
+// String returns "<number>", or "<char>" for chars.
+func (n Number) String() string {
+	if n.Char {
+		return "<char>"
+	}
+	return "<number>"
+}
+
+// DefaultType returns the type of a variable that is defined with a
+// typeless number: int8 for chars, and int for other numbers.
+func DefaultType(n Number) T {
+	if n.Char {
+		return Int8
+	}
+	return Int
+}
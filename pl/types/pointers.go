@@ -31,7 +31,7 @@ func (t *Pointer) RegSizeAlign() bool { return true }
 // PointerOf returns the internal type of the pointer type.
 // If the type is not a pointer, it returns nil.
 func PointerOf(t T) T {
-	pt, ok := t.(*Pointer)
+	pt, ok := Underlying(t).(*Pointer)
 	if !ok {
 		return nil
 	}
@@ -40,6 +40,6 @@ func PointerOf(t T) T {
 
 // IsPointer checks if the type is a pointer.
 func IsPointer(t T) bool {
-	_, ok := t.(*Pointer)
+	_, ok := Underlying(t).(*Pointer)
 	return ok
 }
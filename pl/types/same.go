@@ -10,6 +10,10 @@ func CanAssign(left, right T) (canAssign bool, needCast bool) {
 			ret := InRange(c.Value.(int64), left)
 			return ret, ret
 		}
+		if v, ok := c.Value.(float64); ok && IsNumeric(left) {
+			ret := FloatInRange(v, left)
+			return ret, ret
+		}
 		right = c.Type
 	}
 
@@ -31,11 +35,15 @@ func CanAssign(left, right T) (canAssign bool, needCast bool) {
 	}
 
 	if IsNil(right) {
-		switch left := left.(type) {
+		switch left := Underlying(left).(type) {
 		case *Pointer:
 			return true, true
 		case *Slice:
 			return true, true
+		case *Map:
+			return true, true
+		case *Chan:
+			return true, true
 		case *Func:
 			if left.IsBond {
 				return false, false
@@ -47,7 +55,25 @@ func CanAssign(left, right T) (canAssign bool, needCast bool) {
 		return false, false
 	}
 
-	return SameType(left, right), false
+	if SameType(left, right) {
+		return true, false
+	}
+	return sameUnderlying(left, right), false
+}
+
+// sameUnderlying checks if a value of a named type can be assigned
+// with a value of an unnamed type literal, or the other way around.
+// The two types must have the same underlying type.
+func sameUnderlying(left, right T) bool {
+	if IsNamed(left) == IsNamed(right) {
+		return false
+	}
+	for _, t := range []T{left, right} {
+		if _, ok := t.(Basic); ok {
+			return false
+		}
+	}
+	return SameType(Underlying(left), Underlying(right))
 }
 
 // SameType checks if two types are of the same type
@@ -80,6 +106,16 @@ func SameType(t1, t2 T) bool {
 			return t1.N == t2.N && SameType(t1.T, t2.T)
 		}
 		return false
+	case *Map:
+		if t2, ok := t2.(*Map); ok {
+			return SameType(t1.Key, t2.Key) && SameType(t1.Val, t2.Val)
+		}
+		return false
+	case *Chan:
+		if t2, ok := t2.(*Chan); ok {
+			return SameType(t1.T, t2.T)
+		}
+		return false
 	case *Func:
 		t2, ok := t2.(*Func)
 		if !ok {
@@ -91,6 +127,9 @@ func SameType(t1, t2 T) bool {
 		if len(t1.Rets) != len(t2.Rets) {
 			return false
 		}
+		if t1.IsVariadic != t2.IsVariadic {
+			return false
+		}
 
 		for i, t := range t1.Args {
 			if !SameType(t.T, t2.Args[i].T) {
@@ -107,7 +146,7 @@ func SameType(t1, t2 T) bool {
 		return true
 	case *Struct:
 		if t2, ok := t2.(*Struct); ok {
-			return t1 == t2
+			return t1 == t2 || sameInstance(t1, t2)
 		}
 		return false
 	case *Interface:
@@ -115,6 +154,8 @@ func SameType(t1, t2 T) bool {
 			return t1 == t2
 		}
 		return false
+	case *Named:
+		return false
 	default:
 		panic(fmt.Errorf("invalid type: %T", t1))
 	}
@@ -174,6 +215,23 @@ func BothSlice(t1, t2 T) bool {
 	return SameType(p1, p2)
 }
 
+// BothInterface checks if the two types are comparable interfaces.
+// If they are the same interface type, it returns true.
+// If one is nil, but the other one is an interface, it returns true.
+// Otherwise it returns false.
+func BothInterface(t1, t2 T) bool {
+	_, ok1 := Underlying(t1).(*Interface)
+	_, ok2 := Underlying(t2).(*Interface)
+	if IsNil(t1) && ok2 {
+		return true
+	} else if IsNil(t2) && ok1 {
+		return true
+	} else if !ok1 || !ok2 {
+		return false
+	}
+	return SameType(t1, t2)
+}
+
 // CastConst checks if a const can be used to define a const with type T.
 // and return the Const type
 func CastConst(ct *Const, t T) T {
@@ -187,5 +245,15 @@ func CastConst(ct *Const, t T) T {
 			return ret
 		}
 	}
+	if IsBasic(t, Float32) && ok {
+		return NewConstFloat(float64(ct.Value.(int64)))
+	}
+	if v, ok := ct.Value.(float64); ok && IsNumeric(t) && FloatInRange(v, t) {
+		if IsInteger(t) {
+			ret, _ := NewConstInt(int64(v), t)
+			return ret
+		}
+		return &Const{Value: v, Type: t}
+	}
 	return nil
 }
@@ -24,7 +24,7 @@ func (t *Slice) Size() int32 { return arch.RegSize * 2 }
 // SliceOf returns the internal type of the slice.
 // If the type is not a slice, it returns nil.
 func SliceOf(t T) T {
-	st, ok := t.(*Slice)
+	st, ok := Underlying(t).(*Slice)
 	if !ok {
 		return nil
 	}
@@ -4,10 +4,12 @@ import (
 	"shanhu.io/smlvm/syms"
 )
 
-// Field is a named field in a struct
+// Field is a named field in a struct. An embedded field is named
+// after its type, and its members are promoted to the struct.
 type Field struct {
 	Name string
 	T
+	Embedded bool
 
 	offset int32
 }
@@ -19,7 +21,20 @@ func (f *Field) Offset() int32 { return f.offset }
 
 // Struct is the type of a structure
 type Struct struct {
-	Syms *syms.Table
+	Syms   *syms.Table
+	Fields []*Field // fields in declaration order
+	Pkg    string   // path of the package that declares the struct
+
+	// Promoted saves the methods generated for the methods promoted
+	// from the embedded fields, so that the struct can implement
+	// interfaces with them.
+	Promoted *syms.Table
+
+	// Generic and TypeArgs identify the instance of a generic struct.
+	// The instances of a generic with the same type arguments are the
+	// same type, even when they are built by different packages.
+	Generic  interface{}
+	TypeArgs []T
 
 	name         string
 	size         int32
@@ -31,6 +46,7 @@ func NewStruct(name string) *Struct {
 	ret := new(Struct)
 	ret.name = name
 	ret.Syms = syms.NewTable()
+	ret.Promoted = syms.NewTable()
 
 	return ret
 }
@@ -45,6 +61,7 @@ func (t *Struct) AddField(f *Field) {
 	}
 	f.offset = t.size
 	t.size += fsize
+	t.Fields = append(t.Fields, f)
 }
 
 // Size returns the overall size of the structure type
@@ -56,3 +73,23 @@ func (t *Struct) String() string { return t.name }
 // RegSizeAlign returns true when at least one field in the struct
 // is word aligned.
 func (t *Struct) RegSizeAlign() bool { return t.regSizeAlign }
+
+// SameTypes checks if two lists of types are the same.
+func SameTypes(ts1, ts2 []T) bool {
+	if len(ts1) != len(ts2) {
+		return false
+	}
+	for i, t := range ts1 {
+		if !SameType(t, ts2[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameInstance(t1, t2 *Struct) bool {
+	if t1.Generic == nil || t1.Generic != t2.Generic {
+		return false
+	}
+	return SameTypes(t1.TypeArgs, t2.TypeArgs)
+}
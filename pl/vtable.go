@@ -1,15 +1,20 @@
 package pl
 
 import (
+	"shanhu.io/smlvm/arch"
+	"shanhu.io/smlvm/pl/codegen"
 	"shanhu.io/smlvm/pl/types"
 	"shanhu.io/smlvm/syms"
 )
 
-// vTable is the virtual table to implement the interface
+// vTable is the virtual table to implement the interface. An interface
+// value is two words: the receiver pointer, and the pointer to the
+// vtable of the receiver's struct or named type. A vtable starts with
+// the descriptor of the type, followed by the method functions in
+// the order of the methods declared in the interface.
 type vTable struct {
-	funcs []string
-	// will change it to *ref
-	implementMap map[*types.Struct][]*syms.Symbol
+	funcs        []string
+	implementMap map[types.T]codegen.Ref
 }
 
 func newTable(i *types.Interface) *vTable {
@@ -20,6 +25,85 @@ func newTable(i *types.Interface) *vTable {
 	}
 	return &vTable{
 		funcs:        m,
-		implementMap: make(map[*types.Struct][]*syms.Symbol),
+		implementMap: make(map[types.T]codegen.Ref),
 	}
 }
+
+// methodIndex returns the index of the method in the vtable.
+func (t *vTable) methodIndex(name string) int {
+	for i, f := range t.funcs {
+		if f == name {
+			return i + 1 // after the type descriptor
+		}
+	}
+	panic("method not in interface")
+}
+
+func (b *builder) vTable(i *types.Interface) *vTable {
+	t := b.vTableMap[i]
+	if t == nil {
+		t = newTable(i)
+		b.vTableMap[i] = t
+	}
+	return t
+}
+
+// newImplement returns the vtable that struct or named type t
+// implements interface i.
+func (b *builder) newImplement(i *types.Interface, t types.T) codegen.Ref {
+	tab := b.vTable(i)
+	if ret := tab.implementMap[t]; ret != nil {
+		return ret
+	}
+
+	funcs := []codegen.Ref{b.structDesc(t)}
+	for _, name := range tab.funcs {
+		sym := methodSym(t, name)
+		funcs = append(funcs, sym.Obj.(*objFunc).IR())
+	}
+	ret := b.p.NewVTable(funcs)
+	tab.implementMap[t] = ret
+	return ret
+}
+
+// methodSym looks up the method of a struct or named type, including
+// the methods that a struct promotes from its embedded fields.
+func methodSym(t types.T, name string) *syms.Symbol {
+	switch t := t.(type) {
+	case *types.Struct:
+		if sym := t.Syms.Query(name); sym != nil {
+			return sym
+		}
+		return t.Promoted.Query(name)
+	case *types.Named:
+		return t.Syms.Query(name)
+	}
+	panic("not a struct or named type")
+}
+
+// typePkg returns the package that declares struct or named type t.
+func typePkg(t types.T) string {
+	switch t := t.(type) {
+	case *types.Struct:
+		return t.Pkg
+	case *types.Named:
+		return t.Pkg
+	}
+	panic("not a struct or named type")
+}
+
+// structDesc returns the descriptor of struct or named type t, which
+// might be declared in another package.
+func (b *builder) structDesc(t types.T) codegen.Ref {
+	if ret := b.structDescs[t]; ret != nil {
+		return ret
+	}
+	pkg := typePkg(t)
+	if pkg == b.path {
+		panic("type descriptor missing")
+	}
+	name := codegen.StructDescName(t.String())
+	ret := codegen.NewHeapSym(pkg, name, 2*arch.RegSize, false, true)
+	b.structDescs[t] = ret
+	return ret
+}
@@ -65,3 +65,11 @@ func (s *Scope) Query(n string) *Symbol {
 func (s *Scope) Declare(sym *Symbol) *Symbol {
 	return s.top.Declare(sym)
 }
+
+// Copy returns a new scope that has the same symbol tables on the stack.
+// Pushing or popping tables on the copy does not change the original.
+func (s *Scope) Copy() *Scope {
+	stack := make([]*Table, len(s.stack))
+	copy(stack, s.stack)
+	return &Scope{stack: stack, top: s.top}
+}